@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package devops
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrConfigVersionNotFound is returned by a ConfigHistoryStore if the version of a job does not exist
+var ErrConfigVersionNotFound = errors.New("config version not found")
+
+// ConfigVersion is a config.xml of a job recorded before it was replaced
+type ConfigVersion struct {
+	Version   int       `json:"version" description:"Version of the config, it starts from 1 for each job"`
+	Author    string    `json:"author,omitempty" description:"User who replaced the config"`
+	Timestamp time.Time `json:"timestamp" description:"Time when the config was replaced"`
+	Config    string    `json:"config,omitempty" description:"Content of the config.xml"`
+}
+
+// ConfigHistoryStore keeps the previous configs of jobs, the jobs are identified by their full names, such as project/pipeline
+type ConfigHistoryStore interface {
+	// Add records a config of a job, the version is assigned by the store
+	Add(job string, version *ConfigVersion) (*ConfigVersion, error)
+	// List returns all versions of a job ordered by the version
+	List(job string) ([]*ConfigVersion, error)
+	// Get returns a version of a job, ErrConfigVersionNotFound is returned if it does not exist
+	Get(job string, version int) (*ConfigVersion, error)
+	// Move moves the versions of a job to its new name after the job is moved or renamed,
+	// the versions left by a previous job with the new name are dropped
+	Move(job, target string) error
+}
+
+// ConfigHistoryOperator provides APIs for the config history of pipelines, the history is recorded only if a
+// ConfigHistoryStore is set to the client
+type ConfigHistoryOperator interface {
+	// ListProjectPipelineConfigHistory returns the versions of a pipeline without the content of the configs
+	ListProjectPipelineConfigHistory(projectId, pipelineId string) ([]*ConfigVersion, error)
+
+	// GetProjectPipelineConfigVersion returns a version of a pipeline with the content of the config
+	GetProjectPipelineConfigVersion(projectId, pipelineId string, version int) (*ConfigVersion, error)
+
+	// DiffProjectPipelineConfigVersions returns the unified diff between two versions of a pipeline,
+	// the version 0 stands for the current config in Jenkins
+	DiffProjectPipelineConfigVersions(projectId, pipelineId string, from, to int) (string, error)
+
+	// RollbackProjectPipeline replaces the config of a pipeline with a version, the current config is recorded as well
+	RollbackProjectPipeline(projectId, pipelineId string, version int) error
+}
@@ -60,6 +60,47 @@ type KubeconfigCredential struct {
 	Content string `json:"content,omitempty" description:"content of kubeconfig"`
 }
 
+// CredentialDomain groups credentials of a project by the services they apply to
+type CredentialDomain struct {
+	Name        string                 `json:"name" description:"Name of the credential domain, '_' is the default global domain"`
+	Description string                 `json:"description,omitempty" description:"Description of the credential domain"`
+	Hostname    *HostnameSpecification `json:"hostname,omitempty" description:"Hostnames the credential domain applies to"`
+	Scheme      *SchemeSpecification   `json:"scheme,omitempty" description:"URI schemes the credential domain applies to"`
+}
+
+type HostnameSpecification struct {
+	Includes string `json:"includes,omitempty" description:"Comma separated hostnames to include, wildcards are supported, e.g. *.example.com"`
+	Excludes string `json:"excludes,omitempty" description:"Comma separated hostnames to exclude, wildcards are supported"`
+}
+
+type SchemeSpecification struct {
+	Schemes string `json:"schemes,omitempty" description:"Comma separated URI schemes, e.g. https,ssh"`
+}
+
+// CredentialUsage describes which pipelines reference a credential and which builds used it
+type CredentialUsage struct {
+	Id         string                 `json:"id" description:"Id of Credential"`
+	References []CredentialReference  `json:"references,omitempty" description:"Pipelines which reference the credential in their configuration"`
+	Builds     []CredentialBuildUsage `json:"builds,omitempty" description:"Jobs which used the credential, recorded by the Jenkins fingerprint"`
+	// the usage is incomplete if there are unreadable pipelines
+	UnreadablePipelines []string `json:"unreadablePipelines,omitempty" description:"Pipelines whose configuration could not be read, they might reference the credential as well"`
+}
+
+type CredentialReference struct {
+	Pipeline string `json:"pipeline" description:"Name of the pipeline"`
+	Source   string `json:"source" description:"Where the credential is referenced, e.g. jenkinsfile, git_source"`
+}
+
+type CredentialBuildUsage struct {
+	Name      string `json:"name" description:"Full name of the job, e.g. project/pipeline/branch"`
+	LastBuild int    `json:"last_build" description:"The last build number which used the credential"`
+}
+
+// InUse tells whether the credential is referenced or was used by any build
+func (u *CredentialUsage) InUse() bool {
+	return u != nil && (len(u.References) > 0 || len(u.Builds) > 0)
+}
+
 type CredentialOperator interface {
 	CreateCredentialInProject(projectId string, credential *v1.Secret) (string, error)
 
@@ -67,5 +108,30 @@ type CredentialOperator interface {
 
 	GetCredentialInProject(projectId, id string) (*Credential, error)
 
-	DeleteCredentialInProject(projectId, id string) (string, error)
+	// DeleteCredentialInProject deletes a credential, it refuses to delete a credential which is referenced by
+	// pipelines or used by builds unless force is true
+	DeleteCredentialInProject(projectId, id string, force bool) (string, error)
+
+	ListCredentialsInProject(projectId string) ([]*Credential, error)
+}
+
+type CredentialDomainOperator interface {
+	CreateCredentialDomainInProject(projectId string, domain *CredentialDomain) (string, error)
+
+	UpdateCredentialDomainInProject(projectId string, domain *CredentialDomain) (string, error)
+
+	GetCredentialDomainInProject(projectId, name string) (*CredentialDomain, error)
+
+	DeleteCredentialDomainInProject(projectId, name string) (string, error)
+
+	ListCredentialDomainsInProject(projectId string) ([]*CredentialDomain, error)
+}
+
+type CredentialUsageOperator interface {
+	// GetCredentialUsageInProject returns the usage of all credentials referenced or used in a project, keyed by credential ID
+	GetCredentialUsageInProject(projectId string) (map[string]*CredentialUsage, error)
+
+	// CheckCredentialUnusedInProject returns a conflict error if the credential is referenced by pipelines or used by builds,
+	// the pipelines which could not be read are not checked
+	CheckCredentialUnusedInProject(projectId, id string) error
 }
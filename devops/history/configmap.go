@@ -0,0 +1,240 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/opswave/go-jenkins/devops"
+)
+
+// ConfigMapJobAnnotation records the full name of the job whose history is kept in a ConfigMap
+const ConfigMapJobAnnotation = "devops.kubesphere.io/config-history-job"
+
+// maxConfigMapDataSize is the size of the versions kept in a ConfigMap, it leaves room for the metadata
+// under the 1MiB limit of a ConfigMap
+const maxConfigMapDataSize = 1000 * 1024
+
+// ConfigMapClient is the subset of a namespaced ConfigMap client used by the ConfigMap store,
+// the ConfigMapInterface of client-go satisfies it
+type ConfigMapClient interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ConfigMap, error)
+	Create(ctx context.Context, configMap *v1.ConfigMap, opts metav1.CreateOptions) (*v1.ConfigMap, error)
+	Update(ctx context.Context, configMap *v1.ConfigMap, opts metav1.UpdateOptions) (*v1.ConfigMap, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+}
+
+var invalidConfigMapNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+type configMapStore struct {
+	mutex       sync.Mutex
+	client      ConfigMapClient
+	prefix      string
+	maxVersions int
+}
+
+// NewConfigMapStore returns a store which keeps the history of each job in a ConfigMap, the versions are the keys of
+// the data. The name of a ConfigMap is the prefix followed by the job name and its hash. The oldest versions are
+// pruned when there are more than maxVersions of them, or when they do not fit in a ConfigMap which is limited to
+// 1MiB. Only the size is checked if maxVersions is not positive
+func NewConfigMapStore(client ConfigMapClient, prefix string, maxVersions int) devops.ConfigHistoryStore {
+	return &configMapStore{client: client, prefix: prefix, maxVersions: maxVersions}
+}
+
+func (s *configMapStore) Add(job string, version *devops.ConfigVersion) (*devops.ConfigVersion, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ctx := context.Background()
+	configMap, err := s.client.Get(ctx, s.configMapName(job), metav1.GetOptions{})
+	exists := err == nil
+	if apierrors.IsNotFound(err) {
+		configMap = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        s.configMapName(job),
+				Annotations: map[string]string{ConfigMapJobAnnotation: job},
+			},
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+
+	added := *version
+	added.Version = 1
+	if numbers := versionKeys(configMap); len(numbers) > 0 {
+		added.Version = numbers[len(numbers)-1] + 1
+	}
+	data, err := json.Marshal(&added)
+	if err != nil {
+		return nil, err
+	}
+	configMap.Data[strconv.Itoa(added.Version)] = string(data)
+	if err = s.prune(configMap); err != nil {
+		return nil, err
+	}
+
+	if exists {
+		_, err = s.client.Update(ctx, configMap, metav1.UpdateOptions{})
+	} else {
+		_, err = s.client.Create(ctx, configMap, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &added, nil
+}
+
+func (s *configMapStore) List(job string) ([]*devops.ConfigVersion, error) {
+	configMap, err := s.client.Get(context.Background(), s.configMapName(job), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []*devops.ConfigVersion{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	numbers := versionKeys(configMap)
+	versions := make([]*devops.ConfigVersion, 0, len(numbers))
+	for _, number := range numbers {
+		version, err := parseVersion(job, configMap.Data[strconv.Itoa(number)])
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+func (s *configMapStore) Get(job string, version int) (*devops.ConfigVersion, error) {
+	configMap, err := s.client.Get(context.Background(), s.configMapName(job), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, devops.ErrConfigVersionNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	data, ok := configMap.Data[strconv.Itoa(version)]
+	if !ok {
+		return nil, devops.ErrConfigVersionNotFound
+	}
+	return parseVersion(job, data)
+}
+
+// Move copies the data into the ConfigMap of the target before deleting the one of the job,
+// so the versions are kept if it fails in the middle
+func (s *configMapStore) Move(job, target string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ctx := context.Background()
+	configMap, err := s.client.Get(ctx, s.configMapName(job), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if err = s.client.Delete(ctx, s.configMapName(target), metav1.DeleteOptions{}); apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	targetConfigMap, err := s.client.Get(ctx, s.configMapName(target), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		targetConfigMap = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        s.configMapName(target),
+				Annotations: map[string]string{ConfigMapJobAnnotation: target},
+			},
+			Data: configMap.Data,
+		}
+		_, err = s.client.Create(ctx, targetConfigMap, metav1.CreateOptions{})
+	} else if err == nil {
+		targetConfigMap.Data = configMap.Data
+		_, err = s.client.Update(ctx, targetConfigMap, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	if err = s.client.Delete(ctx, configMap.Name, metav1.DeleteOptions{}); apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// prune removes the oldest versions until the rest are within the limits, the latest one is always kept
+func (s *configMapStore) prune(configMap *v1.ConfigMap) error {
+	numbers := versionKeys(configMap)
+	size := 0
+	for key, value := range configMap.Data {
+		size += len(key) + len(value)
+	}
+	for len(numbers) > 1 && (s.maxVersions > 0 && len(numbers) > s.maxVersions || size > maxConfigMapDataSize) {
+		key := strconv.Itoa(numbers[0])
+		size -= len(key) + len(configMap.Data[key])
+		delete(configMap.Data, key)
+		numbers = numbers[1:]
+	}
+	if size > maxConfigMapDataSize {
+		return fmt.Errorf("the config of job %s is too large to be kept in a ConfigMap",
+			configMap.Annotations[ConfigMapJobAnnotation])
+	}
+	return nil
+}
+
+// configMapName returns a valid name for the ConfigMap of a job, the hash avoids the conflicts
+// between the names which are the same after being sanitized
+func (s *configMapStore) configMapName(job string) string {
+	hash := sha256.Sum256([]byte(job))
+	name := strings.Trim(invalidConfigMapNameChars.ReplaceAllString(strings.ToLower(job), "-"), "-")
+	// the name of a ConfigMap has at most 253 characters
+	if maxLength := 253 - len(s.prefix) - 9; len(name) > maxLength {
+		name = name[:maxLength]
+	}
+	return fmt.Sprintf("%s%s-%s", s.prefix, name, hex.EncodeToString(hash[:4]))
+}
+
+func versionKeys(configMap *v1.ConfigMap) []int {
+	var numbers []int
+	for key := range configMap.Data {
+		if number, err := strconv.Atoi(key); err == nil {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Ints(numbers)
+	return numbers
+}
+
+func parseVersion(job, data string) (*devops.ConfigVersion, error) {
+	version := &devops.ConfigVersion{}
+	if err := json.Unmarshal([]byte(data), version); err != nil {
+		return nil, fmt.Errorf("invalid version of job %s: %v", job, err)
+	}
+	return version, nil
+}
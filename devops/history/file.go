@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/opswave/go-jenkins/devops"
+)
+
+type fileStore struct {
+	mutex sync.Mutex
+	dir   string
+}
+
+// NewFileStore returns a store which writes each version into a JSON file, the files of a job are in a sub directory
+// of dir named by the escaped job name
+func NewFileStore(dir string) devops.ConfigHistoryStore {
+	return &fileStore{dir: dir}
+}
+
+func (s *fileStore) Add(job string, version *devops.ConfigVersion) (*devops.ConfigVersion, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	numbers, err := s.versionNumbers(job)
+	if err != nil {
+		return nil, err
+	}
+	added := *version
+	added.Version = 1
+	if len(numbers) > 0 {
+		added.Version = numbers[len(numbers)-1] + 1
+	}
+
+	data, err := json.Marshal(&added)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(s.jobDir(job), 0700); err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(s.versionFile(job, added.Version), data, 0600); err != nil {
+		return nil, err
+	}
+	return &added, nil
+}
+
+func (s *fileStore) List(job string) ([]*devops.ConfigVersion, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	numbers, err := s.versionNumbers(job)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]*devops.ConfigVersion, 0, len(numbers))
+	for _, number := range numbers {
+		version, err := s.read(job, number)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+func (s *fileStore) Get(job string, version int) (*devops.ConfigVersion, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.read(job, version)
+}
+
+func (s *fileStore) Move(job, target string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := os.RemoveAll(s.jobDir(target)); err != nil {
+		return err
+	}
+	if err := os.Rename(s.jobDir(job), s.jobDir(target)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) read(job string, number int) (*devops.ConfigVersion, error) {
+	data, err := os.ReadFile(s.versionFile(job, number))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, devops.ErrConfigVersionNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	version := &devops.ConfigVersion{}
+	if err = json.Unmarshal(data, version); err != nil {
+		return nil, fmt.Errorf("invalid version file of job %s: %v", job, err)
+	}
+	return version, nil
+}
+
+// versionNumbers returns the sorted version numbers of a job
+func (s *fileStore) versionNumbers(job string) ([]int, error) {
+	entries, err := os.ReadDir(s.jobDir(job))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var numbers []int
+	for _, entry := range entries {
+		if number, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), ".json")); err == nil && !entry.IsDir() {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Ints(numbers)
+	return numbers, nil
+}
+
+func (s *fileStore) jobDir(job string) string {
+	return filepath.Join(s.dir, url.PathEscape(job))
+}
+
+func (s *fileStore) versionFile(job string, version int) string {
+	return filepath.Join(s.jobDir(job), strconv.Itoa(version)+".json")
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/opswave/go-jenkins/devops"
+)
+
+type fakeConfigMapClient struct {
+	configMaps map[string]*v1.ConfigMap
+}
+
+func (c *fakeConfigMapClient) Get(_ context.Context, name string, _ metav1.GetOptions) (*v1.ConfigMap, error) {
+	if configMap, ok := c.configMaps[name]; ok {
+		return configMap.DeepCopy(), nil
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name)
+}
+
+func (c *fakeConfigMapClient) Create(_ context.Context, configMap *v1.ConfigMap, _ metav1.CreateOptions) (*v1.ConfigMap, error) {
+	if _, ok := c.configMaps[configMap.Name]; ok {
+		return nil, apierrors.NewAlreadyExists(schema.GroupResource{Resource: "configmaps"}, configMap.Name)
+	}
+	c.configMaps[configMap.Name] = configMap.DeepCopy()
+	return configMap, nil
+}
+
+func (c *fakeConfigMapClient) Update(_ context.Context, configMap *v1.ConfigMap, _ metav1.UpdateOptions) (*v1.ConfigMap, error) {
+	if _, ok := c.configMaps[configMap.Name]; !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, configMap.Name)
+	}
+	c.configMaps[configMap.Name] = configMap.DeepCopy()
+	return configMap, nil
+}
+
+func (c *fakeConfigMapClient) Delete(_ context.Context, name string, _ metav1.DeleteOptions) error {
+	if _, ok := c.configMaps[name]; !ok {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name)
+	}
+	delete(c.configMaps, name)
+	return nil
+}
+
+func TestStores(t *testing.T) {
+	timestamp := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	stores := map[string]func(t *testing.T) devops.ConfigHistoryStore{
+		"memory": func(t *testing.T) devops.ConfigHistoryStore {
+			return NewMemoryStore()
+		},
+		"file": func(t *testing.T) devops.ConfigHistoryStore {
+			return NewFileStore(t.TempDir())
+		},
+		"configmap": func(t *testing.T) devops.ConfigHistoryStore {
+			return NewConfigMapStore(&fakeConfigMapClient{configMaps: map[string]*v1.ConfigMap{}}, "history-", 0)
+		},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+
+			versions, err := store.List("project/pipeline")
+			assert.Nil(t, err)
+			assert.Empty(t, versions)
+			_, err = store.Get("project/pipeline", 1)
+			assert.Equal(t, devops.ErrConfigVersionNotFound, err)
+
+			for _, config := range []string{"<a/>", "<b/>"} {
+				_, err = store.Add("project/pipeline", &devops.ConfigVersion{Author: "admin", Timestamp: timestamp, Config: config})
+				assert.Nil(t, err)
+			}
+			added, err := store.Add("project/other", &devops.ConfigVersion{Config: "<c/>"})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, added.Version)
+
+			versions, err = store.List("project/pipeline")
+			assert.Nil(t, err)
+			assert.Equal(t, []*devops.ConfigVersion{
+				{Version: 1, Author: "admin", Timestamp: timestamp, Config: "<a/>"},
+				{Version: 2, Author: "admin", Timestamp: timestamp, Config: "<b/>"},
+			}, versions)
+
+			version, err := store.Get("project/pipeline", 2)
+			assert.Nil(t, err)
+			assert.Equal(t, "<b/>", version.Config)
+			_, err = store.Get("project/pipeline", 3)
+			assert.Equal(t, devops.ErrConfigVersionNotFound, err)
+
+			// the versions of the moved job replace the ones of the target
+			assert.Nil(t, store.Move("project/pipeline", "project/other"))
+			versions, err = store.List("project/other")
+			assert.Nil(t, err)
+			assert.Len(t, versions, 2)
+			versions, err = store.List("project/pipeline")
+			assert.Nil(t, err)
+			assert.Empty(t, versions)
+			assert.Nil(t, store.Move("project/pipeline", "project/other"))
+			versions, err = store.List("project/other")
+			assert.Nil(t, err)
+			assert.Empty(t, versions)
+		})
+	}
+}
+
+func TestConfigMapStorePrune(t *testing.T) {
+	store := NewConfigMapStore(&fakeConfigMapClient{configMaps: map[string]*v1.ConfigMap{}}, "history-", 2)
+	for _, config := range []string{"<a/>", "<b/>", "<c/>"} {
+		_, err := store.Add("project/pipeline", &devops.ConfigVersion{Config: config})
+		assert.Nil(t, err)
+	}
+	versions, err := store.List("project/pipeline")
+	assert.Nil(t, err)
+	if assert.Len(t, versions, 2) {
+		assert.Equal(t, 2, versions[0].Version)
+		assert.Equal(t, 3, versions[1].Version)
+	}
+	_, err = store.Get("project/pipeline", 1)
+	assert.Equal(t, devops.ErrConfigVersionNotFound, err)
+
+	// the oldest versions are pruned to fit in a ConfigMap
+	store = NewConfigMapStore(&fakeConfigMapClient{configMaps: map[string]*v1.ConfigMap{}}, "history-", 0)
+	large := "<a>" + strings.Repeat("x", maxConfigMapDataSize/3) + "</a>"
+	for i := 0; i < 4; i++ {
+		_, err = store.Add("project/pipeline", &devops.ConfigVersion{Config: large})
+		assert.Nil(t, err)
+	}
+	versions, err = store.List("project/pipeline")
+	assert.Nil(t, err)
+	if assert.Len(t, versions, 2) {
+		assert.Equal(t, 4, versions[1].Version)
+	}
+
+	_, err = store.Add("project/pipeline", &devops.ConfigVersion{Config: strings.Repeat("x", maxConfigMapDataSize)})
+	assert.NotNil(t, err)
+}
+
+func TestConfigMapName(t *testing.T) {
+	store := &configMapStore{prefix: "history-"}
+	assert.Regexp(t, `^history-project-my-pipeline-[0-9a-f]{8}$`, store.configMapName("Project/My_Pipeline"))
+	assert.NotEqual(t, store.configMapName("project/pipeline"), store.configMapName("project-pipeline"))
+}
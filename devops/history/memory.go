@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package history provides the stores of the config history of jobs
+package history
+
+import (
+	"sync"
+
+	"github.com/opswave/go-jenkins/devops"
+)
+
+type memoryStore struct {
+	mutex    sync.RWMutex
+	versions map[string][]*devops.ConfigVersion
+}
+
+// NewMemoryStore returns a store which keeps the history in memory, the history is lost when the process exits
+func NewMemoryStore() devops.ConfigHistoryStore {
+	return &memoryStore{versions: map[string][]*devops.ConfigVersion{}}
+}
+
+func (s *memoryStore) Add(job string, version *devops.ConfigVersion) (*devops.ConfigVersion, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	added := *version
+	added.Version = len(s.versions[job]) + 1
+	s.versions[job] = append(s.versions[job], &added)
+	result := added
+	return &result, nil
+}
+
+func (s *memoryStore) List(job string) ([]*devops.ConfigVersion, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	versions := make([]*devops.ConfigVersion, 0, len(s.versions[job]))
+	for _, version := range s.versions[job] {
+		copied := *version
+		versions = append(versions, &copied)
+	}
+	return versions, nil
+}
+
+func (s *memoryStore) Get(job string, version int) (*devops.ConfigVersion, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	versions := s.versions[job]
+	if version < 1 || version > len(versions) {
+		return nil, devops.ErrConfigVersionNotFound
+	}
+	copied := *versions[version-1]
+	return &copied, nil
+}
+
+func (s *memoryStore) Move(job, target string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if versions, ok := s.versions[job]; ok {
+		s.versions[target] = versions
+		delete(s.versions, job)
+	} else {
+		delete(s.versions, target)
+	}
+	return nil
+}
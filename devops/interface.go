@@ -28,6 +28,10 @@ import (
 type Interface interface {
 	CredentialOperator
 
+	CredentialDomainOperator
+
+	CredentialUsageOperator
+
 	BuildGetter
 
 	PipelineOperator
@@ -37,6 +41,8 @@ type Interface interface {
 	ProjectOperator
 
 	ConfigurationOperator
+
+	ConfigHistoryOperator
 }
 
 func GetDevOpsStatusCode(devopsErr error) int {
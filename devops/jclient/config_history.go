@@ -0,0 +1,39 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jclient
+
+import "github.com/opswave/go-jenkins/devops"
+
+// ListProjectPipelineConfigHistory returns the config versions of a pipeline
+func (j *JenkinsClient) ListProjectPipelineConfigHistory(projectID, pipelineID string) ([]*devops.ConfigVersion, error) {
+	return j.jenkins.ListProjectPipelineConfigHistory(projectID, pipelineID)
+}
+
+// GetProjectPipelineConfigVersion returns a config version of a pipeline
+func (j *JenkinsClient) GetProjectPipelineConfigVersion(projectID, pipelineID string, version int) (*devops.ConfigVersion, error) {
+	return j.jenkins.GetProjectPipelineConfigVersion(projectID, pipelineID, version)
+}
+
+// DiffProjectPipelineConfigVersions returns the diff between two config versions of a pipeline
+func (j *JenkinsClient) DiffProjectPipelineConfigVersions(projectID, pipelineID string, from, to int) (string, error) {
+	return j.jenkins.DiffProjectPipelineConfigVersions(projectID, pipelineID, from, to)
+}
+
+// RollbackProjectPipeline rolls a pipeline back to a config version
+func (j *JenkinsClient) RollbackProjectPipeline(projectID, pipelineID string, version int) error {
+	return j.jenkins.RollbackProjectPipeline(projectID, pipelineID, version)
+}
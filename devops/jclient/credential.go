@@ -52,12 +52,57 @@ func (j *JenkinsClient) GetCredentialInProject(projectID, id string) (*devops.Cr
 	return j.jenkins.GetCredentialInProject(projectID, id)
 }
 
-// DeleteCredentialInProject deletes a credential
-func (j *JenkinsClient) DeleteCredentialInProject(projectID, id string) (string, error) {
+// DeleteCredentialInProject deletes a credential, the credential in use is not deleted unless force is true
+func (j *JenkinsClient) DeleteCredentialInProject(projectID, id string, force bool) (string, error) {
+	if !force {
+		if err := j.jenkins.CheckCredentialUnusedInProject(projectID, id); err != nil {
+			return "", err
+		}
+	}
 	client := j.getClient()
 	return id, client.DeleteInFolder(projectID, id)
 }
 
+// ListCredentialsInProject lists the credentials of all domains in a project
+func (j *JenkinsClient) ListCredentialsInProject(projectID string) ([]*devops.Credential, error) {
+	return j.jenkins.ListCredentialsInProject(projectID)
+}
+
+// CreateCredentialDomainInProject creates a credential domain, then returns the name
+func (j *JenkinsClient) CreateCredentialDomainInProject(projectID string, domain *devops.CredentialDomain) (string, error) {
+	return j.jenkins.CreateCredentialDomainInProject(projectID, domain)
+}
+
+// UpdateCredentialDomainInProject updates a credential domain
+func (j *JenkinsClient) UpdateCredentialDomainInProject(projectID string, domain *devops.CredentialDomain) (string, error) {
+	return j.jenkins.UpdateCredentialDomainInProject(projectID, domain)
+}
+
+// GetCredentialDomainInProject returns a credential domain
+func (j *JenkinsClient) GetCredentialDomainInProject(projectID, name string) (*devops.CredentialDomain, error) {
+	return j.jenkins.GetCredentialDomainInProject(projectID, name)
+}
+
+// DeleteCredentialDomainInProject deletes a credential domain
+func (j *JenkinsClient) DeleteCredentialDomainInProject(projectID, name string) (string, error) {
+	return j.jenkins.DeleteCredentialDomainInProject(projectID, name)
+}
+
+// ListCredentialDomainsInProject lists the credential domains of a project
+func (j *JenkinsClient) ListCredentialDomainsInProject(projectID string) ([]*devops.CredentialDomain, error) {
+	return j.jenkins.ListCredentialDomainsInProject(projectID)
+}
+
+// GetCredentialUsageInProject returns the usage of all credentials in a project
+func (j *JenkinsClient) GetCredentialUsageInProject(projectID string) (map[string]*devops.CredentialUsage, error) {
+	return j.jenkins.GetCredentialUsageInProject(projectID)
+}
+
+// CheckCredentialUnusedInProject returns a conflict error if the credential is in use
+func (j *JenkinsClient) CheckCredentialUnusedInProject(projectID, id string) error {
+	return j.jenkins.CheckCredentialUnusedInProject(projectID, id)
+}
+
 func (j *JenkinsClient) getClient() *jcredential.CredentialsManager {
 	return &jcredential.CredentialsManager{JenkinsCore: j.Core}
 }
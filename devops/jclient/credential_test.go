@@ -49,7 +49,8 @@ func TestDeleteCredentialInProject(t *testing.T) {
 	const folder = "fake"
 	const id = "id"
 	jcredential.PrepareForDeleteCredentialInFolder(roundTripper, "http://localhost", "", "", folder, id)
-	val, err := client.DeleteCredentialInProject(folder, id)
+	// the usage is checked by the jenkins package, only the deletion is tested here
+	val, err := client.DeleteCredentialInProject(folder, id, true)
 	assert.Nil(t, err)
 	assert.Equal(t, id, val)
 }
@@ -57,3 +57,8 @@ func NewJenkinsClient(options *jenkins.Options) (*JenkinsClient, error) {
 		jenkins: devopsClient, // For refactor purpose only
 	}, nil
 }
+
+// SetConfigHistoryStore enables recording the config history of jobs into the store
+func (j *JenkinsClient) SetConfigHistoryStore(store devops.ConfigHistoryStore) {
+	j.jenkins.ConfigHistory = store
+}
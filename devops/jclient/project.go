@@ -16,6 +16,12 @@ limitations under the License.
 
 package jclient
 
+import (
+	"io"
+
+	"github.com/opswave/go-jenkins/devops"
+)
+
 // CreateDevOpsProject creates a devops project
 func (j *JenkinsClient) CreateDevOpsProject(projectID string) (string, error) {
 	return j.jenkins.CreateDevOpsProject(projectID)
@@ -30,3 +36,63 @@ func (j *JenkinsClient) DeleteDevOpsProject(projectID string) error {
 func (j *JenkinsClient) GetDevOpsProject(projectID string) (string, error) {
 	return j.jenkins.GetDevOpsProject(projectID)
 }
+
+// ListDevOpsProjects returns all the devops projects
+func (j *JenkinsClient) ListDevOpsProjects() ([]*devops.DevOpsProject, error) {
+	return j.jenkins.ListDevOpsProjects()
+}
+
+// CheckDevOpsProjectDeletion reports what would be lost if a devops project is deleted
+func (j *JenkinsClient) CheckDevOpsProjectDeletion(projectID string) (*devops.ProjectDeletionReport, error) {
+	return j.jenkins.CheckDevOpsProjectDeletion(projectID)
+}
+
+// DeleteDevOpsProjectWithOptions deletes a devops project in the given mode
+func (j *JenkinsClient) DeleteDevOpsProjectWithOptions(projectID string, options *devops.ProjectDeleteOptions) (*devops.ProjectDeletionReport, error) {
+	return j.jenkins.DeleteDevOpsProjectWithOptions(projectID, options)
+}
+
+// CreateDevOpsProjectWithConfig creates a devops project with the folder configuration
+func (j *JenkinsClient) CreateDevOpsProjectWithConfig(projectID string, config *devops.ProjectFolderConfig) (string, error) {
+	return j.jenkins.CreateDevOpsProjectWithConfig(projectID, config)
+}
+
+// GetDevOpsProjectConfig returns the folder configuration of a devops project
+func (j *JenkinsClient) GetDevOpsProjectConfig(projectID string) (*devops.ProjectFolderConfig, error) {
+	return j.jenkins.GetDevOpsProjectConfig(projectID)
+}
+
+// UpdateDevOpsProjectConfig updates the folder configuration of a devops project
+func (j *JenkinsClient) UpdateDevOpsProjectConfig(projectID string, config *devops.ProjectFolderConfig) (*devops.ProjectFolderConfig, error) {
+	return j.jenkins.UpdateDevOpsProjectConfig(projectID, config)
+}
+
+// ListProjectLibraries returns the pipeline shared libraries of a devops project
+func (j *JenkinsClient) ListProjectLibraries(projectID string) ([]*devops.PipelineLibrary, error) {
+	return j.jenkins.ListProjectLibraries(projectID)
+}
+
+// CreateProjectLibrary adds a pipeline shared library to a devops project
+func (j *JenkinsClient) CreateProjectLibrary(projectID string, library *devops.PipelineLibrary) (*devops.PipelineLibrary, error) {
+	return j.jenkins.CreateProjectLibrary(projectID, library)
+}
+
+// UpdateProjectLibrary updates a pipeline shared library of a devops project
+func (j *JenkinsClient) UpdateProjectLibrary(projectID string, library *devops.PipelineLibrary) (*devops.PipelineLibrary, error) {
+	return j.jenkins.UpdateProjectLibrary(projectID, library)
+}
+
+// DeleteProjectLibrary removes a pipeline shared library from a devops project
+func (j *JenkinsClient) DeleteProjectLibrary(projectID, name string) error {
+	return j.jenkins.DeleteProjectLibrary(projectID, name)
+}
+
+// ExportProject writes a devops project into an archive
+func (j *JenkinsClient) ExportProject(projectID string, writer io.Writer, options *devops.ProjectExportOptions) (*devops.ProjectArchiveManifest, error) {
+	return j.jenkins.ExportProject(projectID, writer, options)
+}
+
+// ImportProject restores a devops project from an archive
+func (j *JenkinsClient) ImportProject(projectID string, reader io.Reader, options *devops.ProjectImportOptions) (*devops.ProjectImportResult, error) {
+	return j.jenkins.ImportProject(projectID, reader, options)
+}
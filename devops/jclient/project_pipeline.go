@@ -60,6 +60,9 @@ func (j *JenkinsClient) CreateProjectPipeline(projectID string, pipeline *v1alph
 			return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
 		}
 		return pipeline.Name, nil
+	case devopsv1alpha3.OrganizationFolderPipelineType:
+		// an organization folder cannot be created without its navigator, so create it with the config directly
+		return j.jenkins.CreateProjectPipeline(projectID, pipeline)
 	default:
 		err := fmt.Errorf("error unsupport job type")
 		klog.Errorf("%+v", err)
@@ -91,6 +94,66 @@ func (j *JenkinsClient) GetProjectPipelineConfig(projectID, pipelineID string) (
 	return j.jenkins.GetProjectPipelineConfig(projectID, pipelineID)
 }
 
+// GetOrganizationFolderRepositories returns the repositories matched by an organization folder
+func (j *JenkinsClient) GetOrganizationFolderRepositories(folder *devopsv1alpha3.OrganizationFolder, httpParameters *devops.HttpParameters) ([]string, error) {
+	return j.jenkins.GetOrganizationFolderRepositories(folder, httpParameters)
+}
+
+// DiffProjectPipeline compares a pipeline with the config of its job
+func (j *JenkinsClient) DiffProjectPipeline(projectID string, pipeline *devopsv1alpha3.Pipeline) (*devops.PipelineDrift, error) {
+	return j.jenkins.DiffProjectPipeline(projectID, pipeline)
+}
+
+// ScanProjectPipelineDrift reports the drift of all pipelines in a project
+func (j *JenkinsClient) ScanProjectPipelineDrift(projectID string, pipelines []*devopsv1alpha3.Pipeline) ([]*devops.PipelineDrift, error) {
+	return j.jenkins.ScanProjectPipelineDrift(projectID, pipelines)
+}
+
+// DiscoverProjectJobs converts the jobs in the folder tree of a project to pipelines
+func (j *JenkinsClient) DiscoverProjectJobs(projectID string) ([]*devops.DiscoveredJob, error) {
+	return j.jenkins.DiscoverProjectJobs(projectID)
+}
+
+// ApplyProjectPipeline creates or updates a pipeline
+func (j *JenkinsClient) ApplyProjectPipeline(projectID string, pipeline *devopsv1alpha3.Pipeline, fingerprint string) (*devops.PipelineApplyResult, error) {
+	return j.jenkins.ApplyProjectPipeline(projectID, pipeline, fingerprint)
+}
+
+// ListDownstreamPipelines returns the pipelines triggered by a pipeline
+func (j *JenkinsClient) ListDownstreamPipelines(projectID, pipelineID string) ([]*devops.PipelineDependency, error) {
+	return j.jenkins.ListDownstreamPipelines(projectID, pipelineID)
+}
+
+// MoveProjectPipeline moves a pipeline to another project
+func (j *JenkinsClient) MoveProjectPipeline(projectID, pipelineID, targetProjectID string, options *devops.PipelineRelocateOptions) (*devops.PipelineRelocateResult, error) {
+	return j.jenkins.MoveProjectPipeline(projectID, pipelineID, targetProjectID, options)
+}
+
+// RenameProjectPipeline renames a pipeline in its project
+func (j *JenkinsClient) RenameProjectPipeline(projectID, pipelineID, newName string) (*devops.PipelineRelocateResult, error) {
+	return j.jenkins.RenameProjectPipeline(projectID, pipelineID, newName)
+}
+
+// CopyProjectPipeline copies a pipeline to the same or another project
+func (j *JenkinsClient) CopyProjectPipeline(projectID, pipelineID, targetProjectID, targetName string, options *devops.PipelineRelocateOptions) (*devops.PipelineRelocateResult, error) {
+	return j.jenkins.CopyProjectPipeline(projectID, pipelineID, targetProjectID, targetName, options)
+}
+
+// EnableProjectPipeline enables a pipeline
+func (j *JenkinsClient) EnableProjectPipeline(projectID, pipelineID string) error {
+	return j.jenkins.EnableProjectPipeline(projectID, pipelineID)
+}
+
+// DisableProjectPipeline disables a pipeline with the reason
+func (j *JenkinsClient) DisableProjectPipeline(projectID, pipelineID, reason string) error {
+	return j.jenkins.DisableProjectPipeline(projectID, pipelineID, reason)
+}
+
+// ToggleProjectPipelines disables or enables all the pipelines of a project
+func (j *JenkinsClient) ToggleProjectPipelines(projectID string, disabled bool, reason string) ([]*devops.PipelineToggleResult, error) {
+	return j.jenkins.ToggleProjectPipelines(projectID, disabled, reason)
+}
+
 func getCreatePayload(pipeline *devopsv1alpha3.NoScmPipeline) (jobPayload *job.CreateJobPayload, err error) {
 	// NoScmPipeline do not have copy mode to create a pipeline
 	jobPayload = &job.CreateJobPayload{
@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/klog/v2"
+
+	"github.com/opswave/go-jenkins/devops"
+)
+
+// currentConfigVersion stands for the config in Jenkins when diffing versions
+const currentConfigVersion = 0
+
+// recordConfigHistory saves the current config of the job before it is replaced
+func (j *Job) recordConfigHistory() error {
+	store := j.Jenkins.ConfigHistory
+	if store == nil {
+		return nil
+	}
+	config, err := j.GetConfig()
+	if err != nil {
+		return err
+	}
+	var author string
+	if auth := j.Jenkins.Requester.BasicAuth; auth != nil {
+		author = auth.Username
+	}
+	_, err = store.Add(j.fullName(), &devops.ConfigVersion{
+		Author:    author,
+		Timestamp: time.Now(),
+		Config:    config,
+	})
+	return err
+}
+
+// moveConfigHistory moves the history of a job after it is moved or renamed, the history is best effort
+// as it is recorded
+func (j *Jenkins) moveConfigHistory(oldName, newName string) {
+	if store := j.ConfigHistory; store != nil {
+		if err := store.Move(oldName, newName); err != nil {
+			klog.Errorf("failed to move the config history of job %s to %s: %+v", oldName, newName, err)
+		}
+	}
+}
+
+// fullName returns the slash separated name of the job including its folders, such as project/pipeline
+func (j *Job) fullName() string {
+	return strings.ReplaceAll(strings.TrimPrefix(j.Base, "/job/"), "/job/", "/")
+}
+
+func (j *Jenkins) ListProjectPipelineConfigHistory(projectId, pipelineId string) ([]*devops.ConfigVersion, error) {
+	if j.ConfigHistory == nil {
+		return nil, errConfigHistoryDisabled()
+	}
+	versions, err := j.ConfigHistory.List(projectId + "/" + pipelineId)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	for _, version := range versions {
+		version.Config = ""
+	}
+	return versions, nil
+}
+
+func (j *Jenkins) GetProjectPipelineConfigVersion(projectId, pipelineId string, version int) (*devops.ConfigVersion, error) {
+	if j.ConfigHistory == nil {
+		return nil, errConfigHistoryDisabled()
+	}
+	configVersion, err := j.ConfigHistory.Get(projectId+"/"+pipelineId, version)
+	if errors.Is(err, devops.ErrConfigVersionNotFound) {
+		return nil, restful.NewError(http.StatusNotFound, fmt.Sprintf("version %d of pipeline %s not found", version, pipelineId))
+	} else if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	return configVersion, nil
+}
+
+func (j *Jenkins) DiffProjectPipelineConfigVersions(projectId, pipelineId string, from, to int) (string, error) {
+	fromConfig, err := j.getProjectPipelineConfigOfVersion(projectId, pipelineId, from)
+	if err != nil {
+		return "", err
+	}
+	toConfig, err := j.getProjectPipelineConfigOfVersion(projectId, pipelineId, to)
+	if err != nil {
+		return "", err
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromConfig),
+		B:        difflib.SplitLines(toConfig),
+		FromFile: configVersionName(pipelineId, from),
+		ToFile:   configVersionName(pipelineId, to),
+		Context:  3,
+	})
+	if err != nil {
+		return "", restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	return diff, nil
+}
+
+func (j *Jenkins) RollbackProjectPipeline(projectId, pipelineId string, version int) error {
+	configVersion, err := j.GetProjectPipelineConfigVersion(projectId, pipelineId, version)
+	if err != nil {
+		return err
+	}
+	job, err := j.GetJob(pipelineId, projectId)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	if err = job.UpdateConfig(configVersion.Config); err != nil {
+		klog.Errorf("%+v", err)
+		return restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return nil
+}
+
+// getProjectPipelineConfigOfVersion returns the config of a version, or the current config if the version is currentConfigVersion
+func (j *Jenkins) getProjectPipelineConfigOfVersion(projectId, pipelineId string, version int) (string, error) {
+	if version != currentConfigVersion {
+		configVersion, err := j.GetProjectPipelineConfigVersion(projectId, pipelineId, version)
+		if err != nil {
+			return "", err
+		}
+		return configVersion.Config, nil
+	}
+
+	job, err := j.GetJob(pipelineId, projectId)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	config, err := job.GetConfig()
+	if err != nil {
+		return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return config, nil
+}
+
+func configVersionName(pipelineId string, version int) string {
+	if version == currentConfigVersion {
+		return pipelineId + "@current"
+	}
+	return fmt.Sprintf("%s@%d", pipelineId, version)
+}
+
+func errConfigHistoryDisabled() error {
+	return restful.NewError(http.StatusNotImplemented, "the config history is not enabled")
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/opswave/go-jenkins/devops"
+	"github.com/opswave/go-jenkins/devops/history"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+func TestProjectPipelineConfigHistory(t *testing.T) {
+	config, err := createPipelineConfigXml(&devopsv1alpha3.NoScmPipeline{Jenkinsfile: "node{echo 'hello'}"})
+	assert.Nil(t, err)
+	jenkins, requests := newFakeJenkinsWithResponses(t, http.StatusOK, map[string]string{
+		"/job/fake-project/job/deploy/api/json":    `{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"deploy"}`,
+		"/job/fake-project/job/deploy/config.xml/": config,
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, err := jenkins.ListProjectPipelineConfigHistory("fake-project", "deploy")
+		if assert.NotNil(t, err) {
+			assert.Equal(t, http.StatusNotImplemented, err.(restful.ServiceError).Code)
+		}
+	})
+
+	jenkins.ConfigHistory = history.NewMemoryStore()
+	_, err = jenkins.UpdateProjectPipeline("fake-project", &devopsv1alpha3.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "deploy"},
+		Spec: devopsv1alpha3.PipelineSpec{
+			Type:     devopsv1alpha3.NoScmPipelineType,
+			Pipeline: &devopsv1alpha3.NoScmPipeline{Name: "deploy", Jenkinsfile: "node{echo 'world'}"},
+		},
+	})
+	assert.Nil(t, err)
+
+	versions, err := jenkins.ListProjectPipelineConfigHistory("fake-project", "deploy")
+	assert.Nil(t, err)
+	if assert.Len(t, versions, 1) {
+		assert.Equal(t, 1, versions[0].Version)
+		assert.Equal(t, "admin", versions[0].Author)
+		assert.False(t, versions[0].Timestamp.IsZero())
+		assert.Empty(t, versions[0].Config)
+	}
+	version, err := jenkins.GetProjectPipelineConfigVersion("fake-project", "deploy", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, config, version.Config)
+
+	_, err = jenkins.ConfigHistory.Add("fake-project/deploy", &devops.ConfigVersion{Config: "<flow-definition/>\n"})
+	assert.Nil(t, err)
+
+	t.Run("diff", func(t *testing.T) {
+		diff, err := jenkins.DiffProjectPipelineConfigVersions("fake-project", "deploy", 1, currentConfigVersion)
+		assert.Nil(t, err)
+		assert.Empty(t, diff)
+
+		diff, err = jenkins.DiffProjectPipelineConfigVersions("fake-project", "deploy", 2, currentConfigVersion)
+		assert.Nil(t, err)
+		assert.Contains(t, diff, "--- deploy@2\n+++ deploy@current\n")
+		assert.Contains(t, diff, "\n-<flow-definition/>\n")
+		assert.Contains(t, diff, "\n+    <script>node{echo &apos;hello&apos;}</script>\n")
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		*requests = nil
+		err := jenkins.RollbackProjectPipeline("fake-project", "deploy", 2)
+		assert.Nil(t, err)
+		var posted string
+		for _, request := range *requests {
+			if request.method == http.MethodPost && request.path == "/job/fake-project/job/deploy/config.xml" {
+				posted = request.body
+			}
+		}
+		assert.Equal(t, "<flow-definition/>\n", posted)
+
+		versions, err := jenkins.ListProjectPipelineConfigHistory("fake-project", "deploy")
+		assert.Nil(t, err)
+		assert.Len(t, versions, 3)
+	})
+
+	t.Run("version not found", func(t *testing.T) {
+		err := jenkins.RollbackProjectPipeline("fake-project", "deploy", 9)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, http.StatusNotFound, err.(restful.ServiceError).Code)
+		}
+	})
+}
+
+// failingConfigHistoryStore fails to add any version
+type failingConfigHistoryStore struct {
+	devops.ConfigHistoryStore
+}
+
+func (failingConfigHistoryStore) Add(string, *devops.ConfigVersion) (*devops.ConfigVersion, error) {
+	return nil, fmt.Errorf("the store is full")
+}
+
+func TestUpdateConfigWithoutHistory(t *testing.T) {
+	jenkins, requests := newFakeJenkinsWithResponses(t, http.StatusOK, map[string]string{
+		"/job/fake-project/job/deploy/config.xml/": "<flow-definition/>",
+	})
+	jenkins.ConfigHistory = failingConfigHistoryStore{ConfigHistoryStore: history.NewMemoryStore()}
+	job := &Job{Jenkins: jenkins, Raw: new(JobResponse), Base: "/job/fake-project/job/deploy"}
+
+	// the config is updated even if its history can not be recorded
+	assert.Nil(t, job.UpdateConfig("<flow-definition><disabled>true</disabled></flow-definition>"))
+	var updated bool
+	for _, request := range *requests {
+		updated = updated || request.method == http.MethodPost && request.path == "/job/fake-project/job/deploy/config.xml"
+	}
+	assert.True(t, updated)
+}
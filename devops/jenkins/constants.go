@@ -35,6 +35,14 @@ const (
 	PROJECT_ROLE = "projectRoles"
 )
 
+const (
+	WorkflowJobClass                = "org.jenkinsci.plugins.workflow.job.WorkflowJob"
+	WorkflowMultiBranchProjectClass = "org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject"
+	OrganizationFolderClass         = "jenkins.branch.OrganizationFolder"
+	FolderClass                     = "com.cloudbees.hudson.plugins.folder.Folder"
+	FreeStyleProjectClass           = "hudson.model.FreeStyleProject"
+)
+
 const (
 	StringNull = ""
 
@@ -47,6 +55,14 @@ const (
 	NumToKeepTag            = "numToKeep"
 	ArtiDaysToKeepTag       = "artifactDaysToKeep"
 	ArtiNumToKeepTag        = "artifactNumToKeep"
+	AbortPreviousTag        = "abortPrevious"
+	QuietPeriodTag          = "quietPeriod"
+	DurabilityHintJobTag    = "org.jenkinsci.plugins.workflow.job.properties.DurabilityHintJobProperty"
+	DisableResumeJobTag     = "org.jenkinsci.plugins.workflow.job.properties.DisableResumeJobProperty"
+	GithubProjectTag        = "com.coravy.hudson.plugins.github.GithubProjectProperty"
+	GitlabConnectionTag     = "com.dabsquared.gitlabjenkins.connection.GitLabConnectionProperty"
+	RateLimitJobTag         = "jenkins.branch.RateLimitBranchProperty_-JobPropertyImpl"
+	LockableResourcesTag    = "org.jenkins.plugins.lockableresources.RequiredResourcesProperty"
 
 	ParamDefiPropTag = "hudson.model.ParametersDefinitionProperty"
 	ParamDefiTag     = "parameterDefinitions"
@@ -55,13 +71,18 @@ const (
 	TriggersTag            = "triggers"
 	TimerTriggerTag        = "hudson.triggers.TimerTrigger"
 
-	DefinitionTag = "definition"
-	ClassKey      = "class"
-	PluginKey     = "plugin"
-	ScriptTag     = "script"
-	SandboxTag    = "sandbox"
-	AuthTokenTag  = "authToken"
-	DisabledTag   = "disabled"
+	DefinitionTag  = "definition"
+	ClassKey       = "class"
+	PluginKey      = "plugin"
+	ScriptTag      = "script"
+	SandboxTag     = "sandbox"
+	ScriptPathTag  = "scriptPath"
+	LightweightTag = "lightweight"
+	AuthTokenTag   = "authToken"
+	DisabledTag    = "disabled"
+
+	CpsFlowDefinitionClass    = "org.jenkinsci.plugins.workflow.cps.CpsFlowDefinition"
+	CpsScmFlowDefinitionClass = "org.jenkinsci.plugins.workflow.cps.CpsScmFlowDefinition"
 )
 
 // ParameterTypeMap aims for simplifying representation of parameter definition type.
@@ -72,4 +93,28 @@ var ParameterTypeMap = map[string]string{
 	"hudson.model.BooleanParameterDefinition":  "boolean",
 	"hudson.model.FileParameterDefinition":     "file",
 	"hudson.model.PasswordParameterDefinition": "password",
+	ValidatingStringParameterClass:             "validating_string",
+	CredentialsParameterClass:                  "credentials",
+	RunParameterClass:                          "run",
+	ExtendedChoiceParameterClass:               "extended_choice",
+}
+
+// the parameter definitions which have typed options
+const (
+	ValidatingStringParameterClass = "hudson.plugins.validating__string__parameter.ValidatingStringParameterDefinition"
+	CredentialsParameterClass      = "com.cloudbees.plugins.credentials.CredentialsParameterDefinition"
+	RunParameterClass              = "hudson.model.RunParameterDefinition"
+	ExtendedChoiceParameterClass   = "com.cwctravel.hudson.plugins.extended__choice__parameter.ExtendedChoiceParameterDefinition"
+)
+
+// CredentialTypeMap maps the type name of a Jenkins credential to the credential type.
+var CredentialTypeMap = map[string]string{
+	"Username with password":                "basic-auth",
+	"SSH Username with private key":         "ssh-auth",
+	"Secret text":                           "secret-text",
+	"Kubernetes configuration (kubeconfig)": "kubeconfig",
+	"Certificate":                           "certificate",
+	"Secret file":                           "secret-file",
+	"GitHub App":                            "github-app",
+	"AWS Credentials":                       "aws-access-key",
 }
@@ -14,18 +14,43 @@ limitations under the License.
 package jenkins
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 
+	"github.com/beevik/etree"
+	"github.com/emicklei/go-restful"
+	jcredential "github.com/jenkins-zh/jenkins-client/pkg/credential"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
 
 	"github.com/opswave/go-jenkins/devops"
+	"github.com/opswave/go-jenkins/devops/util"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
 )
 
+// credentialResponse is a credential returned by the Jenkins API, which uses camelCase fields
+type credentialResponse struct {
+	devops.Credential
+	TypeName    string `json:"typeName"`
+	DisplayName string `json:"displayName"`
+}
+
+func (c *credentialResponse) toCredential(domain string) *devops.Credential {
+	credential := c.Credential
+	credential.Type = c.TypeName
+	if credentialType, ok := CredentialTypeMap[c.TypeName]; ok {
+		credential.Type = credentialType
+	}
+	credential.DisplayName = c.DisplayName
+	credential.Domain = domain
+	return &credential
+}
+
 func (j *Jenkins) GetCredentialInProject(projectId, id string) (*devops.Credential, error) {
-	responseStruct := &devops.Credential{}
+	responseStruct := &credentialResponse{}
 
 	domain := "_"
 
@@ -40,24 +65,166 @@ func (j *Jenkins) GetCredentialInProject(projectId, id string) (*devops.Credenti
 	if response.StatusCode != http.StatusOK {
 		return nil, errors.New(strconv.Itoa(response.StatusCode))
 	}
-	responseStruct.Domain = domain
-	return responseStruct, nil
+	return responseStruct.toCredential(domain), nil
+}
+
+// ListCredentialsInProject lists the credentials of all domains in a project
+func (j *Jenkins) ListCredentialsInProject(projectId string) ([]*devops.Credential, error) {
+	domains, err := j.listCredentialDomainNames(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := make([]*devops.Credential, 0)
+	for _, domain := range domains {
+		responseStruct := &struct {
+			Credentials []*credentialResponse `json:"credentials"`
+		}{}
+		_, err := j.Requester.GetJSON(
+			fmt.Sprintf("/job/%s/credentials/store/folder/domain/%s", projectId, domain),
+			responseStruct, map[string]string{
+				"depth": "2",
+			})
+		if err != nil {
+			klog.Errorf("%+v", err)
+			return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+		for _, credential := range responseStruct.Credentials {
+			credentials = append(credentials, credential.toCredential(domain))
+		}
+	}
+	return credentials, nil
 }
 
 func (j *Jenkins) CreateCredentialInProject(projectId string, credential *v1.Secret) (string, error) {
-	// use pkg/client/devops/jclient/credential.go instead
-	panic(nil)
+	jenkinsCredential, err := util.ConvertSecretToCredential(credential)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return "", err
+	}
+
+	_, err = j.Requester.PostForm(
+		fmt.Sprintf("/job/%s/credentials/store/folder/domain/_/createCredentials", projectId),
+		nil, nil, map[string]string{
+			"json": fmt.Sprintf(`{"credentials": %s}`, makeJson(jenkinsCredential)),
+		})
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return credential.Name, nil
 }
 
 func (j *Jenkins) UpdateCredentialInProject(projectId string, credential *v1.Secret) (string, error) {
-	// use pkg/client/devops/jclient/credential.go instead
-	panic(nil)
+	credentialXml, err := createCredentialConfigXml(credential)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return "", err
+	}
+
+	_, err = j.Requester.PostXML(
+		fmt.Sprintf("/job/%s/credentials/store/folder/domain/_/credential/%s/config.xml", projectId, credential.Name),
+		credentialXml, nil, nil)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return credential.Name, nil
+}
+
+// DeleteCredentialInProject deletes credential, see also CheckCredentialUnusedInProject
+func (j *Jenkins) DeleteCredentialInProject(projectId, id string, force bool) (string, error) {
+	if !force {
+		if err := j.CheckCredentialUnusedInProject(projectId, id); err != nil {
+			return "", err
+		}
+	}
+	_, err := j.Requester.Post(
+		fmt.Sprintf("/job/%s/credentials/store/folder/domain/_/credential/%s/doDelete", projectId, id),
+		nil, nil, nil)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return id, nil
+}
+
+// createCredentialConfigXml renders the config.xml of the Jenkins credential which the secret maps to.
+func createCredentialConfigXml(secret *v1.Secret) (string, error) {
+	doc := etree.NewDocument()
+	doc.CreateProcInst("xml", `version='1.0' encoding='UTF-8'`)
+
+	var credential *etree.Element
+	switch secret.Type {
+	case devopsv1alpha3.SecretTypeBasicAuth:
+		credential = newCredentialElement(doc, jcredential.UsernamePassswordCredentialStaplerClass, secret.Name)
+		credential.CreateElement("username").SetText(string(secret.Data[devopsv1alpha3.BasicAuthUsernameKey]))
+		credential.CreateElement("password").SetText(string(secret.Data[devopsv1alpha3.BasicAuthPasswordKey]))
+	case devopsv1alpha3.SecretTypeSSHAuth:
+		credential = newCredentialElement(doc, jcredential.SSHCrenditalStaplerClass, secret.Name)
+		credential.CreateElement("username").SetText(string(secret.Data[devopsv1alpha3.SSHAuthUsernameKey]))
+		credential.CreateElement("passphrase").SetText(string(secret.Data[devopsv1alpha3.SSHAuthPassphraseKey]))
+		keySource := credential.CreateElement("privateKeySource")
+		keySource.CreateAttr(ClassKey, jcredential.DirectSSHCrenditalStaplerClass)
+		keySource.CreateElement("privateKey").SetText(string(secret.Data[devopsv1alpha3.SSHAuthPrivateKey]))
+	case devopsv1alpha3.SecretTypeSecretText:
+		credential = newCredentialElement(doc, jcredential.SecretTextCredentialStaplerClass, secret.Name)
+		credential.CreateElement("secret").SetText(string(secret.Data[devopsv1alpha3.SecretTextSecretKey]))
+	case devopsv1alpha3.SecretTypeKubeConfig:
+		credential = newCredentialElement(doc, jcredential.KubeconfigCredentialStaplerClass, secret.Name)
+		kubeconfigSource := credential.CreateElement("kubeconfigSource")
+		kubeconfigSource.CreateAttr(ClassKey, jcredential.DirectKubeconfigCredentialStaperClass)
+		kubeconfigSource.CreateElement("content").SetText(string(secret.Data[devopsv1alpha3.KubeConfigSecretKey]))
+	case devopsv1alpha3.SecretTypeCertificate:
+		credential = newCredentialElement(doc, util.CertificateCredentialStaplerClass, secret.Name)
+		credential.CreateElement("password").SetText(string(secret.Data[devopsv1alpha3.CertificatePasswordKey]))
+		keyStoreSource := credential.CreateElement("keyStoreSource")
+		keyStoreSource.CreateAttr(ClassKey, util.UploadedKeyStoreSourceStaplerClass)
+		keyStoreSource.CreateElement("uploadedKeystoreBytes").
+			SetText(base64.StdEncoding.EncodeToString(secret.Data[devopsv1alpha3.CertificateKeystoreKey]))
+	case devopsv1alpha3.SecretTypeSecretFile:
+		credential = newCredentialElement(doc, util.FileCredentialStaplerClass, secret.Name)
+		credential.CreateElement("fileName").SetText(string(secret.Data[devopsv1alpha3.SecretFileNameKey]))
+		credential.CreateElement("secretBytes").
+			SetText(base64.StdEncoding.EncodeToString(secret.Data[devopsv1alpha3.SecretFileContentKey]))
+	case devopsv1alpha3.SecretTypeGitHubApp:
+		credential = newCredentialElement(doc, util.GitHubAppCredentialStaplerClass, secret.Name)
+		credential.CreateElement("appID").SetText(string(secret.Data[devopsv1alpha3.GitHubAppIDKey]))
+		credential.CreateElement("privateKey").SetText(string(secret.Data[devopsv1alpha3.GitHubAppPrivateKey]))
+		if apiURI := string(secret.Data[devopsv1alpha3.GitHubAppAPIURIKey]); apiURI != "" {
+			credential.CreateElement("apiUri").SetText(apiURI)
+		}
+		if owner := string(secret.Data[devopsv1alpha3.GitHubAppOwnerKey]); owner != "" {
+			credential.CreateElement("owner").SetText(owner)
+		}
+	case devopsv1alpha3.SecretTypeDockerRegistry:
+		username, password, err := util.GetDockerRegistryAuth(secret)
+		if err != nil {
+			return "", restful.NewError(http.StatusBadRequest, err.Error())
+		}
+		credential = newCredentialElement(doc, jcredential.UsernamePassswordCredentialStaplerClass, secret.Name)
+		credential.CreateElement("username").SetText(username)
+		credential.CreateElement("password").SetText(password)
+	case devopsv1alpha3.SecretTypeAWSAccessKey:
+		credential = newCredentialElement(doc, util.AWSCredentialStaplerClass, secret.Name)
+		credential.CreateElement("accessKey").SetText(string(secret.Data[devopsv1alpha3.AWSAccessKeyIDKey]))
+		credential.CreateElement("secretKey").SetText(string(secret.Data[devopsv1alpha3.AWSSecretAccessKeyKey]))
+		if roleArn := string(secret.Data[devopsv1alpha3.AWSIAMRoleARNKey]); roleArn != "" {
+			credential.CreateElement("iamRoleArn").SetText(roleArn)
+		}
+	default:
+		err := fmt.Errorf("error unsupport credential type")
+		return "", restful.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	doc.Indent(2)
+	return doc.WriteToString()
 }
 
-// DeleteCredentialInProject deletes credential
-//
-// Deprecated
-func (j *Jenkins) DeleteCredentialInProject(projectId, id string) (string, error) {
-	// use pkg/client/devops/jclient/credential.go instead
-	panic(nil)
+func newCredentialElement(doc *etree.Document, class, id string) *etree.Element {
+	credential := doc.CreateElement(class)
+	credential.CreateElement("scope").SetText(jcredential.GLOBALScope)
+	credential.CreateElement("id").SetText(id)
+	credential.CreateElement("description")
+	return credential
 }
@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/beevik/etree"
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/opswave/go-jenkins/devops"
+)
+
+const (
+	// GlobalCredentialDomain is the default domain of a credentials store, it cannot be changed
+	GlobalCredentialDomain = "_"
+
+	CredentialDomainTag      = "com.cloudbees.plugins.credentials.domains.Domain"
+	SpecificationsTag        = "specifications"
+	HostnameSpecificationTag = "com.cloudbees.plugins.credentials.domains.HostnameSpecification"
+	SchemeSpecificationTag   = "com.cloudbees.plugins.credentials.domains.SchemeSpecification"
+)
+
+func (j *Jenkins) CreateCredentialDomainInProject(projectId string, domain *devops.CredentialDomain) (string, error) {
+	if domain.Name == "" || domain.Name == GlobalCredentialDomain {
+		err := fmt.Errorf("invalid credential domain name '%s'", domain.Name)
+		return "", restful.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	domainXml, err := createCredentialDomainConfigXml(domain)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = j.Requester.PostXML(
+		fmt.Sprintf("/job/%s/credentials/store/folder/createDomain", projectId), domainXml, nil, nil)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return domain.Name, nil
+}
+
+func (j *Jenkins) UpdateCredentialDomainInProject(projectId string, domain *devops.CredentialDomain) (string, error) {
+	if domain.Name == GlobalCredentialDomain {
+		err := fmt.Errorf("the global credential domain cannot be updated")
+		return "", restful.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	domainXml, err := createCredentialDomainConfigXml(domain)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = j.Requester.PostXML(
+		fmt.Sprintf("/job/%s/credentials/store/folder/domain/%s/config.xml", projectId, domain.Name), domainXml, nil, nil)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return domain.Name, nil
+}
+
+func (j *Jenkins) GetCredentialDomainInProject(projectId, name string) (*devops.CredentialDomain, error) {
+	if name == GlobalCredentialDomain {
+		return &devops.CredentialDomain{Name: GlobalCredentialDomain}, nil
+	}
+
+	var domainXml string
+	_, err := j.Requester.GetXML(
+		fmt.Sprintf("/job/%s/credentials/store/folder/domain/%s/config.xml", projectId, name), &domainXml, nil)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return parseCredentialDomainConfigXml(domainXml)
+}
+
+func (j *Jenkins) DeleteCredentialDomainInProject(projectId, name string) (string, error) {
+	if name == GlobalCredentialDomain {
+		err := fmt.Errorf("the global credential domain cannot be deleted")
+		return "", restful.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	_, err := j.Requester.Post(
+		fmt.Sprintf("/job/%s/credentials/store/folder/domain/%s/doDelete", projectId, name), nil, nil, nil)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return name, nil
+}
+
+func (j *Jenkins) ListCredentialDomainsInProject(projectId string) ([]*devops.CredentialDomain, error) {
+	names, err := j.listCredentialDomainNames(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	domains := make([]*devops.CredentialDomain, 0, len(names))
+	for _, name := range names {
+		domain, err := j.GetCredentialDomainInProject(projectId, name)
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, domain)
+	}
+	return domains, nil
+}
+
+// listCredentialDomainNames returns the sorted domain names of the folder credentials store
+func (j *Jenkins) listCredentialDomainNames(projectId string) ([]string, error) {
+	responseStruct := &struct {
+		Domains map[string]interface{} `json:"domains"`
+	}{}
+	_, err := j.Requester.GetJSON(
+		fmt.Sprintf("/job/%s/credentials/store/folder", projectId),
+		responseStruct, map[string]string{
+			"depth": "1",
+		})
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+
+	names := make([]string, 0, len(responseStruct.Domains))
+	for name := range responseStruct.Domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func createCredentialDomainConfigXml(domain *devops.CredentialDomain) (string, error) {
+	doc := etree.NewDocument()
+	doc.CreateProcInst("xml", `version='1.0' encoding='UTF-8'`)
+
+	domainEle := doc.CreateElement(CredentialDomainTag)
+	domainEle.CreateAttr(PluginKey, "credentials")
+	domainEle.CreateElement("name").SetText(domain.Name)
+	domainEle.CreateElement("description").SetText(domain.Description)
+	specifications := domainEle.CreateElement(SpecificationsTag)
+	if domain.Hostname != nil {
+		hostname := specifications.CreateElement(HostnameSpecificationTag)
+		hostname.CreateElement("includes").SetText(domain.Hostname.Includes)
+		hostname.CreateElement("excludes").SetText(domain.Hostname.Excludes)
+	}
+	if domain.Scheme != nil {
+		specifications.CreateElement(SchemeSpecificationTag).CreateElement("schemes").SetText(domain.Scheme.Schemes)
+	}
+
+	doc.Indent(2)
+	return doc.WriteToString()
+}
+
+func parseCredentialDomainConfigXml(config string) (*devops.CredentialDomain, error) {
+	config = replaceXmlVersion(config, "1.1", "1.0")
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(config); err != nil {
+		return nil, err
+	}
+
+	domainEle := doc.SelectElement(CredentialDomainTag)
+	if domainEle == nil {
+		return nil, fmt.Errorf("failed to find credential domain in config")
+	}
+	domain := &devops.CredentialDomain{
+		Name:        getElementTextValueOrEmpty(domainEle, "name"),
+		Description: getElementTextValueOrEmpty(domainEle, "description"),
+	}
+	if specifications := domainEle.SelectElement(SpecificationsTag); specifications != nil {
+		if hostname := specifications.SelectElement(HostnameSpecificationTag); hostname != nil {
+			domain.Hostname = &devops.HostnameSpecification{
+				Includes: getElementTextValueOrEmpty(hostname, "includes"),
+				Excludes: getElementTextValueOrEmpty(hostname, "excludes"),
+			}
+		}
+		if scheme := specifications.SelectElement(SchemeSpecificationTag); scheme != nil {
+			domain.Scheme = &devops.SchemeSpecification{
+				Schemes: getElementTextValueOrEmpty(scheme, "schemes"),
+			}
+		}
+	}
+	return domain, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opswave/go-jenkins/devops"
+)
+
+func Test_CredentialDomainConfig(t *testing.T) {
+	inputs := []*devops.CredentialDomain{{
+		Name: "empty",
+	}, {
+		Name:        "github",
+		Description: "credentials for GitHub",
+		Hostname: &devops.HostnameSpecification{
+			Includes: "github.com,*.github.com",
+			Excludes: "gist.github.com",
+		},
+	}, {
+		Name: "ssh",
+		Scheme: &devops.SchemeSpecification{
+			Schemes: "ssh",
+		},
+	}, {
+		Name: "both",
+		Hostname: &devops.HostnameSpecification{
+			Includes: "*.example.com",
+		},
+		Scheme: &devops.SchemeSpecification{
+			Schemes: "https,ssh",
+		},
+	}}
+	for _, input := range inputs {
+		outputString, err := createCredentialDomainConfigXml(input)
+		if err != nil {
+			t.Fatalf("should not get error %v", err)
+		}
+		output, err := parseCredentialDomainConfigXml(outputString)
+		if err != nil {
+			t.Fatalf("should not get error %v", err)
+		}
+		if !reflect.DeepEqual(input, output) {
+			t.Fatalf("input [%+v] output [%+v] should equal ", input, output)
+		}
+	}
+}
+
+func TestCredentialDomainInProject(t *testing.T) {
+	domain := &devops.CredentialDomain{
+		Name:   "github",
+		Scheme: &devops.SchemeSpecification{Schemes: "https"},
+	}
+
+	t.Run("create", func(t *testing.T) {
+		jenkins, requests := newFakeJenkins(t, http.StatusOK)
+		name, err := jenkins.CreateCredentialDomainInProject("fake-project", domain)
+		assert.Nil(t, err)
+		assert.Equal(t, "github", name)
+		if assert.Len(t, *requests, 1) {
+			assert.Equal(t, "/job/fake-project/credentials/store/folder/createDomain", (*requests)[0].path)
+			assert.Contains(t, (*requests)[0].body, "<schemes>https</schemes>")
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		jenkins, requests := newFakeJenkins(t, http.StatusOK)
+		_, err := jenkins.UpdateCredentialDomainInProject("fake-project", domain)
+		assert.Nil(t, err)
+		if assert.Len(t, *requests, 1) {
+			assert.Equal(t, "/job/fake-project/credentials/store/folder/domain/github/config.xml", (*requests)[0].path)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		jenkins, requests := newFakeJenkins(t, http.StatusOK)
+		_, err := jenkins.DeleteCredentialDomainInProject("fake-project", "github")
+		assert.Nil(t, err)
+		if assert.Len(t, *requests, 1) {
+			assert.Equal(t, "/job/fake-project/credentials/store/folder/domain/github/doDelete", (*requests)[0].path)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		domainXml, _ := createCredentialDomainConfigXml(domain)
+		jenkins, _ := newFakeJenkinsWithResponses(t, http.StatusOK, map[string]string{
+			"/job/fake-project/credentials/store/folder/api/json":                  `{"domains":{"github":{},"_":{}}}`,
+			"/job/fake-project/credentials/store/folder/domain/github/config.xml/": domainXml,
+		})
+		domains, err := jenkins.ListCredentialDomainsInProject("fake-project")
+		assert.Nil(t, err)
+		assert.Equal(t, []*devops.CredentialDomain{{Name: GlobalCredentialDomain}, domain}, domains)
+	})
+
+	t.Run("global domain is read-only", func(t *testing.T) {
+		jenkins, requests := newFakeJenkins(t, http.StatusOK)
+		_, err := jenkins.CreateCredentialDomainInProject("fake-project", &devops.CredentialDomain{Name: GlobalCredentialDomain})
+		assert.NotNil(t, err)
+		_, err = jenkins.UpdateCredentialDomainInProject("fake-project", &devops.CredentialDomain{Name: GlobalCredentialDomain})
+		assert.NotNil(t, err)
+		_, err = jenkins.DeleteCredentialDomainInProject("fake-project", GlobalCredentialDomain)
+		assert.NotNil(t, err)
+		assert.Empty(t, *requests)
+	})
+}
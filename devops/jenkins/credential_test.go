@@ -0,0 +1,286 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+type recordedRequest struct {
+	method string
+	path   string
+	query  string
+	body   string
+	form   string
+}
+
+func newFakeJenkins(t *testing.T, status int) (*Jenkins, *[]recordedRequest) {
+	return newFakeJenkinsWithResponses(t, status, nil)
+}
+
+// newFakeJenkinsWithResponses starts a fake Jenkins which records all requests and
+// replies the body of the matched path in responses
+func newFakeJenkinsWithResponses(t *testing.T, status int, responses map[string]string) (*Jenkins, *[]recordedRequest) {
+	requests := &[]recordedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/crumbIssuer/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		record := recordedRequest{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, body: string(body)}
+		if values, err := ParseJenkinsQuery(string(body)); err == nil {
+			record.form = values.Get("json")
+		}
+		*requests = append(*requests, record)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(responses[r.URL.Path]))
+	}))
+	t.Cleanup(server.Close)
+	return CreateJenkins(nil, server.URL, 0, "admin", "password"), requests
+}
+
+func newFakeSecret(secretType v1.SecretType, data map[string]string) *v1.Secret {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "fake-credential", Namespace: "fake-project"},
+		Type:       secretType,
+		Data:       map[string][]byte{},
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+func TestCredentialInProject(t *testing.T) {
+	secret := newFakeSecret(devopsv1alpha3.SecretTypeBasicAuth, map[string]string{
+		devopsv1alpha3.BasicAuthUsernameKey: "admin",
+		devopsv1alpha3.BasicAuthPasswordKey: "password",
+	})
+
+	t.Run("create", func(t *testing.T) {
+		jenkins, requests := newFakeJenkins(t, http.StatusOK)
+		id, err := jenkins.CreateCredentialInProject("fake-project", secret)
+		assert.Nil(t, err)
+		assert.Equal(t, "fake-credential", id)
+		if assert.Len(t, *requests, 1) {
+			request := (*requests)[0]
+			assert.Equal(t, http.MethodPost, request.method)
+			assert.Equal(t, "/job/fake-project/credentials/store/folder/domain/_/createCredentials", request.path)
+			assert.Contains(t, request.form, `"credentials": {`)
+			assert.Contains(t, request.form, `"id":"fake-credential"`)
+			assert.Contains(t, request.form, `"username":"admin"`)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		jenkins, requests := newFakeJenkins(t, http.StatusOK)
+		id, err := jenkins.UpdateCredentialInProject("fake-project", secret)
+		assert.Nil(t, err)
+		assert.Equal(t, "fake-credential", id)
+		if assert.Len(t, *requests, 1) {
+			request := (*requests)[0]
+			assert.Equal(t, "/job/fake-project/credentials/store/folder/domain/_/credential/fake-credential/config.xml", request.path)
+			assert.Contains(t, request.body, "<password>password</password>")
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		// the usage check is covered by TestDeleteCredentialInUse
+		jenkins, requests := newFakeJenkins(t, http.StatusOK)
+		id, err := jenkins.DeleteCredentialInProject("fake-project", "fake-credential", true)
+		assert.Nil(t, err)
+		assert.Equal(t, "fake-credential", id)
+		if assert.Len(t, *requests, 1) {
+			assert.Equal(t, "/job/fake-project/credentials/store/folder/domain/_/credential/fake-credential/doDelete", (*requests)[0].path)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		jenkins, _ := newFakeJenkins(t, http.StatusNotFound)
+		_, err := jenkins.DeleteCredentialInProject("fake-project", "fake-credential", true)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		jenkins, requests := newFakeJenkins(t, http.StatusOK)
+		_, err := jenkins.CreateCredentialInProject("fake-project", newFakeSecret(v1.SecretTypeOpaque, nil))
+		assert.NotNil(t, err)
+		_, err = jenkins.UpdateCredentialInProject("fake-project", newFakeSecret(v1.SecretTypeOpaque, nil))
+		assert.NotNil(t, err)
+		assert.Empty(t, *requests)
+	})
+}
+
+func Test_createCredentialConfigXml(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret *v1.Secret
+		root   string
+		expect map[string]string
+	}{{
+		name: "basic auth",
+		secret: newFakeSecret(devopsv1alpha3.SecretTypeBasicAuth, map[string]string{
+			devopsv1alpha3.BasicAuthUsernameKey: "admin",
+			devopsv1alpha3.BasicAuthPasswordKey: "password",
+		}),
+		root: "com.cloudbees.plugins.credentials.impl.UsernamePasswordCredentialsImpl",
+		expect: map[string]string{
+			"id":       "fake-credential",
+			"scope":    "GLOBAL",
+			"username": "admin",
+			"password": "password",
+		},
+	}, {
+		name: "ssh auth",
+		secret: newFakeSecret(devopsv1alpha3.SecretTypeSSHAuth, map[string]string{
+			devopsv1alpha3.SSHAuthUsernameKey:   "git",
+			devopsv1alpha3.SSHAuthPassphraseKey: "passphrase",
+			devopsv1alpha3.SSHAuthPrivateKey:    "private <key>",
+		}),
+		root: "com.cloudbees.jenkins.plugins.sshcredentials.impl.BasicSSHUserPrivateKey",
+		expect: map[string]string{
+			"username":                    "git",
+			"passphrase":                  "passphrase",
+			"privateKeySource/privateKey": "private <key>",
+		},
+	}, {
+		name: "secret text",
+		secret: newFakeSecret(devopsv1alpha3.SecretTypeSecretText, map[string]string{
+			devopsv1alpha3.SecretTextSecretKey: "token",
+		}),
+		root:   "org.jenkinsci.plugins.plaincredentials.impl.StringCredentialsImpl",
+		expect: map[string]string{"secret": "token"},
+	}, {
+		name: "kubeconfig",
+		secret: newFakeSecret(devopsv1alpha3.SecretTypeKubeConfig, map[string]string{
+			devopsv1alpha3.KubeConfigSecretKey: "apiVersion: v1",
+		}),
+		root:   "com.microsoft.jenkins.kubernetes.credentials.KubeconfigCredentials",
+		expect: map[string]string{"kubeconfigSource/content": "apiVersion: v1"},
+	}, {
+		name: "certificate",
+		secret: newFakeSecret(devopsv1alpha3.SecretTypeCertificate, map[string]string{
+			devopsv1alpha3.CertificateKeystoreKey: "keystore",
+			devopsv1alpha3.CertificatePasswordKey: "password",
+		}),
+		root: "com.cloudbees.plugins.credentials.impl.CertificateCredentialsImpl",
+		expect: map[string]string{
+			"password":                             "password",
+			"keyStoreSource/uploadedKeystoreBytes": "a2V5c3RvcmU=",
+		},
+	}, {
+		name: "secret file",
+		secret: newFakeSecret(devopsv1alpha3.SecretTypeSecretFile, map[string]string{
+			devopsv1alpha3.SecretFileNameKey:    "settings.xml",
+			devopsv1alpha3.SecretFileContentKey: "content",
+		}),
+		root: "org.jenkinsci.plugins.plaincredentials.impl.FileCredentialsImpl",
+		expect: map[string]string{
+			"fileName":    "settings.xml",
+			"secretBytes": "Y29udGVudA==",
+		},
+	}, {
+		name: "GitHub App",
+		secret: newFakeSecret(devopsv1alpha3.SecretTypeGitHubApp, map[string]string{
+			devopsv1alpha3.GitHubAppIDKey:      "1234",
+			devopsv1alpha3.GitHubAppPrivateKey: "private key",
+			devopsv1alpha3.GitHubAppOwnerKey:   "kubesphere",
+		}),
+		root: "org.jenkinsci.plugins.github_branch_source.GitHubAppCredentials",
+		expect: map[string]string{
+			"appID":      "1234",
+			"privateKey": "private key",
+			"owner":      "kubesphere",
+		},
+	}, {
+		name: "docker registry",
+		secret: newFakeSecret(devopsv1alpha3.SecretTypeDockerRegistry, map[string]string{
+			v1.DockerConfigJsonKey: `{"auths":{"quay.io":{"username":"robot","password":"token"},"docker.io":{"auth":"YWRtaW46cGFzc3dvcmQ="}}}`,
+		}),
+		root: "com.cloudbees.plugins.credentials.impl.UsernamePasswordCredentialsImpl",
+		expect: map[string]string{
+			"username": "admin",
+			"password": "password",
+		},
+	}, {
+		name: "AWS access key",
+		secret: newFakeSecret(devopsv1alpha3.SecretTypeAWSAccessKey, map[string]string{
+			devopsv1alpha3.AWSAccessKeyIDKey:     "AKIA",
+			devopsv1alpha3.AWSSecretAccessKeyKey: "secret",
+		}),
+		root: "com.cloudbees.jenkins.plugins.awscredentials.AWSCredentialsImpl",
+		expect: map[string]string{
+			"accessKey": "AKIA",
+			"secretKey": "secret",
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credentialXml, err := createCredentialConfigXml(tt.secret)
+			assert.Nil(t, err)
+
+			doc := etree.NewDocument()
+			assert.Nil(t, doc.ReadFromString(credentialXml))
+			root := doc.SelectElement(tt.root)
+			if assert.NotNil(t, root) {
+				for path, value := range tt.expect {
+					assert.Equal(t, value, root.FindElement(path).Text(), path)
+				}
+			}
+		})
+	}
+}
+
+func TestListCredentialsInProject(t *testing.T) {
+	jenkins, _ := newFakeJenkinsWithResponses(t, http.StatusOK, map[string]string{
+		"/job/fake-project/credentials/store/folder/api/json": `{"domains":{"_":{},"github":{}}}`,
+		"/job/fake-project/credentials/store/folder/domain/_/api/json": `{"credentials":[{
+			"id":"kubeconfig","typeName":"Kubernetes configuration (kubeconfig)","displayName":"kubeconfig","description":"cluster",
+			"fingerprint":{"hash":"abc","usage":[{"name":"fake-project/demo","ranges":{"ranges":[{"start":1,"end":3}]}}]}}]}`,
+		"/job/fake-project/credentials/store/folder/domain/github/api/json": `{"credentials":[{"id":"token","typeName":"Custom"}]}`,
+	})
+
+	credentials, err := jenkins.ListCredentialsInProject("fake-project")
+	assert.Nil(t, err)
+	if assert.Len(t, credentials, 2) {
+		assert.Equal(t, "kubeconfig", credentials[0].Id)
+		assert.Equal(t, "kubeconfig", credentials[0].Type)
+		assert.Equal(t, "kubeconfig", credentials[0].DisplayName)
+		assert.Equal(t, "cluster", credentials[0].Description)
+		assert.Equal(t, "_", credentials[0].Domain)
+		if assert.NotNil(t, credentials[0].Fingerprint) && assert.Len(t, credentials[0].Fingerprint.Usage, 1) {
+			assert.Equal(t, "fake-project/demo", credentials[0].Fingerprint.Usage[0].Name)
+			assert.Equal(t, 3, credentials[0].Fingerprint.Usage[0].Ranges.Ranges[0].End)
+		}
+
+		assert.Equal(t, "token", credentials[1].Id)
+		assert.Equal(t, "Custom", credentials[1].Type)
+		assert.Equal(t, "github", credentials[1].Domain)
+	}
+}
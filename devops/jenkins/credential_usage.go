@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/opswave/go-jenkins/devops"
+	"github.com/opswave/go-jenkins/devops/util"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+func (j *Jenkins) GetCredentialUsageInProject(projectId string) (map[string]*devops.CredentialUsage, error) {
+	usages, unreadable, err := j.credentialUsageInProject(projectId)
+	if err != nil {
+		return nil, err
+	}
+	for _, usage := range usages {
+		usage.UnreadablePipelines = unreadable
+	}
+	return usages, nil
+}
+
+// credentialUsageInProject returns the usage of the credentials, and the sorted names of the pipelines which could
+// not be read, the credentials might be referenced by them as well
+func (j *Jenkins) credentialUsageInProject(projectId string) (map[string]*devops.CredentialUsage, []string, error) {
+	usages := map[string]*devops.CredentialUsage{}
+	getUsage := func(id string) *devops.CredentialUsage {
+		if _, ok := usages[id]; !ok {
+			usages[id] = &devops.CredentialUsage{Id: id}
+		}
+		return usages[id]
+	}
+
+	pipelines, unreadable, err := j.listProjectPipelines(projectId)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, pipeline := range pipelines {
+		for id, sources := range util.FindCredentialReferences(pipeline) {
+			usage := getUsage(id)
+			for _, source := range sources {
+				usage.References = append(usage.References, devops.CredentialReference{
+					Pipeline: pipeline.Name,
+					Source:   source,
+				})
+			}
+		}
+	}
+
+	credentials, err := j.ListCredentialsInProject(projectId)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, credential := range credentials {
+		if credential.Fingerprint == nil {
+			continue
+		}
+		for _, jobUsage := range credential.Fingerprint.Usage {
+			// the end of a range is exclusive
+			lastBuild := 0
+			for _, buildRange := range jobUsage.Ranges.Ranges {
+				if buildRange.End-1 > lastBuild {
+					lastBuild = buildRange.End - 1
+				}
+			}
+			if lastBuild > 0 {
+				usage := getUsage(credential.Id)
+				usage.Builds = append(usage.Builds, devops.CredentialBuildUsage{
+					Name:      jobUsage.Name,
+					LastBuild: lastBuild,
+				})
+			}
+		}
+	}
+
+	for _, usage := range usages {
+		sort.Slice(usage.References, func(i, k int) bool {
+			if usage.References[i].Pipeline != usage.References[k].Pipeline {
+				return usage.References[i].Pipeline < usage.References[k].Pipeline
+			}
+			return usage.References[i].Source < usage.References[k].Source
+		})
+		sort.Slice(usage.Builds, func(i, k int) bool {
+			return usage.Builds[i].Name < usage.Builds[k].Name
+		})
+	}
+	return usages, unreadablePipelineNames(unreadable), nil
+}
+
+// CheckCredentialUnusedInProject returns a conflict error which lists the users of the credential if it is in use,
+// the check is incomplete if some pipelines could not be read, they are logged instead of failing the check
+func (j *Jenkins) CheckCredentialUnusedInProject(projectId, id string) error {
+	usages, unreadable, err := j.credentialUsageInProject(projectId)
+	if err != nil {
+		return err
+	}
+	if usage := usages[id]; usage.InUse() {
+		var users []string
+		for _, reference := range usage.References {
+			users = append(users, reference.Pipeline)
+		}
+		for _, build := range usage.Builds {
+			users = append(users, build.Name)
+		}
+		err := fmt.Errorf("credential [%s] is in use by %s", id, strings.Join(users, ", "))
+		return restful.NewError(http.StatusConflict, err.Error())
+	}
+	if len(unreadable) > 0 {
+		klog.Warningf("the usage check of credential [%s] is incomplete, pipelines %s in project %s could not be read",
+			id, strings.Join(unreadable, ", "), projectId)
+	}
+	return nil
+}
+
+// listProjectPipelines returns the configuration of all pipelines in a project, other jobs are ignored.
+// The pipelines which cannot be read are skipped, they are returned with the errors keyed by their names
+func (j *Jenkins) listProjectPipelines(projectId string) ([]*devopsv1alpha3.Pipeline, map[string]error, error) {
+	folder, err := j.GetFolder(projectId)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+
+	var pipelines []*devopsv1alpha3.Pipeline
+	unreadable := map[string]error{}
+	for _, job := range folder.Raw.Jobs {
+		if !isPipelineJobClass(job.Class) {
+			continue
+		}
+		pipeline, err := j.GetProjectPipelineConfig(projectId, job.Name)
+		if err != nil {
+			klog.Warningf("skip pipeline %s/%s which cannot be read: %v", projectId, job.Name, err)
+			unreadable[job.Name] = err
+			continue
+		}
+		pipeline.Name = job.Name
+		pipelines = append(pipelines, pipeline)
+	}
+	return pipelines, unreadable, nil
+}
+
+// unreadablePipelineNames returns the sorted names of the pipelines which cannot be read
+func unreadablePipelineNames(unreadable map[string]error) []string {
+	var names []string
+	for name := range unreadable {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
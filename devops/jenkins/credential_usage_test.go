@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+func newFakeProjectResponses(t *testing.T) map[string]string {
+	noScmConfig, err := createPipelineConfigXml(&devopsv1alpha3.NoScmPipeline{
+		Name:        "deploy",
+		Jenkinsfile: `withCredentials([kubeconfigContent(credentialsId: 'kubeconfig', variable: 'VARIABLE')]) { sh 'kubectl apply' }`,
+	})
+	assert.Nil(t, err)
+	multiBranchConfig, err := createMultiBranchPipelineConfigXml("fake-project", &devopsv1alpha3.MultiBranchPipeline{
+		Name:       "build",
+		SourceType: devopsv1alpha3.SourceTypeGit,
+		GitSource:  &devopsv1alpha3.GitSource{Url: "https://github.com/kubesphere/ks-devops", CredentialId: "github"},
+	})
+	assert.Nil(t, err)
+
+	return map[string]string{
+		"/job/fake-project/api/json": `{"jobs":[
+			{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"deploy"},
+			{"_class":"org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject","name":"build"},
+			{"_class":"hudson.model.FreeStyleProject","name":"legacy"}]}`,
+		"/job/fake-project/job/deploy/api/json":               `{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"deploy"}`,
+		"/job/fake-project/job/deploy/config.xml/":            noScmConfig,
+		"/job/fake-project/job/build/api/json":                `{"_class":"org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject","name":"build"}`,
+		"/job/fake-project/job/build/config.xml/":             multiBranchConfig,
+		"/job/fake-project/credentials/store/folder/api/json": `{"domains":{"_":{}}}`,
+		"/job/fake-project/credentials/store/folder/domain/_/api/json": `{"credentials":[
+			{"id":"kubeconfig","fingerprint":{"usage":[{"name":"fake-project/deploy","ranges":{"ranges":[{"start":1,"end":3},{"start":5,"end":8}]}}]}},
+			{"id":"docker","fingerprint":{"usage":[{"name":"fake-project/build/master","ranges":{"ranges":[{"start":2,"end":3}]}}]}},
+			{"id":"unused","fingerprint":null}]}`,
+	}
+}
+
+// newFakeUnreadableProjectResponses adds the pipeline broken whose config cannot be parsed to the fake project
+func newFakeUnreadableProjectResponses(t *testing.T) map[string]string {
+	responses := newFakeProjectResponses(t)
+	responses["/job/fake-project/api/json"] = `{"jobs":[
+			{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"deploy"},
+			{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"broken"},
+			{"_class":"org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject","name":"build"},
+			{"_class":"hudson.model.FreeStyleProject","name":"legacy"}]}`
+	responses["/job/fake-project/job/broken/api/json"] = `{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"broken"}`
+	responses["/job/fake-project/job/broken/config.xml/"] = `<flow-definition plugin="workflow-job">`
+	return responses
+}
+
+func TestGetCredentialUsageInProject(t *testing.T) {
+	jenkins, _ := newFakeJenkinsWithResponses(t, http.StatusOK, newFakeProjectResponses(t))
+
+	usages, err := jenkins.GetCredentialUsageInProject("fake-project")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]*devops.CredentialUsage{
+		"kubeconfig": {
+			Id:         "kubeconfig",
+			References: []devops.CredentialReference{{Pipeline: "deploy", Source: "jenkinsfile"}},
+			Builds:     []devops.CredentialBuildUsage{{Name: "fake-project/deploy", LastBuild: 7}},
+		},
+		"github": {
+			Id:         "github",
+			References: []devops.CredentialReference{{Pipeline: "build", Source: "git_source"}},
+		},
+		"docker": {
+			Id:     "docker",
+			Builds: []devops.CredentialBuildUsage{{Name: "fake-project/build/master", LastBuild: 2}},
+		},
+	}, usages)
+
+	t.Run("unreadable pipeline", func(t *testing.T) {
+		jenkins, _ := newFakeJenkinsWithResponses(t, http.StatusOK, newFakeUnreadableProjectResponses(t))
+
+		usages, err := jenkins.GetCredentialUsageInProject("fake-project")
+		assert.Nil(t, err)
+		assert.Equal(t, []devops.CredentialReference{{Pipeline: "build", Source: "git_source"}}, usages["github"].References)
+		for _, usage := range usages {
+			assert.Equal(t, []string{"broken"}, usage.UnreadablePipelines)
+		}
+	})
+}
+
+func TestDeleteCredentialInUse(t *testing.T) {
+	deleted := func(requests []recordedRequest, id string) bool {
+		for _, request := range requests {
+			if request.method == http.MethodPost && request.path == "/job/fake-project/credentials/store/folder/domain/_/credential/"+id+"/doDelete" {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("in use", func(t *testing.T) {
+		jenkins, requests := newFakeJenkinsWithResponses(t, http.StatusOK, newFakeProjectResponses(t))
+		_, err := jenkins.DeleteCredentialInProject("fake-project", "github", false)
+		assert.NotNil(t, err)
+		if serviceErr, ok := err.(restful.ServiceError); assert.True(t, ok) {
+			assert.Equal(t, http.StatusConflict, serviceErr.Code)
+		}
+		assert.Contains(t, err.Error(), "build")
+		assert.False(t, deleted(*requests, "github"))
+	})
+
+	t.Run("not in use", func(t *testing.T) {
+		jenkins, requests := newFakeJenkinsWithResponses(t, http.StatusOK, newFakeProjectResponses(t))
+		id, err := jenkins.DeleteCredentialInProject("fake-project", "unused", false)
+		assert.Nil(t, err)
+		assert.Equal(t, "unused", id)
+		assert.True(t, deleted(*requests, "unused"))
+	})
+
+	t.Run("unreadable pipeline", func(t *testing.T) {
+		jenkins, requests := newFakeJenkinsWithResponses(t, http.StatusOK, newFakeUnreadableProjectResponses(t))
+		_, err := jenkins.DeleteCredentialInProject("fake-project", "github", false)
+		if serviceErr, ok := err.(restful.ServiceError); assert.True(t, ok) {
+			assert.Equal(t, http.StatusConflict, serviceErr.Code)
+		}
+
+		// the check is incomplete, but it does not block the deletion
+		id, err := jenkins.DeleteCredentialInProject("fake-project", "unused", false)
+		assert.Nil(t, err)
+		assert.Equal(t, "unused", id)
+		assert.True(t, deleted(*requests, "unused"))
+	})
+
+	t.Run("force", func(t *testing.T) {
+		jenkins, requests := newFakeJenkinsWithResponses(t, http.StatusOK, newFakeProjectResponses(t))
+		_, err := jenkins.DeleteCredentialInProject("fake-project", "github", true)
+		assert.Nil(t, err)
+		assert.True(t, deleted(*requests, "github"))
+	})
+}
@@ -0,0 +1,410 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cron parses the cron specs of the Jenkins triggers offline. The syntax and the hashing of H fields
+// follow hudson.scheduler.CronTab, so the fire times are the same as the ones computed by Jenkins.
+package cron
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	minuteField = iota
+	hourField
+	dayOfMonthField
+	monthField
+	dayOfWeekField
+	fieldCount
+)
+
+var (
+	fieldNames  = [fieldCount]string{"minute", "hour", "day of month", "month", "day of week"}
+	lowerBounds = [fieldCount]int{0, 0, 1, 1, 0}
+	upperBounds = [fieldCount]int{59, 23, 31, 12, 7}
+
+	aliases = map[string]string{
+		"@yearly":   "H H H H *",
+		"@annually": "H H H H *",
+		"@monthly":  "H H H * *",
+		"@weekly":   "H H * * H",
+		"@daily":    "H H * * *",
+		"@midnight": "H H(0-2) * * *",
+		"@hourly":   "H * * * *",
+	}
+
+	hashRangePattern = regexp.MustCompile(`^H\((\d+)-(\d+)\)$`)
+	periodPattern    = regexp.MustCompile(`^0(,(\d+)(,\d+)*)( .+)$`)
+)
+
+// the fire times are searched in this number of days, it covers the schedules on February 29
+const searchDays = 366 * 8
+
+// Schedule is a parsed cron spec, which is made of one cron tab per line
+type Schedule struct {
+	location *time.Location
+	tabs     []*cronTab
+}
+
+type cronTab struct {
+	spec string
+	bits [fieldCount]uint64
+}
+
+// Parse parses a cron spec of Jenkins. The seed is the full name of the job, such as project/pipeline, which is used
+// to hash the H fields, the lowest values are taken if it is empty. Blank lines and lines starting with # are
+// ignored, the first line could be TZ=<time zone> to set the time zone, the local time zone is used by default.
+func Parse(spec, seed string) (*Schedule, error) {
+	schedule := &Schedule{location: time.Local}
+	h := newHash(seed)
+	for i, line := range strings.Split(spec, "\n") {
+		line = strings.TrimSpace(line)
+		if i == 0 && strings.HasPrefix(line, "TZ=") {
+			location, err := time.LoadLocation(strings.TrimPrefix(line, "TZ="))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid time zone %s", i+1, strings.TrimPrefix(line, "TZ="))
+			}
+			schedule.location = location
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tab, err := parseCronTab(line, h)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		schedule.tabs = append(schedule.tabs, tab)
+	}
+	return schedule, nil
+}
+
+// Location returns the time zone of the schedule
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// Next returns the first fire time after t, it is zero if the schedule never fires
+func (s *Schedule) Next(t time.Time) time.Time {
+	var next time.Time
+	for _, tab := range s.tabs {
+		if candidate := tab.next(t, s.location); !candidate.IsZero() && (next.IsZero() || candidate.Before(next)) {
+			next = candidate
+		}
+	}
+	return next
+}
+
+// NextN returns at most n fire times after t
+func (s *Schedule) NextN(t time.Time, n int) []time.Time {
+	var times []time.Time
+	for len(times) < n {
+		if t = s.Next(t); t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+// Prev returns the last fire time at or before t, it is zero if the schedule never fires
+func (s *Schedule) Prev(t time.Time) time.Time {
+	var prev time.Time
+	for _, tab := range s.tabs {
+		if candidate := tab.prev(t, s.location); !candidate.IsZero() && candidate.After(prev) {
+			prev = candidate
+		}
+	}
+	return prev
+}
+
+// Warning returns the first suspicious line of the schedule, such as firing every minute, it is the same as
+// hudson.scheduler.CronTabList#checkSanity
+func (s *Schedule) Warning() string {
+	for _, tab := range s.tabs {
+		if warning := tab.checkSanity(); warning != "" {
+			return warning
+		}
+	}
+	return ""
+}
+
+func parseCronTab(spec string, h hash) (*cronTab, error) {
+	if alias, ok := aliases[spec]; ok {
+		tab, err := parseCronTab(alias, h)
+		if err != nil {
+			return nil, err
+		}
+		tab.spec = spec
+		return tab, nil
+	} else if strings.HasPrefix(spec, "@") {
+		return nil, fmt.Errorf("unknown alias %s", spec)
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != fieldCount {
+		return nil, fmt.Errorf("%d fields are expected but found %d in %q", fieldCount, len(fields), spec)
+	}
+	tab := &cronTab{spec: spec}
+	for field, expr := range fields {
+		bits, err := parseField(expr, field, h)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s field %q: %v", fieldNames[field], expr, err)
+		}
+		tab.bits[field] = bits
+	}
+	// both 0 and 7 are Sunday
+	if tab.bits[dayOfWeekField]&(1<<7) != 0 {
+		tab.bits[dayOfWeekField] = tab.bits[dayOfWeekField]&^(1<<7) | 1
+	}
+	return tab, nil
+}
+
+// parseField parses the comma separated terms of a field, the terms are parsed from left to right since the order
+// of hashing matters
+func parseField(expr string, field int, h hash) (uint64, error) {
+	var bits uint64
+	for _, term := range strings.Split(expr, ",") {
+		termBits, err := parseTerm(term, field, h)
+		if err != nil {
+			return 0, err
+		}
+		bits |= termBits
+	}
+	return bits, nil
+}
+
+func parseTerm(term string, field int, h hash) (uint64, error) {
+	step := 1
+	hasStep := false
+	if index := strings.Index(term, "/"); index >= 0 {
+		value, err := strconv.Atoi(term[index+1:])
+		if err != nil {
+			return 0, fmt.Errorf("invalid step %q", term[index+1:])
+		}
+		if value <= 0 {
+			return 0, fmt.Errorf("step must be positive, but found %d", value)
+		}
+		step, hasStep = value, true
+		term = term[:index]
+	}
+
+	switch {
+	case term == "*":
+		return rangeBits(lowerBounds[field], upperBounds[field], step), nil
+	case term == "H":
+		upper := upperBounds[field]
+		switch field {
+		case dayOfMonthField:
+			// the number of days varies among months, so 28 is always safe
+			upper = 28
+		case dayOfWeekField:
+			// both 0 and 7 are Sunday, limit to 6 for a better distribution
+			upper = 6
+		}
+		return hashBits(lowerBounds[field], upper, step, field, h)
+	case strings.HasPrefix(term, "H"):
+		match := hashRangePattern.FindStringSubmatch(term)
+		if match == nil {
+			return 0, fmt.Errorf("invalid term %q", term)
+		}
+		start, _ := strconv.Atoi(match[1])
+		end, _ := strconv.Atoi(match[2])
+		if err := checkRange(start, end, field); err != nil {
+			return 0, err
+		}
+		return hashBits(start, end, step, field, h)
+	}
+
+	start, end := term, term
+	if index := strings.Index(term, "-"); index >= 0 {
+		start, end = term[:index], term[index+1:]
+	} else if hasStep {
+		return 0, fmt.Errorf("step is only allowed with a range, such as 0-59/%d", step)
+	}
+	startValue, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", start)
+	}
+	endValue, err := strconv.Atoi(end)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", end)
+	}
+	if err = checkRange(startValue, endValue, field); err != nil {
+		return 0, err
+	}
+	return rangeBits(startValue, endValue, step), nil
+}
+
+func checkRange(start, end, field int) error {
+	for _, value := range []int{start, end} {
+		if value < lowerBounds[field] || value > upperBounds[field] {
+			return fmt.Errorf("%d is an invalid value, must be within %d and %d", value, lowerBounds[field], upperBounds[field])
+		}
+	}
+	if start > end {
+		return fmt.Errorf("%d-%d is an invalid range, you mean %d-%d?", start, end, end, start)
+	}
+	return nil
+}
+
+func rangeBits(start, end, step int) uint64 {
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << uint(i)
+	}
+	return bits
+}
+
+// hashBits picks one value in the range if there is no step, otherwise the start of the steps is hashed
+func hashBits(start, end, step, field int, h hash) (uint64, error) {
+	if step > end-start+1 {
+		return 0, fmt.Errorf("step must be less than or equal to %d, the size of the range %d-%d of %s",
+			end-start+1, start, end, fieldNames[field])
+	}
+	if step > 1 {
+		return rangeBits(start+h.next(step), end, step), nil
+	}
+	return 1 << uint(start+h.next(end+1-start)), nil
+}
+
+func (c *cronTab) has(field, value int) bool {
+	return c.bits[field]&(1<<uint(value)) != 0
+}
+
+func (c *cronTab) matchDay(day time.Time) bool {
+	return c.has(monthField, int(day.Month())) && c.has(dayOfMonthField, day.Day()) &&
+		c.has(dayOfWeekField, int(day.Weekday()))
+}
+
+// next searches day by day, the times skipped by daylight saving time are ignored
+func (c *cronTab) next(t time.Time, location *time.Location) time.Time {
+	t = t.In(location)
+	year, month, day := t.Date()
+	for i := 0; i < searchDays; i++ {
+		// noon always exists, even if the midnight is skipped by daylight saving time
+		date := time.Date(year, month, day+i, 12, 0, 0, 0, location)
+		if !c.matchDay(date) {
+			continue
+		}
+		for hour := 0; hour < 24; hour++ {
+			if !c.has(hourField, hour) || (i == 0 && hour < t.Hour()) {
+				continue
+			}
+			for minute := 0; minute < 60; minute++ {
+				if !c.has(minuteField, minute) {
+					continue
+				}
+				candidate := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, location)
+				if candidate.Hour() == hour && candidate.Minute() == minute && candidate.After(t) {
+					return candidate
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cronTab) prev(t time.Time, location *time.Location) time.Time {
+	t = t.In(location)
+	year, month, day := t.Date()
+	for i := 0; i < searchDays; i++ {
+		date := time.Date(year, month, day-i, 12, 0, 0, 0, location)
+		if !c.matchDay(date) {
+			continue
+		}
+		for hour := 23; hour >= 0; hour-- {
+			if !c.has(hourField, hour) || (i == 0 && hour > t.Hour()) {
+				continue
+			}
+			for minute := 59; minute >= 0; minute-- {
+				if !c.has(minuteField, minute) {
+					continue
+				}
+				candidate := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, location)
+				if candidate.Hour() == hour && candidate.Minute() == minute && !candidate.After(t) {
+					return candidate
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cronTab) checkSanity() string {
+outer:
+	for field := 0; field < fieldCount; field++ {
+		for value := lowerBounds[field]; value <= upperBounds[field]; value++ {
+			if !c.has(field, value) {
+				// if there is a sparse field, the minute field should be one of them
+				if field > minuteField {
+					return fmt.Sprintf("Do you really mean \"every minute\" when you say \"%s\"? Perhaps you meant \"H %s\"",
+						c.spec, c.spec[strings.IndexAny(c.spec, " \t")+1:])
+				}
+				break outer
+			}
+		}
+	}
+
+	daysOfMonth := 0
+	for day := 1; day < 31; day++ {
+		if c.has(dayOfMonthField, day) {
+			daysOfMonth++
+		}
+	}
+	if daysOfMonth > 5 && daysOfMonth < 28 {
+		return "Short cycles in the day of month field will behave oddly near the end of a month"
+	}
+
+	if hashed := hashify(c.spec); hashed != "" {
+		return fmt.Sprintf("To allow periodically scheduled tasks to produce even load on the system, "+
+			"the symbol H (for \"hash\") should be used wherever possible. Spread load evenly by using \"%s\" rather than \"%s\"",
+			hashed, c.spec)
+	}
+	return ""
+}
+
+// hashify returns the spec using H instead of the fixed minutes, it is empty if there is no suggestion
+func hashify(spec string) string {
+	if strings.Contains(spec, "H") {
+		return ""
+	}
+	if strings.HasPrefix(spec, "*/") {
+		return "H" + spec[1:]
+	}
+	if index := strings.Index(spec, " "); index > 0 {
+		if _, err := strconv.Atoi(spec[:index]); err == nil {
+			return "H " + spec[index+1:]
+		}
+	}
+	if match := periodPattern.FindStringSubmatch(spec); match != nil {
+		period, _ := strconv.Atoi(match[2])
+		if period <= 0 || period > 30 {
+			return ""
+		}
+		for i := period * 2; i < 60; i += period {
+			if !strings.Contains(match[3], ","+strconv.Itoa(i)) {
+				return ""
+			}
+		}
+		return "H/" + strconv.Itoa(period) + match[4]
+	}
+	return ""
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJavaRandom(t *testing.T) {
+	// the well known values of new java.util.Random(seed).nextInt()
+	assert.Equal(t, int32(-1155484576), newJavaRandom(0).nextBits(32))
+	assert.Equal(t, int32(-1170105035), newJavaRandom(42).nextBits(32))
+
+	random := newJavaRandom(42)
+	for i := 0; i < 1000; i++ {
+		value := random.next(60)
+		assert.True(t, value >= 0 && value < 60)
+	}
+}
+
+func TestParse_Hash(t *testing.T) {
+	first, err := Parse("H H * * *", "project/pipeline")
+	assert.Nil(t, err)
+	second, err := Parse("H H * * *", "project/pipeline")
+	assert.Nil(t, err)
+	assert.Equal(t, first.tabs, second.tabs)
+
+	// the lowest values are taken without seed
+	schedule, err := Parse("H H(2-4) H * H", "")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), schedule.tabs[0].bits[minuteField])
+	assert.Equal(t, uint64(1<<2), schedule.tabs[0].bits[hourField])
+	assert.Equal(t, uint64(1<<1), schedule.tabs[0].bits[dayOfMonthField])
+	assert.Equal(t, uint64(1), schedule.tabs[0].bits[dayOfWeekField])
+
+	schedule, err = Parse("H/15 * * * *", "project/pipeline")
+	assert.Nil(t, err)
+	minutes := schedule.tabs[0].bits[minuteField]
+	count := 0
+	for minute := 0; minute < 60; minute++ {
+		if minutes&(1<<uint(minute)) != 0 {
+			count++
+			assert.True(t, minutes&(1<<uint((minute+15)%60)) != 0, "every 15 minutes")
+		}
+	}
+	assert.Equal(t, 4, count)
+
+	// the H of day of month is within 1-28
+	for _, seed := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		schedule, err = Parse("H H H * *", seed)
+		assert.Nil(t, err)
+		assert.True(t, schedule.tabs[0].bits[dayOfMonthField] < 1<<29)
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	now := time.Date(2024, 2, 27, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		spec     string
+		expected []time.Time
+	}{{
+		spec: "TZ=UTC\n15 10-12/2 * * *",
+		expected: []time.Time{
+			time.Date(2024, 2, 27, 12, 15, 0, 0, time.UTC),
+			time.Date(2024, 2, 28, 10, 15, 0, 0, time.UTC),
+			time.Date(2024, 2, 28, 12, 15, 0, 0, time.UTC),
+		},
+	}, {
+		spec: "TZ=UTC\n# leap day\n0 0 29 2 *",
+		expected: []time.Time{
+			time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+	}, {
+		// both 0 and 7 are Sunday, and all the fields must match
+		spec: "TZ=UTC\n0 8 1-7 * 7\n30 10 * * 2",
+		expected: []time.Time{
+			time.Date(2024, 3, 3, 8, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 5, 10, 30, 0, 0, time.UTC),
+		},
+	}, {
+		spec: "TZ=Asia/Shanghai\n0 9 * * *",
+		expected: []time.Time{
+			time.Date(2024, 2, 28, 1, 0, 0, 0, time.UTC),
+		},
+	}, {
+		spec: "TZ=UTC\n0 0 31 2 *",
+	}}
+	for _, test := range tests {
+		schedule, err := Parse(test.spec, "")
+		assert.Nil(t, err, test.spec)
+		times := schedule.NextN(now, len(test.expected))
+		assert.Equal(t, len(test.expected), len(times), test.spec)
+		for i := range times {
+			assert.True(t, test.expected[i].Equal(times[i]), "%s: %v", test.spec, times[i])
+		}
+	}
+
+	schedule, err := Parse("TZ=UTC\n15 10-12/2 * * *", "")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 2, 27, 10, 15, 0, 0, time.UTC), schedule.Prev(now))
+	assert.Equal(t, time.Date(2024, 2, 27, 10, 15, 0, 0, time.UTC), schedule.Prev(time.Date(2024, 2, 27, 10, 15, 0, 0, time.UTC)))
+	assert.True(t, schedule.Next(time.Date(2024, 2, 27, 12, 15, 0, 0, time.UTC)).After(time.Date(2024, 2, 27, 12, 15, 0, 0, time.UTC)))
+
+	// the time skipped by daylight saving time never fires
+	schedule, err = Parse("TZ=Europe/Berlin\n30 2 * * *", "")
+	assert.Nil(t, err)
+	next := schedule.Next(time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC))
+	assert.True(t, time.Date(2024, 4, 1, 0, 30, 0, 0, time.UTC).Equal(next), next.String())
+}
+
+func TestParse_Aliases(t *testing.T) {
+	for alias, spec := range aliases {
+		fromAlias, err := Parse(alias, "project/pipeline")
+		assert.Nil(t, err)
+		fromSpec, err := Parse(spec, "project/pipeline")
+		assert.Nil(t, err)
+		assert.Equal(t, fromSpec.tabs[0].bits, fromAlias.tabs[0].bits, alias)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, spec := range []string{
+		"* * * *",
+		"60 * * * *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"H/61 * * * *",
+		"5/2 * * * *",
+		"a * * * *",
+		"H(1-100) * * * *",
+		"@often",
+		"TZ=Nowhere/Unknown\nH * * * *",
+	} {
+		_, err := Parse(spec, "")
+		assert.NotNil(t, err, spec)
+	}
+	_, err := Parse("H * * * *\n* * * * * *", "")
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestSchedule_Warning(t *testing.T) {
+	for spec, warning := range map[string]string{
+		"H * * * *":       "",
+		"@daily":          "",
+		"* * * * *":       `Do you really mean "every minute" when you say "* * * * *"? Perhaps you meant "H * * * *"`,
+		"* 1 * * *":       `Perhaps you meant "H 1 * * *"`,
+		"H H 1-10 * *":    "Short cycles",
+		"0 1 * * *":       `Spread load evenly by using "H 1 * * *" rather than "0 1 * * *"`,
+		"*/5 * * * *":     `Spread load evenly by using "H/5 * * * *" rather than "*/5 * * * *"`,
+		"0,20,40 * * * *": `Spread load evenly by using "H/20 * * * *" rather than "0,20,40 * * * *"`,
+	} {
+		schedule, err := Parse(spec, "")
+		assert.Nil(t, err)
+		if warning == "" {
+			assert.Empty(t, schedule.Warning(), spec)
+		} else {
+			assert.Contains(t, schedule.Warning(), warning, spec)
+		}
+	}
+}
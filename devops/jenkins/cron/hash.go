@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+)
+
+// hash picks the values of the H fields, the same seed always gives the same values
+type hash interface {
+	next(n int) int
+}
+
+// zeroHash is used when there is no seed, every H field takes its lowest value
+type zeroHash struct{}
+
+func (zeroHash) next(int) int {
+	return 0
+}
+
+// newHash returns the hash used by Jenkins for a job, the seed is the full name of the job.
+// It is the same as hudson.scheduler.Hash#from, so the H fields resolve to the values used by Jenkins
+func newHash(seed string) hash {
+	if seed == "" {
+		return zeroHash{}
+	}
+	digest := md5.Sum([]byte(seed))
+	for i := 8; i < len(digest); i++ {
+		digest[i%8] ^= digest[i]
+	}
+	return newJavaRandom(int64(binary.BigEndian.Uint64(digest[:8])))
+}
+
+// javaRandom is the linear congruential generator of java.util.Random
+type javaRandom struct {
+	seed uint64
+}
+
+const (
+	javaRandomMultiplier = 0x5DEECE66D
+	javaRandomMask       = 1<<48 - 1
+)
+
+func newJavaRandom(seed int64) *javaRandom {
+	return &javaRandom{seed: (uint64(seed) ^ javaRandomMultiplier) & javaRandomMask}
+}
+
+func (r *javaRandom) nextBits(bits uint) int32 {
+	r.seed = (r.seed*javaRandomMultiplier + 0xB) & javaRandomMask
+	return int32(r.seed >> (48 - bits))
+}
+
+// next returns a value in [0, n), it is java.util.Random#nextInt(int)
+func (r *javaRandom) next(n int) int {
+	bound := int32(n)
+	value := r.nextBits(31)
+	m := bound - 1
+	if bound&m == 0 {
+		return int((int64(bound) * int64(value)) >> 31)
+	}
+	for u := value; ; u = r.nextBits(31) {
+		value = u % bound
+		// the overflow of int32 is expected here
+		if u-value+m >= 0 {
+			return int(value)
+		}
+	}
+}
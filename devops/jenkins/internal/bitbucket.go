@@ -64,25 +64,8 @@ func AppendBitbucketServerSourceToEtree(source *etree.Element, gitSource *devops
 	if gitSource.DiscoverTags {
 		traits.CreateElement("com.cloudbees.jenkins.plugins.bitbucket.TagDiscoveryTrait")
 	}
-	if gitSource.CloneOption != nil {
-		cloneExtension := traits.CreateElement("jenkins.plugins.git.traits.CloneOptionTrait").CreateElement("extension")
-		cloneExtension.CreateAttr("class", "hudson.plugins.git.extensions.impl.CloneOption")
-		cloneExtension.CreateElement("shallow").SetText(strconv.FormatBool(gitSource.CloneOption.Shallow))
-		cloneExtension.CreateElement("noTags").SetText(strconv.FormatBool(false))
-		cloneExtension.CreateElement("honorRefspec").SetText(strconv.FormatBool(true))
-		cloneExtension.CreateElement("reference")
-		if gitSource.CloneOption.Timeout >= 0 {
-			cloneExtension.CreateElement("timeout").SetText(strconv.Itoa(gitSource.CloneOption.Timeout))
-		} else {
-			cloneExtension.CreateElement("timeout").SetText(strconv.Itoa(10))
-		}
-
-		if gitSource.CloneOption.Depth >= 0 {
-			cloneExtension.CreateElement("depth").SetText(strconv.Itoa(gitSource.CloneOption.Depth))
-		} else {
-			cloneExtension.CreateElement("depth").SetText(strconv.Itoa(1))
-		}
-	}
+	appendCloneTrait(traits, gitSource.CloneOption)
+	appendCheckoutTraits(traits, gitSource.CheckoutOption)
 	if gitSource.RegexFilter != "" {
 		regexTraits := traits.CreateElement("jenkins.scm.impl.trait.RegexSCMHeadFilterTrait")
 		regexTraits.CreateAttr("plugin", "scm-api")
@@ -140,20 +123,139 @@ func GetBitbucketServerSourceFromEtree(source *etree.Element) *devopsv1alpha3.Bi
 				klog.Warningf("invalid Bitbucket discover PR trust value: %s", trust[1])
 			}
 		}
+	}
+
+	s.CloneOption = parseFromCloneTrait(traits.SelectElement("jenkins.plugins.git.traits.CloneOptionTrait"))
+	s.CheckoutOption = parseFromCheckoutTraits(traits)
 
-		s.CloneOption = parseFromCloneTrait(traits.SelectElement("jenkins.plugins.git.traits.CloneOptionTrait"))
+	if regexTrait := traits.SelectElement(
+		"jenkins.scm.impl.trait.RegexSCMHeadFilterTrait"); regexTrait != nil {
+		if regex := regexTrait.SelectElement("regex"); regex != nil {
+			s.RegexFilter = regex.Text()
+		}
+	}
 
-		if regexTrait := traits.SelectElement(
-			"jenkins.scm.impl.trait.RegexSCMHeadFilterTrait"); regexTrait != nil {
-			if regex := regexTrait.SelectElement("regex"); regex != nil {
-				s.RegexFilter = regex.Text()
+	if skipNotificationTrait := traits.SelectElement(
+		"com.cloudbees.jenkins.plugins.bitbucket.notifications.SkipNotificationsTrait"); skipNotificationTrait == nil {
+		s.AcceptJenkinsNotification = true
+	}
+	return &s
+}
+
+// AppendBitbucketCloudSourceToEtree uses the same SCM source class as Bitbucket Server, the server URL
+// is the only way to tell Bitbucket Cloud apart.
+func AppendBitbucketCloudSourceToEtree(source *etree.Element, gitSource *devopsv1alpha3.BitbucketCloudSource) {
+	if gitSource == nil {
+		klog.Warning("please provide BitbucketCloud source when the sourceType is BitbucketCloud")
+		return
+	}
+	source.CreateAttr("class", "com.cloudbees.jenkins.plugins.bitbucket.BitbucketSCMSource")
+	source.CreateAttr("plugin", "cloudbees-bitbucket-branch-source")
+	source.CreateElement("id").SetText(gitSource.ScmId)
+	source.CreateElement("credentialsId").SetText(gitSource.CredentialId)
+	source.CreateElement("repoOwner").SetText(gitSource.Owner)
+	source.CreateElement("repository").SetText(gitSource.Repo)
+	source.CreateElement("serverUrl").SetText(devopsv1alpha3.BitbucketCloudServerURL)
+
+	traits := source.CreateElement("traits")
+	if gitSource.DiscoverBranches != 0 {
+		traits.CreateElement("com.cloudbees.jenkins.plugins.bitbucket.BranchDiscoveryTrait").
+			CreateElement("strategyId").SetText(strconv.Itoa(gitSource.DiscoverBranches))
+	}
+	if gitSource.DiscoverPRFromOrigin != 0 {
+		traits.CreateElement("com.cloudbees.jenkins.plugins.bitbucket.OriginPullRequestDiscoveryTrait").
+			CreateElement("strategyId").SetText(strconv.Itoa(gitSource.DiscoverPRFromOrigin))
+	}
+	if gitSource.DiscoverPRFromForks != nil {
+		forkTrait := traits.CreateElement("com.cloudbees.jenkins.plugins.bitbucket.ForkPullRequestDiscoveryTrait")
+		forkTrait.CreateElement("strategyId").SetText(strconv.Itoa(gitSource.DiscoverPRFromForks.Strategy))
+		trustClass := "com.cloudbees.jenkins.plugins.bitbucket.ForkPullRequestDiscoveryTrait$"
+		trustClass += BitbucketPRDiscoverTrust(gitSource.DiscoverPRFromForks.Trust).String()
+		forkTrait.CreateElement("trust").CreateAttr("class", trustClass)
+	}
+	if gitSource.DiscoverTags {
+		traits.CreateElement("com.cloudbees.jenkins.plugins.bitbucket.TagDiscoveryTrait")
+	}
+	appendCloneTrait(traits, gitSource.CloneOption)
+	appendCheckoutTraits(traits, gitSource.CheckoutOption)
+	if gitSource.RegexFilter != "" {
+		regexTraits := traits.CreateElement("jenkins.scm.impl.trait.RegexSCMHeadFilterTrait")
+		regexTraits.CreateAttr("plugin", "scm-api")
+		regexTraits.CreateElement("regex").SetText(gitSource.RegexFilter)
+	}
+	if !gitSource.AcceptJenkinsNotification {
+		skipNotifications := traits.CreateElement("com.cloudbees.jenkins.plugins.bitbucket.notifications.SkipNotificationsTrait")
+		skipNotifications.CreateAttr("plugin", "skip-notifications-trait")
+	}
+	return
+}
+
+func GetBitbucketCloudSourceFromEtree(source *etree.Element) *devopsv1alpha3.BitbucketCloudSource {
+	var s devopsv1alpha3.BitbucketCloudSource
+	if credential := source.SelectElement("credentialsId"); credential != nil {
+		s.CredentialId = credential.Text()
+	}
+	if repoOwner := source.SelectElement("repoOwner"); repoOwner != nil {
+		s.Owner = repoOwner.Text()
+	}
+	if repository := source.SelectElement("repository"); repository != nil {
+		s.Repo = repository.Text()
+	}
+	traits := source.SelectElement("traits")
+	if traits == nil {
+		return &s
+	}
+	if branchDiscoverTrait := traits.SelectElement(
+		"com.cloudbees.jenkins.plugins.bitbucket.BranchDiscoveryTrait"); branchDiscoverTrait != nil {
+		strategyId, _ := strconv.Atoi(branchDiscoverTrait.SelectElement("strategyId").Text())
+		s.DiscoverBranches = strategyId
+	}
+	if tagDiscoverTrait := traits.SelectElement(
+		"com.cloudbees.jenkins.plugins.bitbucket.TagDiscoveryTrait"); tagDiscoverTrait != nil {
+		s.DiscoverTags = true
+	}
+	if originPRDiscoverTrait := traits.SelectElement(
+		"com.cloudbees.jenkins.plugins.bitbucket.OriginPullRequestDiscoveryTrait"); originPRDiscoverTrait != nil {
+		strategyId, _ := strconv.Atoi(originPRDiscoverTrait.SelectElement("strategyId").Text())
+		s.DiscoverPRFromOrigin = strategyId
+	}
+	if forkPRDiscoverTrait := traits.SelectElement(
+		"com.cloudbees.jenkins.plugins.bitbucket.ForkPullRequestDiscoveryTrait"); forkPRDiscoverTrait != nil {
+		strategyId, _ := strconv.Atoi(forkPRDiscoverTrait.SelectElement("strategyId").Text())
+		if trustEle := forkPRDiscoverTrait.SelectElement("trust"); trustEle != nil {
+			trustClass := trustEle.SelectAttrValue("class", "")
+			trust := trustClass[strings.LastIndex(trustClass, "$")+1:]
+			s.DiscoverPRFromForks = &devopsv1alpha3.DiscoverPRFromForks{
+				Strategy: strategyId,
+				Trust:    BitbucketPRDiscoverTrust(1).ParseFromString(trust).Value(),
 			}
 		}
+	}
+
+	s.CloneOption = parseFromCloneTrait(traits.SelectElement("jenkins.plugins.git.traits.CloneOptionTrait"))
+	s.CheckoutOption = parseFromCheckoutTraits(traits)
 
-		if skipNotificationTrait := traits.SelectElement(
-			"com.cloudbees.jenkins.plugins.bitbucket.notifications.SkipNotificationsTrait"); skipNotificationTrait == nil {
-			s.AcceptJenkinsNotification = true
+	if regexTrait := traits.SelectElement(
+		"jenkins.scm.impl.trait.RegexSCMHeadFilterTrait"); regexTrait != nil {
+		if regex := regexTrait.SelectElement("regex"); regex != nil {
+			s.RegexFilter = regex.Text()
 		}
 	}
+
+	if skipNotificationTrait := traits.SelectElement(
+		"com.cloudbees.jenkins.plugins.bitbucket.notifications.SkipNotificationsTrait"); skipNotificationTrait == nil {
+		s.AcceptJenkinsNotification = true
+	}
 	return &s
 }
+
+// IsBitbucketCloudSource tells whether a BitbucketSCMSource element points to Bitbucket Cloud,
+// the server URL is Bitbucket Cloud if it is empty as Jenkins does
+func IsBitbucketCloudSource(source *etree.Element) bool {
+	serverUrl := source.SelectElement("serverUrl")
+	if serverUrl == nil {
+		return true
+	}
+	server := strings.TrimSuffix(strings.TrimSpace(serverUrl.Text()), "/")
+	return server == "" || server == devopsv1alpha3.BitbucketCloudServerURL
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/beevik/etree"
+
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+const (
+	namedExceptionsStrategyClass = "jenkins.branch.NamedExceptionsBranchPropertyStrategy"
+	namedExceptionTag            = "jenkins.branch.NamedExceptionsBranchPropertyStrategy_-Named"
+
+	noTriggerBranchProperty      = "jenkins.branch.NoTriggerBranchProperty"
+	durabilityHintBranchProperty = "org.jenkinsci.plugins.workflow.multibranch.DurabilityHintBranchProperty"
+	untrustedBranchProperty      = "jenkins.branch.UntrustedBranchProperty"
+
+	tagBuildStrategy               = "jenkins.branch.buildstrategies.basic.TagBuildStrategyImpl"
+	changeRequestBuildStrategy     = "jenkins.branch.buildstrategies.basic.ChangeRequestBuildStrategyImpl"
+	branchBuildStrategy            = "jenkins.branch.buildstrategies.basic.BranchBuildStrategyImpl"
+	skipInitialBuildStrategy       = "jenkins.branch.buildstrategies.basic.SkipInitialBuildOnFirstBranchIndexing"
+	basicBranchBuildStrategyPlugin = "basic-branch-build-strategies"
+
+	millisPerDay = int64(24 * time.Hour / time.Millisecond)
+)
+
+// AppendBranchPropertyStrategyToEtree writes the properties into the strategy element of a branch source
+func AppendBranchPropertyStrategyToEtree(strategy *etree.Element, propertyStrategy *devopsv1alpha3.BranchPropertyStrategy) {
+	strategy.CreateAttr("class", namedExceptionsStrategyClass)
+	defaultProperties := strategy.CreateElement("defaultProperties")
+	namedExceptions := strategy.CreateElement("namedExceptions")
+	if propertyStrategy == nil {
+		defaultProperties.CreateAttr("class", "empty-list")
+		namedExceptions.CreateAttr("class", "empty-list")
+		return
+	}
+
+	if propertyStrategy.DefaultProperties != nil {
+		appendBranchPropertiesToEtree(defaultProperties, propertyStrategy.DefaultProperties)
+	} else {
+		defaultProperties.CreateAttr("class", "empty-list")
+	}
+
+	if len(propertyStrategy.NamedExceptions) == 0 {
+		namedExceptions.CreateAttr("class", "empty-list")
+		return
+	}
+	namedExceptions.CreateAttr("class", "java.util.Arrays$ArrayList")
+	namedArray := namedExceptions.CreateElement("a")
+	namedArray.CreateAttr("class", namedExceptionsStrategyClass+"$Named-array")
+	for i := range propertyStrategy.NamedExceptions {
+		named := namedArray.CreateElement(namedExceptionTag)
+		appendBranchPropertiesToEtree(named.CreateElement("props"), &propertyStrategy.NamedExceptions[i].Properties)
+		named.CreateElement("name").SetText(propertyStrategy.NamedExceptions[i].Name)
+	}
+}
+
+func appendBranchPropertiesToEtree(props *etree.Element, properties *devopsv1alpha3.BranchProperties) {
+	props.CreateAttr("class", "java.util.Arrays$ArrayList")
+	propArray := props.CreateElement("a")
+	propArray.CreateAttr("class", "jenkins.branch.BranchProperty-array")
+	if properties.SuppressAutomaticTriggering {
+		propArray.CreateElement(noTriggerBranchProperty)
+	}
+	if properties.DurabilityHint != "" {
+		hint := propArray.CreateElement(durabilityHintBranchProperty)
+		hint.CreateAttr("plugin", "workflow-multibranch")
+		hint.CreateElement("hint").SetText(properties.DurabilityHint)
+	}
+	if properties.UntrustedBranchProtection {
+		untrusted := propArray.CreateElement(untrustedBranchProperty)
+		untrusted.CreateElement("publisherWhitelist")
+	}
+}
+
+// GetBranchPropertyStrategyFromEtree returns nil if there is neither a default property nor a named exception
+func GetBranchPropertyStrategyFromEtree(strategy *etree.Element) *devopsv1alpha3.BranchPropertyStrategy {
+	if strategy == nil || strategy.SelectAttrValue("class", "") != namedExceptionsStrategyClass {
+		return nil
+	}
+	propertyStrategy := &devopsv1alpha3.BranchPropertyStrategy{}
+	if defaultProperties := strategy.SelectElement("defaultProperties"); defaultProperties != nil &&
+		defaultProperties.SelectElement("a") != nil {
+		propertyStrategy.DefaultProperties = getBranchPropertiesFromEtree(defaultProperties)
+	}
+	if namedExceptions := strategy.FindElement("namedExceptions/a"); namedExceptions != nil {
+		for _, named := range namedExceptions.SelectElements(namedExceptionTag) {
+			namedProperties := devopsv1alpha3.NamedBranchProperties{
+				Name: getChildText(named, "name"),
+			}
+			if props := named.SelectElement("props"); props != nil {
+				namedProperties.Properties = *getBranchPropertiesFromEtree(props)
+			}
+			propertyStrategy.NamedExceptions = append(propertyStrategy.NamedExceptions, namedProperties)
+		}
+	}
+	if propertyStrategy.DefaultProperties == nil && len(propertyStrategy.NamedExceptions) == 0 {
+		return nil
+	}
+	return propertyStrategy
+}
+
+func getBranchPropertiesFromEtree(props *etree.Element) *devopsv1alpha3.BranchProperties {
+	properties := &devopsv1alpha3.BranchProperties{}
+	propArray := props.SelectElement("a")
+	if propArray == nil {
+		return properties
+	}
+	if propArray.SelectElement(noTriggerBranchProperty) != nil {
+		properties.SuppressAutomaticTriggering = true
+	}
+	if hint := propArray.SelectElement(durabilityHintBranchProperty); hint != nil {
+		properties.DurabilityHint = getChildText(hint, "hint")
+	}
+	if propArray.SelectElement(untrustedBranchProperty) != nil {
+		properties.UntrustedBranchProtection = true
+	}
+	return properties
+}
+
+// AppendBuildStrategiesToEtree writes the build strategies of the basic-branch-build-strategies plugin
+func AppendBuildStrategiesToEtree(branchSource *etree.Element, buildStrategies *devopsv1alpha3.BranchBuildStrategies) {
+	if buildStrategies == nil {
+		return
+	}
+	strategies := branchSource.CreateElement("buildStrategies")
+	if buildStrategies.Tags != nil {
+		tags := strategies.CreateElement(tagBuildStrategy)
+		tags.CreateAttr("plugin", basicBranchBuildStrategyPlugin)
+		tags.CreateElement("atLeastMillis").SetText(strconv.FormatInt(daysToMillis(buildStrategies.Tags.AtLeastDays), 10))
+		tags.CreateElement("atMostMillis").SetText(strconv.FormatInt(daysToMillis(buildStrategies.Tags.AtMostDays), 10))
+	}
+	if buildStrategies.ChangeRequests != nil {
+		changeRequests := strategies.CreateElement(changeRequestBuildStrategy)
+		changeRequests.CreateAttr("plugin", basicBranchBuildStrategyPlugin)
+		changeRequests.CreateElement("ignoreTargetOnlyChanges").
+			SetText(strconv.FormatBool(buildStrategies.ChangeRequests.IgnoreTargetOnlyChanges))
+		changeRequests.CreateElement("ignoreUntrustedChanges").
+			SetText(strconv.FormatBool(buildStrategies.ChangeRequests.IgnoreUntrustedChanges))
+	}
+	if buildStrategies.RegularBranches {
+		strategies.CreateElement(branchBuildStrategy).CreateAttr("plugin", basicBranchBuildStrategyPlugin)
+	}
+	if buildStrategies.SkipInitialBuild {
+		strategies.CreateElement(skipInitialBuildStrategy).CreateAttr("plugin", basicBranchBuildStrategyPlugin)
+	}
+}
+
+func GetBuildStrategiesFromEtree(branchSource *etree.Element) *devopsv1alpha3.BranchBuildStrategies {
+	strategies := branchSource.SelectElement("buildStrategies")
+	if strategies == nil {
+		return nil
+	}
+	buildStrategies := &devopsv1alpha3.BranchBuildStrategies{}
+	if tags := strategies.SelectElement(tagBuildStrategy); tags != nil {
+		atLeast, _ := strconv.ParseInt(getChildText(tags, "atLeastMillis"), 10, 64)
+		atMost, _ := strconv.ParseInt(getChildText(tags, "atMostMillis"), 10, 64)
+		buildStrategies.Tags = &devopsv1alpha3.TagBuildStrategy{
+			AtLeastDays: millisToDays(atLeast),
+			AtMostDays:  millisToDays(atMost),
+		}
+	}
+	if changeRequests := strategies.SelectElement(changeRequestBuildStrategy); changeRequests != nil {
+		buildStrategies.ChangeRequests = &devopsv1alpha3.ChangeRequestBuildStrategy{}
+		buildStrategies.ChangeRequests.IgnoreTargetOnlyChanges, _ = strconv.ParseBool(getChildText(changeRequests, "ignoreTargetOnlyChanges"))
+		buildStrategies.ChangeRequests.IgnoreUntrustedChanges, _ = strconv.ParseBool(getChildText(changeRequests, "ignoreUntrustedChanges"))
+	}
+	if strategies.SelectElement(branchBuildStrategy) != nil {
+		buildStrategies.RegularBranches = true
+	}
+	if strategies.SelectElement(skipInitialBuildStrategy) != nil {
+		buildStrategies.SkipInitialBuild = true
+	}
+	return buildStrategies
+}
+
+// daysToMillis returns -1 which means no limitation if the days is not positive
+func daysToMillis(days int) int64 {
+	if days <= 0 {
+		return -1
+	}
+	return int64(days) * millisPerDay
+}
+
+func millisToDays(millis int64) int {
+	if millis <= 0 {
+		return 0
+	}
+	return int(millis / millisPerDay)
+}
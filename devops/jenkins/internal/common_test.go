@@ -29,6 +29,8 @@ func TestCommonSituation(t *testing.T) {
 	AppendGitlabSourceToEtree(nil, nil)
 	AppendGithubSourceToEtree(nil, nil)
 	AppendBitbucketServerSourceToEtree(nil, nil)
+	AppendBitbucketCloudSourceToEtree(nil, nil)
+	AppendGiteaSourceToEtree(nil, nil)
 	AppendGitSourceToEtree(nil, nil)
 	AppendSingleSvnSourceToEtree(nil, nil)
 	AppendSvnSourceToEtree(nil, nil)
@@ -83,3 +85,108 @@ func TestAcceptJenkinsNotification(t *testing.T) {
 	bitbucketServerSource := GetBitbucketServerSourceFromEtree(source)
 	assert.True(t, bitbucketServerSource.AcceptJenkinsNotification)
 }
+
+func TestCloneAndCheckoutTraits(t *testing.T) {
+	cloneOption := &devopsv1alpha3.GitCloneOption{
+		Shallow:   true,
+		Timeout:   20,
+		Depth:     3,
+		NoTags:    true,
+		Reference: "/var/cache/git/devops.git",
+	}
+	checkoutOption := &devopsv1alpha3.GitCheckoutOption{
+		Submodule: &devopsv1alpha3.GitSubmoduleOption{
+			Recursive:         true,
+			Shallow:           true,
+			Depth:             2,
+			ParentCredentials: true,
+			Timeout:           15,
+		},
+		LFS:                 true,
+		SparseCheckoutPaths: []string{"docs", "pkg/api"},
+		CleanBeforeCheckout: true,
+		CleanAfterCheckout:  true,
+		LocalBranch:         true,
+		RefSpecs:            []string{"+refs/heads/*:refs/remotes/@{remote}/*", "+refs/pull/*:refs/remotes/@{remote}/pr/*"},
+	}
+
+	// git
+	source := etree.NewDocument().CreateElement("source")
+	AppendGitSourceToEtree(source, &devopsv1alpha3.GitSource{CloneOption: cloneOption, CheckoutOption: checkoutOption})
+	gitSource := GetGitSourcefromEtree(source)
+	assert.Equal(t, cloneOption, gitSource.CloneOption)
+	assert.Equal(t, checkoutOption, gitSource.CheckoutOption)
+
+	// github
+	source = etree.NewDocument().CreateElement("source")
+	AppendGithubSourceToEtree(source, &devopsv1alpha3.GithubSource{CloneOption: cloneOption, CheckoutOption: checkoutOption})
+	githubSource := GetGithubSourcefromEtree(source)
+	assert.Equal(t, cloneOption, githubSource.CloneOption)
+	assert.Equal(t, checkoutOption, githubSource.CheckoutOption)
+
+	// gitlab
+	source = etree.NewDocument().CreateElement("source")
+	AppendGitlabSourceToEtree(source, &devopsv1alpha3.GitlabSource{CloneOption: cloneOption, CheckoutOption: checkoutOption})
+	gitlabSource := GetGitlabSourceFromEtree(source)
+	assert.Equal(t, cloneOption, gitlabSource.CloneOption)
+	assert.Equal(t, checkoutOption, gitlabSource.CheckoutOption)
+
+	// bitbucketServer
+	source = etree.NewDocument().CreateElement("source")
+	AppendBitbucketServerSourceToEtree(source, &devopsv1alpha3.BitbucketServerSource{CloneOption: cloneOption, CheckoutOption: checkoutOption})
+	bitbucketServerSource := GetBitbucketServerSourceFromEtree(source)
+	assert.Equal(t, cloneOption, bitbucketServerSource.CloneOption)
+	assert.Equal(t, checkoutOption, bitbucketServerSource.CheckoutOption)
+
+	// bitbucketCloud
+	source = etree.NewDocument().CreateElement("source")
+	AppendBitbucketCloudSourceToEtree(source, &devopsv1alpha3.BitbucketCloudSource{CloneOption: cloneOption, CheckoutOption: checkoutOption})
+	bitbucketCloudSource := GetBitbucketCloudSourceFromEtree(source)
+	assert.Equal(t, cloneOption, bitbucketCloudSource.CloneOption)
+	assert.Equal(t, checkoutOption, bitbucketCloudSource.CheckoutOption)
+
+	// the depth of submodules is kept as given
+	submoduleOption := &devopsv1alpha3.GitCheckoutOption{Submodule: &devopsv1alpha3.GitSubmoduleOption{Recursive: true}}
+	source = etree.NewDocument().CreateElement("source")
+	AppendGitSourceToEtree(source, &devopsv1alpha3.GitSource{CheckoutOption: submoduleOption})
+	assert.Equal(t, submoduleOption, GetGitSourcefromEtree(source).CheckoutOption)
+
+	// no checkout traits
+	source = etree.NewDocument().CreateElement("source")
+	AppendGitSourceToEtree(source, &devopsv1alpha3.GitSource{CheckoutOption: &devopsv1alpha3.GitCheckoutOption{}})
+	assert.Nil(t, GetGitSourcefromEtree(source).CheckoutOption)
+}
+
+func TestTraitsWithoutForkPRDiscovery(t *testing.T) {
+	source := etree.NewDocument().CreateElement("source")
+	AppendGithubSourceToEtree(source, &devopsv1alpha3.GithubSource{
+		RegexFilter:               "main|release-.*",
+		CloneOption:               &devopsv1alpha3.GitCloneOption{Depth: 1},
+		AcceptJenkinsNotification: true,
+	})
+	githubSource := GetGithubSourcefromEtree(source)
+	assert.Equal(t, "main|release-.*", githubSource.RegexFilter)
+	assert.Equal(t, &devopsv1alpha3.GitCloneOption{Depth: 1}, githubSource.CloneOption)
+	assert.True(t, githubSource.AcceptJenkinsNotification)
+}
+
+func TestIsBitbucketCloudSource(t *testing.T) {
+	for serverUrl, expected := range map[string]bool{
+		"":                                  true,
+		"https://bitbucket.org":             true,
+		"https://bitbucket.org/":            true,
+		"https://bitbucket.example.com":     false,
+		"https://bitbucket.example.com/org": false,
+	} {
+		source := etree.NewDocument().CreateElement("source")
+		AppendBitbucketServerSourceToEtree(source, &devopsv1alpha3.BitbucketServerSource{ApiUri: serverUrl})
+		assert.Equal(t, expected, IsBitbucketCloudSource(source), serverUrl)
+	}
+
+	// Jenkins takes the source without the server URL as Bitbucket Cloud
+	assert.True(t, IsBitbucketCloudSource(etree.NewDocument().CreateElement("source")))
+
+	source := etree.NewDocument().CreateElement("source")
+	AppendBitbucketCloudSourceToEtree(source, &devopsv1alpha3.BitbucketCloudSource{Owner: "kubesphere", Repo: "devops"})
+	assert.True(t, IsBitbucketCloudSource(source))
+}
@@ -17,7 +17,7 @@ limitations under the License.
 package internal
 
 import (
-	"strconv"
+	"strings"
 
 	"github.com/beevik/etree"
 	"k8s.io/klog/v2"
@@ -44,25 +44,8 @@ func AppendGitSourceToEtree(source *etree.Element, gitSource *devopsv1alpha3.Git
 	if gitSource.DiscoverTags {
 		traits.CreateElement("jenkins.plugins.git.traits.TagDiscoveryTrait")
 	}
-	if gitSource.CloneOption != nil {
-		cloneExtension := traits.CreateElement("jenkins.plugins.git.traits.CloneOptionTrait").CreateElement("extension")
-		cloneExtension.CreateAttr("class", "hudson.plugins.git.extensions.impl.CloneOption")
-		cloneExtension.CreateElement("shallow").SetText(strconv.FormatBool(gitSource.CloneOption.Shallow))
-		cloneExtension.CreateElement("noTags").SetText(strconv.FormatBool(false))
-		cloneExtension.CreateElement("honorRefspec").SetText(strconv.FormatBool(true))
-		cloneExtension.CreateElement("reference")
-		if gitSource.CloneOption.Timeout >= 0 {
-			cloneExtension.CreateElement("timeout").SetText(strconv.Itoa(gitSource.CloneOption.Timeout))
-		} else {
-			cloneExtension.CreateElement("timeout").SetText(strconv.Itoa(10))
-		}
-
-		if gitSource.CloneOption.Depth >= 0 {
-			cloneExtension.CreateElement("depth").SetText(strconv.Itoa(gitSource.CloneOption.Depth))
-		} else {
-			cloneExtension.CreateElement("depth").SetText(strconv.Itoa(1))
-		}
-	}
+	appendCloneTrait(traits, gitSource.CloneOption)
+	appendCheckoutTraits(traits, gitSource.CheckoutOption)
 
 	if gitSource.RegexFilter != "" {
 		regexTraits := traits.CreateElement("jenkins.scm.impl.trait.RegexSCMHeadFilterTrait")
@@ -92,6 +75,7 @@ func GetGitSourcefromEtree(source *etree.Element) *devopsv1alpha3.GitSource {
 	}
 
 	gitSource.CloneOption = parseFromCloneTrait(traits.SelectElement("jenkins.plugins.git.traits.CloneOptionTrait"))
+	gitSource.CheckoutOption = parseFromCheckoutTraits(traits)
 
 	if regexTrait := traits.SelectElement(
 		"jenkins.scm.impl.trait.RegexSCMHeadFilterTrait"); regexTrait != nil {
@@ -101,3 +85,68 @@ func GetGitSourcefromEtree(source *etree.Element) *devopsv1alpha3.GitSource {
 	}
 	return &gitSource
 }
+
+// AppendGitSCMToEtree writes the GitSCM used by the single branch pipelines, the refspecs are written to the
+// remote config since they are not an extension of GitSCM
+func AppendGitSCMToEtree(scm *etree.Element, gitSource *devopsv1alpha3.GitSource, branch string) {
+	if gitSource == nil {
+		klog.Warning("please provide Git source when the sourceType is Git")
+		return
+	}
+	scm.CreateAttr("class", "hudson.plugins.git.GitSCM")
+	scm.CreateAttr("plugin", "git")
+	scm.CreateElement("configVersion").SetText("2")
+	remoteConfig := scm.CreateElement("userRemoteConfigs").CreateElement("hudson.plugins.git.UserRemoteConfig")
+	remoteConfig.CreateElement("url").SetText(gitSource.Url)
+	if gitSource.CredentialId != "" {
+		remoteConfig.CreateElement("credentialsId").SetText(gitSource.CredentialId)
+	}
+	if gitSource.CheckoutOption != nil && len(gitSource.CheckoutOption.RefSpecs) > 0 {
+		remoteConfig.CreateElement("refspec").SetText(strings.Join(gitSource.CheckoutOption.RefSpecs, " "))
+	}
+	scm.CreateElement("branches").CreateElement("hudson.plugins.git.BranchSpec").CreateElement("name").SetText(branch)
+	scm.CreateElement("doGenerateSubmoduleConfigurations").SetText("false")
+	scm.CreateElement("submoduleCfg").CreateAttr("class", "empty-list")
+
+	extensions := scm.CreateElement("extensions")
+	if gitSource.CloneOption != nil {
+		appendCloneExtension(extensions.CreateElement(cloneOptionExtension), gitSource.CloneOption)
+	}
+	if gitSource.CheckoutOption != nil {
+		appendCheckoutExtensions(gitSource.CheckoutOption, func(_, extensionClass string) *etree.Element {
+			return extensions.CreateElement(extensionClass)
+		})
+	}
+}
+
+// GetGitSCMFromEtree returns the git source and the branch specifier of the GitSCM
+func GetGitSCMFromEtree(scm *etree.Element) (*devopsv1alpha3.GitSource, string) {
+	var gitSource devopsv1alpha3.GitSource
+	var refSpecs []string
+	if remoteConfig := scm.FindElement("userRemoteConfigs/hudson.plugins.git.UserRemoteConfig"); remoteConfig != nil {
+		gitSource.Url = getChildText(remoteConfig, "url")
+		gitSource.CredentialId = getChildText(remoteConfig, "credentialsId")
+		refSpecs = strings.Fields(getChildText(remoteConfig, "refspec"))
+	}
+	var branch string
+	if branchSpec := scm.FindElement("branches/hudson.plugins.git.BranchSpec"); branchSpec != nil {
+		branch = getChildText(branchSpec, "name")
+	}
+
+	if extensions := scm.SelectElement("extensions"); extensions != nil {
+		gitSource.CloneOption = parseFromCloneExtension(extensions.SelectElement(cloneOptionExtension))
+		checkoutOption, found := parseFromCheckoutExtensions(func(_, extensionClass string) *etree.Element {
+			return extensions.SelectElement(extensionClass)
+		})
+		if found {
+			gitSource.CheckoutOption = checkoutOption
+		}
+	}
+	if len(refSpecs) > 0 {
+		if gitSource.CheckoutOption == nil {
+			gitSource.CheckoutOption = &devopsv1alpha3.GitCheckoutOption{}
+		}
+		gitSource.CheckoutOption.RefSpecs = refSpecs
+	}
+	return &gitSource, branch
+}
@@ -63,25 +63,8 @@ func AppendGithubSourceToEtree(source *etree.Element, githubSource *devopsv1alph
 	if githubSource.DiscoverTags {
 		traits.CreateElement("org.jenkinsci.plugins.github__branch__source.TagDiscoveryTrait")
 	}
-	if githubSource.CloneOption != nil {
-		cloneExtension := traits.CreateElement("jenkins.plugins.git.traits.CloneOptionTrait").CreateElement("extension")
-		cloneExtension.CreateAttr("class", "hudson.plugins.git.extensions.impl.CloneOption")
-		cloneExtension.CreateElement("shallow").SetText(strconv.FormatBool(githubSource.CloneOption.Shallow))
-		cloneExtension.CreateElement("noTags").SetText(strconv.FormatBool(false))
-		cloneExtension.CreateElement("honorRefspec").SetText(strconv.FormatBool(true))
-		cloneExtension.CreateElement("reference")
-		if githubSource.CloneOption.Timeout >= 0 {
-			cloneExtension.CreateElement("timeout").SetText(strconv.Itoa(githubSource.CloneOption.Timeout))
-		} else {
-			cloneExtension.CreateElement("timeout").SetText(strconv.Itoa(10))
-		}
-
-		if githubSource.CloneOption.Depth >= 0 {
-			cloneExtension.CreateElement("depth").SetText(strconv.Itoa(githubSource.CloneOption.Depth))
-		} else {
-			cloneExtension.CreateElement("depth").SetText(strconv.Itoa(1))
-		}
-	}
+	appendCloneTrait(traits, githubSource.CloneOption)
+	appendCheckoutTraits(traits, githubSource.CheckoutOption)
 	if githubSource.RegexFilter != "" {
 		regexTraits := traits.CreateElement("jenkins.scm.impl.trait.RegexSCMHeadFilterTrait")
 		regexTraits.CreateAttr("plugin", "scm-api")
@@ -138,20 +121,21 @@ func GetGithubSourcefromEtree(source *etree.Element) *devopsv1alpha3.GithubSourc
 				klog.Warningf("invalid Gitlab discover PR trust value: %s", trust[1])
 			}
 		}
+	}
 
-		githubSource.CloneOption = parseFromCloneTrait(traits.SelectElement("jenkins.plugins.git.traits.CloneOptionTrait"))
+	githubSource.CloneOption = parseFromCloneTrait(traits.SelectElement("jenkins.plugins.git.traits.CloneOptionTrait"))
+	githubSource.CheckoutOption = parseFromCheckoutTraits(traits)
 
-		if regexTrait := traits.SelectElement(
-			"jenkins.scm.impl.trait.RegexSCMHeadFilterTrait"); regexTrait != nil {
-			if regex := regexTrait.SelectElement("regex"); regex != nil {
-				githubSource.RegexFilter = regex.Text()
-			}
+	if regexTrait := traits.SelectElement(
+		"jenkins.scm.impl.trait.RegexSCMHeadFilterTrait"); regexTrait != nil {
+		if regex := regexTrait.SelectElement("regex"); regex != nil {
+			githubSource.RegexFilter = regex.Text()
 		}
+	}
 
-		if skipNotificationTrait := traits.SelectElement(
-			"org.jenkinsci.plugins.github.notifications.NotificationsSkipTrait"); skipNotificationTrait == nil {
-			githubSource.AcceptJenkinsNotification = true
-		}
+	if skipNotificationTrait := traits.SelectElement(
+		"org.jenkinsci.plugins.github.notifications.NotificationsSkipTrait"); skipNotificationTrait == nil {
+		githubSource.AcceptJenkinsNotification = true
 	}
 	return &githubSource
 }
@@ -62,25 +62,8 @@ func AppendGitlabSourceToEtree(source *etree.Element, gitSource *devopsv1alpha3.
 		}
 		forkTrait.CreateElement("trust").CreateAttr("class", trustClass)
 	}
-	if gitSource.CloneOption != nil {
-		cloneExtension := traits.CreateElement("jenkins.plugins.git.traits.CloneOptionTrait").CreateElement("extension")
-		cloneExtension.CreateAttr("class", "hudson.plugins.git.extensions.impl.CloneOption")
-		cloneExtension.CreateElement("shallow").SetText(strconv.FormatBool(gitSource.CloneOption.Shallow))
-		cloneExtension.CreateElement("noTags").SetText(strconv.FormatBool(false))
-		cloneExtension.CreateElement("honorRefspec").SetText(strconv.FormatBool(true))
-		cloneExtension.CreateElement("reference")
-		if gitSource.CloneOption.Timeout >= 0 {
-			cloneExtension.CreateElement("timeout").SetText(strconv.Itoa(gitSource.CloneOption.Timeout))
-		} else {
-			cloneExtension.CreateElement("timeout").SetText(strconv.Itoa(10))
-		}
-
-		if gitSource.CloneOption.Depth >= 0 {
-			cloneExtension.CreateElement("depth").SetText(strconv.Itoa(gitSource.CloneOption.Depth))
-		} else {
-			cloneExtension.CreateElement("depth").SetText(strconv.Itoa(1))
-		}
-	}
+	appendCloneTrait(traits, gitSource.CloneOption)
+	appendCheckoutTraits(traits, gitSource.CheckoutOption)
 	if gitSource.RegexFilter != "" {
 		regexTraits := traits.CreateElement("jenkins.scm.impl.trait.RegexSCMHeadFilterTrait")
 		regexTraits.CreateAttr("plugin", "scm-api")
@@ -137,20 +120,21 @@ func GetGitlabSourceFromEtree(source *etree.Element) (gitSource *devopsv1alpha3.
 				klog.Warningf("invalid Gitlab discover PR trust value: %s", trust[1])
 			}
 		}
+	}
 
-		gitSource.CloneOption = parseFromCloneTrait(traits.SelectElement("jenkins.plugins.git.traits.CloneOptionTrait"))
+	gitSource.CloneOption = parseFromCloneTrait(traits.SelectElement("jenkins.plugins.git.traits.CloneOptionTrait"))
+	gitSource.CheckoutOption = parseFromCheckoutTraits(traits)
 
-		if regexTrait := traits.SelectElement(
-			"jenkins.scm.impl.trait.RegexSCMHeadFilterTrait"); regexTrait != nil {
-			if regex := regexTrait.SelectElement("regex"); regex != nil {
-				gitSource.RegexFilter = regex.Text()
-			}
+	if regexTrait := traits.SelectElement(
+		"jenkins.scm.impl.trait.RegexSCMHeadFilterTrait"); regexTrait != nil {
+		if regex := regexTrait.SelectElement("regex"); regex != nil {
+			gitSource.RegexFilter = regex.Text()
 		}
+	}
 
-		if skipNotificationTrait := traits.SelectElement(
-			"io.jenkins.plugins.gitlabbranchsource.GitLabSkipNotificationsTrait"); skipNotificationTrait == nil {
-			gitSource.AcceptJenkinsNotification = true
-		}
+	if skipNotificationTrait := traits.SelectElement(
+		"io.jenkins.plugins.gitlabbranchsource.GitLabSkipNotificationsTrait"); skipNotificationTrait == nil {
+		gitSource.AcceptJenkinsNotification = true
 	}
 	return
 }
@@ -0,0 +1,226 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+	"k8s.io/klog/v2"
+
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+// navigator describes how the SCM navigator of an organization folder is serialized
+type navigator struct {
+	tag       string
+	plugin    string
+	ownerTag  string
+	serverTag string
+	// the prefix of the trait elements
+	traitPrefix   string
+	originPRTrait string
+	forkPRTrait   string
+	// the prefix of the trust class of the fork PR discovery trait
+	trustPrefix     string
+	trustToString   func(int) string
+	trustFromString func(string) (int, bool)
+}
+
+var navigators = map[string]navigator{
+	devopsv1alpha3.NavigatorTypeGithub: {
+		tag:           "org.jenkinsci.plugins.github__branch__source.GitHubSCMNavigator",
+		plugin:        "github-branch-source",
+		ownerTag:      "repoOwner",
+		serverTag:     "apiUri",
+		traitPrefix:   "org.jenkinsci.plugins.github__branch__source.",
+		originPRTrait: "OriginPullRequestDiscoveryTrait",
+		forkPRTrait:   "ForkPullRequestDiscoveryTrait",
+		trustPrefix:   "org.jenkinsci.plugins.github_branch_source.ForkPullRequestDiscoveryTrait$",
+		trustToString: func(trust int) string {
+			return GitHubPRDiscoverTrust(trust).String()
+		},
+		trustFromString: func(trust string) (int, bool) {
+			prTrust := GitHubPRDiscoverTrust(1).ParseFromString(trust)
+			return prTrust.Value(), prTrust.IsValid()
+		},
+	},
+	devopsv1alpha3.NavigatorTypeGitlab: {
+		tag:           "io.jenkins.plugins.gitlabbranchsource.GitLabSCMNavigator",
+		plugin:        "gitlab-branch-source",
+		ownerTag:      "projectOwner",
+		serverTag:     "serverName",
+		traitPrefix:   "io.jenkins.plugins.gitlabbranchsource.",
+		originPRTrait: "OriginMergeRequestDiscoveryTrait",
+		forkPRTrait:   "ForkMergeRequestDiscoveryTrait",
+		trustPrefix:   "io.jenkins.plugins.gitlabbranchsource.ForkMergeRequestDiscoveryTrait$",
+		trustToString: func(trust int) string {
+			return PRDiscoverTrust(trust).String()
+		},
+		trustFromString: func(trust string) (int, bool) {
+			prTrust := PRDiscoverTrust(1).ParseFromString(trust)
+			return prTrust.Value(), prTrust.IsValid()
+		},
+	},
+	devopsv1alpha3.NavigatorTypeBitbucket: {
+		tag:           "com.cloudbees.jenkins.plugins.bitbucket.BitbucketSCMNavigator",
+		plugin:        "cloudbees-bitbucket-branch-source",
+		ownerTag:      "repoOwner",
+		serverTag:     "serverUrl",
+		traitPrefix:   "com.cloudbees.jenkins.plugins.bitbucket.",
+		originPRTrait: "OriginPullRequestDiscoveryTrait",
+		forkPRTrait:   "ForkPullRequestDiscoveryTrait",
+		trustPrefix:   "com.cloudbees.jenkins.plugins.bitbucket.ForkPullRequestDiscoveryTrait$",
+		trustToString: func(trust int) string {
+			return BitbucketPRDiscoverTrust(trust).String()
+		},
+		trustFromString: func(trust string) (int, bool) {
+			prTrust := BitbucketPRDiscoverTrust(1).ParseFromString(trust)
+			return prTrust.Value(), prTrust.IsValid()
+		},
+	},
+}
+
+const repositoryFilterTrait = "jenkins.scm.impl.trait.RegexSCMSourceFilterTrait"
+
+// AppendNavigatorToEtree writes the SCM navigator of an organization folder into the navigators element
+func AppendNavigatorToEtree(navigatorsEle *etree.Element, folder *devopsv1alpha3.OrganizationFolder) error {
+	nav, ok := navigators[folder.Navigator]
+	if !ok {
+		return fmt.Errorf("unsupport navigator type: %s", folder.Navigator)
+	}
+	navigatorEle := navigatorsEle.CreateElement(nav.tag)
+	navigatorEle.CreateAttr("plugin", nav.plugin)
+	navigatorEle.CreateElement(nav.ownerTag).SetText(folder.Owner)
+	server := folder.ApiUri
+	if folder.Navigator == devopsv1alpha3.NavigatorTypeGitlab {
+		server = folder.ServerName
+	}
+	if server != "" {
+		navigatorEle.CreateElement(nav.serverTag).SetText(server)
+	}
+	navigatorEle.CreateElement("credentialsId").SetText(folder.CredentialId)
+
+	traits := navigatorEle.CreateElement("traits")
+	if folder.RepositoryFilter != "" {
+		filter := traits.CreateElement(repositoryFilterTrait)
+		filter.CreateAttr("plugin", "scm-api")
+		filter.CreateElement("regex").SetText(folder.RepositoryFilter)
+	}
+	folderTraits := folder.Traits
+	if folderTraits == nil {
+		return nil
+	}
+	if folderTraits.DiscoverBranches != 0 {
+		traits.CreateElement(nav.traitPrefix + "BranchDiscoveryTrait").
+			CreateElement("strategyId").SetText(strconv.Itoa(folderTraits.DiscoverBranches))
+	}
+	if folderTraits.DiscoverPRFromOrigin != 0 {
+		traits.CreateElement(nav.traitPrefix + nav.originPRTrait).
+			CreateElement("strategyId").SetText(strconv.Itoa(folderTraits.DiscoverPRFromOrigin))
+	}
+	if folderTraits.DiscoverPRFromForks != nil {
+		forkTrait := traits.CreateElement(nav.traitPrefix + nav.forkPRTrait)
+		forkTrait.CreateElement("strategyId").SetText(strconv.Itoa(folderTraits.DiscoverPRFromForks.Strategy))
+		trustClass := nav.trustPrefix
+		if trust := nav.trustToString(folderTraits.DiscoverPRFromForks.Trust); trust != "" {
+			trustClass += trust
+		} else {
+			klog.Warningf("invalid %s discover PR trust value: %d", folder.Navigator, folderTraits.DiscoverPRFromForks.Trust)
+		}
+		forkTrait.CreateElement("trust").CreateAttr("class", trustClass)
+	}
+	if folderTraits.DiscoverTags {
+		traits.CreateElement(nav.traitPrefix + "TagDiscoveryTrait")
+	}
+	appendCloneTrait(traits, folderTraits.CloneOption)
+	appendCheckoutTraits(traits, folderTraits.CheckoutOption)
+	if folderTraits.RegexFilter != "" {
+		regexTraits := traits.CreateElement("jenkins.scm.impl.trait.RegexSCMHeadFilterTrait")
+		regexTraits.CreateAttr("plugin", "scm-api")
+		regexTraits.CreateElement("regex").SetText(folderTraits.RegexFilter)
+	}
+	return nil
+}
+
+// GetNavigatorFromEtree reads the first supported SCM navigator into the organization folder,
+// it returns false if there is no supported navigator
+func GetNavigatorFromEtree(navigatorsEle *etree.Element, folder *devopsv1alpha3.OrganizationFolder) bool {
+	if navigatorsEle == nil {
+		return false
+	}
+	for navigatorType, nav := range navigators {
+		navigatorEle := navigatorsEle.SelectElement(nav.tag)
+		if navigatorEle == nil {
+			continue
+		}
+		folder.Navigator = navigatorType
+		folder.Owner = getChildText(navigatorEle, nav.ownerTag)
+		if navigatorType == devopsv1alpha3.NavigatorTypeGitlab {
+			folder.ServerName = getChildText(navigatorEle, nav.serverTag)
+		} else {
+			folder.ApiUri = getChildText(navigatorEle, nav.serverTag)
+		}
+		folder.CredentialId = getChildText(navigatorEle, "credentialsId")
+
+		traits := navigatorEle.SelectElement("traits")
+		if traits == nil {
+			return true
+		}
+		if filter := traits.SelectElement(repositoryFilterTrait); filter != nil {
+			folder.RepositoryFilter = getChildText(filter, "regex")
+		}
+
+		folderTraits := &devopsv1alpha3.OrganizationFolderTraits{}
+		if branchDiscoverTrait := traits.SelectElement(nav.traitPrefix + "BranchDiscoveryTrait"); branchDiscoverTrait != nil {
+			folderTraits.DiscoverBranches, _ = strconv.Atoi(getChildText(branchDiscoverTrait, "strategyId"))
+		}
+		if originPRDiscoverTrait := traits.SelectElement(nav.traitPrefix + nav.originPRTrait); originPRDiscoverTrait != nil {
+			folderTraits.DiscoverPRFromOrigin, _ = strconv.Atoi(getChildText(originPRDiscoverTrait, "strategyId"))
+		}
+		if forkPRDiscoverTrait := traits.SelectElement(nav.traitPrefix + nav.forkPRTrait); forkPRDiscoverTrait != nil {
+			strategyId, _ := strconv.Atoi(getChildText(forkPRDiscoverTrait, "strategyId"))
+			if trustEle := forkPRDiscoverTrait.SelectElement("trust"); trustEle != nil {
+				trustClass := trustEle.SelectAttrValue("class", "")
+				trust := trustClass[strings.LastIndex(trustClass, "$")+1:]
+				if trustValue, ok := nav.trustFromString(trust); ok {
+					folderTraits.DiscoverPRFromForks = &devopsv1alpha3.DiscoverPRFromForks{
+						Strategy: strategyId,
+						Trust:    trustValue,
+					}
+				} else {
+					klog.Warningf("invalid %s discover PR trust value: %s", navigatorType, trust)
+				}
+			}
+		}
+		if traits.SelectElement(nav.traitPrefix+"TagDiscoveryTrait") != nil {
+			folderTraits.DiscoverTags = true
+		}
+		folderTraits.CloneOption = parseFromCloneTrait(traits.SelectElement(cloneOptionTrait))
+		folderTraits.CheckoutOption = parseFromCheckoutTraits(traits)
+		if regexTrait := traits.SelectElement("jenkins.scm.impl.trait.RegexSCMHeadFilterTrait"); regexTrait != nil {
+			folderTraits.RegexFilter = getChildText(regexTrait, "regex")
+		}
+		if *folderTraits != (devopsv1alpha3.OrganizationFolderTraits{}) {
+			folder.Traits = folderTraits
+		}
+		return true
+	}
+	return false
+}
@@ -138,3 +138,45 @@ func (p BitbucketPRDiscoverTrust) ParseFromString(prTrust string) BitbucketPRDis
 		return BitbucketPRDiscoverTrustNobody
 	}
 }
+
+// Gitea
+type GiteaPRDiscoverTrust int
+
+const (
+	GiteaPRDiscoverTrustContributors GiteaPRDiscoverTrust = 1
+	GiteaPRDiscoverTrustEveryone     GiteaPRDiscoverTrust = 2
+	GiteaPRDiscoverTrustNobody       GiteaPRDiscoverTrust = 4
+)
+
+func (p GiteaPRDiscoverTrust) Value() int {
+	return int(p)
+}
+
+func (p GiteaPRDiscoverTrust) IsValid() bool {
+	return p.String() != ""
+}
+
+func (p GiteaPRDiscoverTrust) String() string {
+	switch p {
+	case GiteaPRDiscoverTrustContributors:
+		return "TrustContributors"
+	case GiteaPRDiscoverTrustEveryone:
+		return "TrustEveryone"
+	case GiteaPRDiscoverTrustNobody:
+		return "TrustNobody"
+	}
+	return ""
+}
+
+func (p GiteaPRDiscoverTrust) ParseFromString(prTrust string) GiteaPRDiscoverTrust {
+	switch prTrust {
+	case "TrustContributors":
+		return GiteaPRDiscoverTrustContributors
+	case "TrustEveryone":
+		return GiteaPRDiscoverTrustEveryone
+	case "TrustNobody":
+		return GiteaPRDiscoverTrustNobody
+	default:
+		return GiteaPRDiscoverTrust(PRDiscoverUnknown)
+	}
+}
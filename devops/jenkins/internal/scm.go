@@ -17,34 +17,235 @@ limitations under the License.
 package internal
 
 import (
+	"strconv"
+
 	"github.com/beevik/etree"
+
 	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
-	"strconv"
 )
 
 // common functions for all kinds of scm
 
+const (
+	cloneOptionTrait         = "jenkins.plugins.git.traits.CloneOptionTrait"
+	submoduleOptionTrait     = "jenkins.plugins.git.traits.SubmoduleOptionTrait"
+	gitLFSPullTrait          = "jenkins.plugins.git.traits.GitLFSPullTrait"
+	sparseCheckoutPathsTrait = "jenkins.plugins.git.traits.SparseCheckoutPathsTrait"
+	cleanBeforeCheckoutTrait = "jenkins.plugins.git.traits.CleanBeforeCheckoutTrait"
+	cleanAfterCheckoutTrait  = "jenkins.plugins.git.traits.CleanAfterCheckoutTrait"
+	localBranchTrait         = "jenkins.plugins.git.traits.LocalBranchTrait"
+	refSpecsTrait            = "jenkins.plugins.git.traits.RefSpecsSCMSourceTrait"
+	refSpecTemplate          = "jenkins.plugins.git.traits.RefSpecsSCMSourceTrait_-RefSpecTemplate"
+	sparseCheckoutPath       = "hudson.plugins.git.extensions.impl.SparseCheckoutPath"
+	cloneOptionExtension     = "hudson.plugins.git.extensions.impl.CloneOption"
+)
+
+func appendCloneTrait(traits *etree.Element, cloneOption *devopsv1alpha3.GitCloneOption) {
+	if cloneOption == nil {
+		return
+	}
+	appendCloneExtension(createTraitExtension(traits, cloneOptionTrait, cloneOptionExtension), cloneOption)
+}
+
+func appendCloneExtension(cloneExtension *etree.Element, cloneOption *devopsv1alpha3.GitCloneOption) {
+	cloneExtension.CreateElement("shallow").SetText(strconv.FormatBool(cloneOption.Shallow))
+	cloneExtension.CreateElement("noTags").SetText(strconv.FormatBool(cloneOption.NoTags))
+	cloneExtension.CreateElement("honorRefspec").SetText(strconv.FormatBool(true))
+	cloneExtension.CreateElement("reference").SetText(cloneOption.Reference)
+	if cloneOption.Timeout >= 0 {
+		cloneExtension.CreateElement("timeout").SetText(strconv.Itoa(cloneOption.Timeout))
+	} else {
+		cloneExtension.CreateElement("timeout").SetText(strconv.Itoa(10))
+	}
+
+	if cloneOption.Depth >= 0 {
+		cloneExtension.CreateElement("depth").SetText(strconv.Itoa(cloneOption.Depth))
+	} else {
+		cloneExtension.CreateElement("depth").SetText(strconv.Itoa(1))
+	}
+}
+
 func parseFromCloneTrait(cloneTrait *etree.Element) *devopsv1alpha3.GitCloneOption {
-	var cloneOption *devopsv1alpha3.GitCloneOption
-	if cloneTrait != nil {
-		if cloneExtension := cloneTrait.SelectElement("extension"); cloneExtension != nil {
-			cloneOption = &devopsv1alpha3.GitCloneOption{}
-			if shallow := cloneExtension.SelectElement("shallow"); shallow != nil {
-				if value, err := strconv.ParseBool(shallow.Text()); err == nil {
-					cloneOption.Shallow = value
-				}
+	if cloneTrait == nil {
+		return nil
+	}
+	return parseFromCloneExtension(cloneTrait.SelectElement("extension"))
+}
+
+func parseFromCloneExtension(cloneExtension *etree.Element) *devopsv1alpha3.GitCloneOption {
+	if cloneExtension == nil {
+		return nil
+	}
+	cloneOption := &devopsv1alpha3.GitCloneOption{}
+	if shallow := cloneExtension.SelectElement("shallow"); shallow != nil {
+		if value, err := strconv.ParseBool(shallow.Text()); err == nil {
+			cloneOption.Shallow = value
+		}
+	}
+	if noTags := cloneExtension.SelectElement("noTags"); noTags != nil {
+		if value, err := strconv.ParseBool(noTags.Text()); err == nil {
+			cloneOption.NoTags = value
+		}
+	}
+	if reference := cloneExtension.SelectElement("reference"); reference != nil {
+		cloneOption.Reference = reference.Text()
+	}
+	if timeout := cloneExtension.SelectElement("timeout"); timeout != nil {
+		if value, err := strconv.ParseInt(timeout.Text(), 10, 32); err == nil {
+			cloneOption.Timeout = int(value)
+		}
+	}
+	if depth := cloneExtension.SelectElement("depth"); depth != nil {
+		if value, err := strconv.ParseInt(depth.Text(), 10, 32); err == nil {
+			cloneOption.Depth = int(value)
+		}
+	}
+	return cloneOption
+}
+
+// appendCheckoutTraits writes the git plugin traits which are shared by all the git based SCM sources
+func appendCheckoutTraits(traits *etree.Element, checkoutOption *devopsv1alpha3.GitCheckoutOption) {
+	if checkoutOption == nil {
+		return
+	}
+	appendCheckoutExtensions(checkoutOption, func(trait, extensionClass string) *etree.Element {
+		return createTraitExtension(traits, trait, extensionClass)
+	})
+	if len(checkoutOption.RefSpecs) > 0 {
+		templatesEle := traits.CreateElement(refSpecsTrait).CreateElement("templates")
+		for _, refSpec := range checkoutOption.RefSpecs {
+			templatesEle.CreateElement(refSpecTemplate).CreateElement("value").SetText(refSpec)
+		}
+	}
+}
+
+// extensionCreator creates the element of a git extension, the extension is wrapped by the trait in the SCM sources
+// of multi-branch pipelines, but not in the GitSCM of single branch pipelines
+type extensionCreator func(trait, extensionClass string) *etree.Element
+
+// extensionFinder returns the element of a git extension, it is nil if the extension is not found
+type extensionFinder func(trait, extensionClass string) *etree.Element
+
+// appendCheckoutExtensions writes the git extensions of the checkout option except the refspecs, which are
+// not an extension
+func appendCheckoutExtensions(checkoutOption *devopsv1alpha3.GitCheckoutOption, create extensionCreator) {
+	if submodule := checkoutOption.Submodule; submodule != nil {
+		submoduleExtension := create(submoduleOptionTrait, "hudson.plugins.git.extensions.impl.SubmoduleOption")
+		submoduleExtension.CreateElement("disableSubmodules").SetText(strconv.FormatBool(false))
+		submoduleExtension.CreateElement("recursiveSubmodules").SetText(strconv.FormatBool(submodule.Recursive))
+		submoduleExtension.CreateElement("trackingSubmodules").SetText(strconv.FormatBool(false))
+		submoduleExtension.CreateElement("reference")
+		submoduleExtension.CreateElement("parentCredentials").SetText(strconv.FormatBool(submodule.ParentCredentials))
+		if submodule.Timeout > 0 {
+			submoduleExtension.CreateElement("timeout").SetText(strconv.Itoa(submodule.Timeout))
+		}
+		submoduleExtension.CreateElement("shallow").SetText(strconv.FormatBool(submodule.Shallow))
+		submoduleExtension.CreateElement("depth").SetText(strconv.Itoa(submodule.Depth))
+	}
+	if checkoutOption.LFS {
+		create(gitLFSPullTrait, "hudson.plugins.git.extensions.impl.GitLFSPull")
+	}
+	if len(checkoutOption.SparseCheckoutPaths) > 0 {
+		paths := create(sparseCheckoutPathsTrait, "hudson.plugins.git.extensions.impl.SparseCheckoutPaths").
+			CreateElement("sparseCheckoutPaths")
+		for _, path := range checkoutOption.SparseCheckoutPaths {
+			paths.CreateElement(sparseCheckoutPath).CreateElement("path").SetText(path)
+		}
+	}
+	if checkoutOption.CleanBeforeCheckout {
+		create(cleanBeforeCheckoutTrait, "hudson.plugins.git.extensions.impl.CleanBeforeCheckout").
+			CreateElement("deleteUntrackedNestedRepositories").SetText(strconv.FormatBool(false))
+	}
+	if checkoutOption.CleanAfterCheckout {
+		create(cleanAfterCheckoutTrait, "hudson.plugins.git.extensions.impl.CleanCheckout").
+			CreateElement("deleteUntrackedNestedRepositories").SetText(strconv.FormatBool(false))
+	}
+	if checkoutOption.LocalBranch {
+		create(localBranchTrait, "hudson.plugins.git.extensions.impl.LocalBranch").
+			CreateElement("localBranch").SetText("**")
+	}
+}
+
+// parseFromCheckoutTraits returns nil if there is no checkout related trait
+func parseFromCheckoutTraits(traits *etree.Element) *devopsv1alpha3.GitCheckoutOption {
+	if traits == nil {
+		return nil
+	}
+	checkoutOption, found := parseFromCheckoutExtensions(func(trait, _ string) *etree.Element {
+		if traitEle := traits.SelectElement(trait); traitEle != nil {
+			if extension := traitEle.SelectElement("extension"); extension != nil {
+				return extension
 			}
-			if timeout := cloneExtension.SelectElement("timeout"); timeout != nil {
-				if value, err := strconv.ParseInt(timeout.Text(), 10, 32); err == nil {
-					cloneOption.Timeout = int(value)
-				}
+			return traitEle
+		}
+		return nil
+	})
+	if refSpecs := traits.SelectElement(refSpecsTrait); refSpecs != nil {
+		found = true
+		if templates := refSpecs.SelectElement("templates"); templates != nil {
+			for _, template := range templates.SelectElements(refSpecTemplate) {
+				checkoutOption.RefSpecs = append(checkoutOption.RefSpecs, getChildText(template, "value"))
 			}
-			if depth := cloneExtension.SelectElement("depth"); depth != nil {
-				if value, err := strconv.ParseInt(depth.Text(), 10, 32); err == nil {
-					cloneOption.Depth = int(value)
-				}
+		}
+	}
+	if !found {
+		return nil
+	}
+	return checkoutOption
+}
+
+// parseFromCheckoutExtensions returns whether there is any checkout related extension
+func parseFromCheckoutExtensions(find extensionFinder) (*devopsv1alpha3.GitCheckoutOption, bool) {
+	checkoutOption := &devopsv1alpha3.GitCheckoutOption{}
+	found := false
+	if extension := find(submoduleOptionTrait, "hudson.plugins.git.extensions.impl.SubmoduleOption"); extension != nil {
+		found = true
+		submodule := &devopsv1alpha3.GitSubmoduleOption{}
+		if disable, _ := strconv.ParseBool(getChildText(extension, "disableSubmodules")); !disable {
+			submodule.Recursive, _ = strconv.ParseBool(getChildText(extension, "recursiveSubmodules"))
+			submodule.ParentCredentials, _ = strconv.ParseBool(getChildText(extension, "parentCredentials"))
+			submodule.Shallow, _ = strconv.ParseBool(getChildText(extension, "shallow"))
+			submodule.Timeout, _ = strconv.Atoi(getChildText(extension, "timeout"))
+			submodule.Depth, _ = strconv.Atoi(getChildText(extension, "depth"))
+			checkoutOption.Submodule = submodule
+		}
+	}
+	if find(gitLFSPullTrait, "hudson.plugins.git.extensions.impl.GitLFSPull") != nil {
+		found = true
+		checkoutOption.LFS = true
+	}
+	if extension := find(sparseCheckoutPathsTrait, "hudson.plugins.git.extensions.impl.SparseCheckoutPaths"); extension != nil {
+		found = true
+		if paths := extension.SelectElement("sparseCheckoutPaths"); paths != nil {
+			for _, path := range paths.SelectElements(sparseCheckoutPath) {
+				checkoutOption.SparseCheckoutPaths = append(checkoutOption.SparseCheckoutPaths, getChildText(path, "path"))
 			}
 		}
 	}
-	return cloneOption
+	if find(cleanBeforeCheckoutTrait, "hudson.plugins.git.extensions.impl.CleanBeforeCheckout") != nil {
+		found = true
+		checkoutOption.CleanBeforeCheckout = true
+	}
+	if find(cleanAfterCheckoutTrait, "hudson.plugins.git.extensions.impl.CleanCheckout") != nil {
+		found = true
+		checkoutOption.CleanAfterCheckout = true
+	}
+	if find(localBranchTrait, "hudson.plugins.git.extensions.impl.LocalBranch") != nil {
+		found = true
+		checkoutOption.LocalBranch = true
+	}
+	return checkoutOption, found
+}
+
+func createTraitExtension(traits *etree.Element, trait, extensionClass string) *etree.Element {
+	extension := traits.CreateElement(trait).CreateElement("extension")
+	extension.CreateAttr("class", extensionClass)
+	return extension
+}
+
+func getChildText(element *etree.Element, tag string) string {
+	if child := element.SelectElement(tag); child != nil {
+		return child.Text()
+	}
+	return ""
 }
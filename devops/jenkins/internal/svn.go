@@ -60,6 +60,24 @@ func AppendSingleSvnSourceToEtree(source *etree.Element, svnSource *devopsv1alph
 	scm.CreateAttr("class", "hudson.scm.SubversionSCM")
 	scm.CreateAttr("plugin", "subversion")
 
+	appendSvnModuleLocation(scm, svnSource)
+	appendSvnOptions(source)
+	return
+}
+
+// AppendSubversionSCMToEtree writes the SubversionSCM used by the single branch pipelines
+func AppendSubversionSCMToEtree(scm *etree.Element, svnSource *devopsv1alpha3.SingleSvnSource) {
+	if svnSource == nil {
+		klog.Warning("please provide SVN source when the sourceType is SVN")
+		return
+	}
+	scm.CreateAttr("class", "hudson.scm.SubversionSCM")
+	scm.CreateAttr("plugin", "subversion")
+	appendSvnModuleLocation(scm, svnSource)
+	appendSvnOptions(scm)
+}
+
+func appendSvnModuleLocation(scm *etree.Element, svnSource *devopsv1alpha3.SingleSvnSource) {
 	location := scm.CreateElement("locations").CreateElement("hudson.scm.SubversionSCM_-ModuleLocation")
 	if svnSource.Remote != "" {
 		location.CreateElement("remote").SetText(svnSource.Remote)
@@ -71,31 +89,36 @@ func AppendSingleSvnSourceToEtree(source *etree.Element, svnSource *devopsv1alph
 	location.CreateElement("depthOption").SetText("infinity")
 	location.CreateElement("ignoreExternalsOption").SetText("true")
 	location.CreateElement("cancelProcessOnExternalsFail").SetText("true")
+}
 
-	source.CreateElement("excludedRegions")
-	source.CreateElement("includedRegions")
-	source.CreateElement("excludedUsers")
-	source.CreateElement("excludedRevprop")
-	source.CreateElement("excludedCommitMessages")
-	source.CreateElement("workspaceUpdater").CreateAttr("class", "hudson.scm.subversion.UpdateUpdater")
-	source.CreateElement("ignoreDirPropChanges").SetText("false")
-	source.CreateElement("filterChangelog").SetText("false")
-	source.CreateElement("quietOperation").SetText("true")
-
-	return
+func appendSvnOptions(parent *etree.Element) {
+	parent.CreateElement("excludedRegions")
+	parent.CreateElement("includedRegions")
+	parent.CreateElement("excludedUsers")
+	parent.CreateElement("excludedRevprop")
+	parent.CreateElement("excludedCommitMessages")
+	parent.CreateElement("workspaceUpdater").CreateAttr("class", "hudson.scm.subversion.UpdateUpdater")
+	parent.CreateElement("ignoreDirPropChanges").SetText("false")
+	parent.CreateElement("filterChangelog").SetText("false")
+	parent.CreateElement("quietOperation").SetText("true")
 }
 
 func GetSingleSvnSourceFromEtree(source *etree.Element) *devopsv1alpha3.SingleSvnSource {
-	var s devopsv1alpha3.SingleSvnSource
 	if scm := source.SelectElement("scm"); scm != nil {
-		if locations := scm.SelectElement("locations"); locations != nil {
-			if moduleLocations := locations.SelectElement("hudson.scm.SubversionSCM_-ModuleLocation"); moduleLocations != nil {
-				if remote := moduleLocations.SelectElement("remote"); remote != nil {
-					s.Remote = remote.Text()
-				}
-				if credentialId := moduleLocations.SelectElement("credentialsId"); credentialId != nil {
-					s.CredentialId = credentialId.Text()
-				}
+		return GetSubversionSCMFromEtree(scm)
+	}
+	return &devopsv1alpha3.SingleSvnSource{}
+}
+
+func GetSubversionSCMFromEtree(scm *etree.Element) *devopsv1alpha3.SingleSvnSource {
+	var s devopsv1alpha3.SingleSvnSource
+	if locations := scm.SelectElement("locations"); locations != nil {
+		if moduleLocations := locations.SelectElement("hudson.scm.SubversionSCM_-ModuleLocation"); moduleLocations != nil {
+			if remote := moduleLocations.SelectElement("remote"); remote != nil {
+				s.Remote = remote.Text()
+			}
+			if credentialId := moduleLocations.SelectElement("credentialsId"); credentialId != nil {
+				s.CredentialId = credentialId.Text()
 			}
 		}
 	}
@@ -27,6 +27,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"k8s.io/klog/v2"
 
@@ -43,6 +44,8 @@ type Jenkins struct {
 	Server    string
 	Version   string
 	Requester *Requester
+	// ConfigHistory records the config of a job before it is updated, the history is not recorded if it is nil
+	ConfigHistory devops.ConfigHistoryStore
 }
 
 // Loggers
@@ -611,6 +614,10 @@ func (j *Jenkins) CheckCron(projectName string, httpParameters *devops.HttpParam
 		return nil, err
 	}
 
+	if !cron.UseJenkins {
+		return checkCronLocally(projectName, cron, time.Now()), nil
+	}
+
 	query := url.Values{
 		"value": []string{cron.Cron},
 	}
@@ -24,6 +24,8 @@ import (
 	"strconv"
 	"strings"
 
+	"k8s.io/klog/v2"
+
 	"github.com/opswave/go-jenkins/devops"
 )
 
@@ -45,6 +47,7 @@ type JobBuildStatus struct {
 }
 
 type InnerJob struct {
+	Class string `json:"_class"`
 	Name  string `json:"name"`
 	Url   string `json:"url"`
 	Color string `json:"color"`
@@ -269,6 +272,10 @@ func (j *Job) Copy(destinationName string) (*Job, error) {
 }
 
 func (j *Job) UpdateConfig(config string) error {
+	// the history is best effort, the config is still updated without it
+	if err := j.recordConfigHistory(); err != nil {
+		klog.Errorf("failed to record the config history of job %s: %+v", j.fullName(), err)
+	}
 
 	var querystring map[string]string
 
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+const branchSourcePath = "sources/data/jenkins.branch.BranchSource"
+
+// DiscoverProjectJobs walks the folder tree of a project, the supported jobs are converted to pipelines,
+// and the others are reported with the reason
+func (j *Jenkins) DiscoverProjectJobs(projectId string) ([]*devops.DiscoveredJob, error) {
+	// GetFolder does not keep the status code, so a missing project is checked at first
+	if _, err := j.GetJob(projectId); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	var jobs []*devops.DiscoveredJob
+	if err := j.discoverFolderJobs([]string{projectId}, &jobs); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return jobs, nil
+}
+
+// discoverFolderJobs appends the items of a folder and its sub folders, folderIds starts with the project
+func (j *Jenkins) discoverFolderJobs(folderIds []string, jobs *[]*devops.DiscoveredJob) error {
+	last := len(folderIds) - 1
+	folder, err := j.GetFolder(folderIds[last], folderIds[:last]...)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range folder.Raw.Jobs {
+		discovered := &devops.DiscoveredJob{
+			Path:  strings.Join(append(append([]string{}, folderIds[1:]...), item.Name), "/"),
+			Class: item.Class,
+		}
+		*jobs = append(*jobs, discovered)
+
+		switch item.Class {
+		case FolderClass:
+			discovered.Kind = devops.DiscoveredJobKindFolder
+			if err = j.discoverFolderJobs(append(append([]string{}, folderIds...), item.Name), jobs); err != nil {
+				return err
+			}
+		case FreeStyleProjectClass:
+			discovered.Kind = devops.DiscoveredJobKindFreestyle
+			discovered.Reason = "freestyle jobs cannot be converted to pipelines"
+		default:
+			if !isPipelineJobClass(item.Class) {
+				discovered.Kind = devops.DiscoveredJobKindUnsupported
+				discovered.Reason = fmt.Sprintf("unsupported job class %s", item.Class)
+				continue
+			}
+			job, err := j.GetJob(item.Name, folderIds...)
+			if err != nil {
+				return err
+			}
+			config, err := job.GetConfig()
+			if err != nil {
+				return err
+			}
+			convertDiscoveredJob(discovered, item.Name, config)
+		}
+	}
+	return nil
+}
+
+// convertDiscoveredJob converts the config of a pipeline job, a job whose config cannot be parsed is unsupported
+func convertDiscoveredJob(discovered *devops.DiscoveredJob, name, config string) {
+	pipeline, err := parseJobConfig(discovered.Class, name, config)
+	if err != nil {
+		discovered.Kind = devops.DiscoveredJobKindUnsupported
+		discovered.Reason = fmt.Sprintf("invalid config: %v", err)
+		return
+	}
+	pipeline.Name = name
+	discovered.Pipeline = pipeline
+
+	var unknowns []devopsv1alpha3.UnknownElement
+	switch pipeline.Spec.Type {
+	case devopsv1alpha3.NoScmPipelineType:
+		discovered.Kind = devops.DiscoveredJobKindPipeline
+		unknowns = pipeline.Spec.Pipeline.UnknownElements
+	case devopsv1alpha3.MultiBranchPipelineType:
+		discovered.Kind = devops.DiscoveredJobKindMultiBranchPipeline
+		unknowns = pipeline.Spec.MultiBranchPipeline.UnknownElements
+		// only the first branch source of a supported type can be converted
+		doc := etree.NewDocument()
+		if err = doc.ReadFromString(replaceXmlVersion(config, "1.1", "1.0")); err == nil {
+			if sources := doc.Root().FindElements(branchSourcePath); len(sources) > 1 ||
+				(len(sources) == 1 && pipeline.Spec.MultiBranchPipeline.SourceType == "") {
+				discovered.LossyFields = append(discovered.LossyFields, branchSourcePath)
+			}
+		}
+	case devopsv1alpha3.OrganizationFolderPipelineType:
+		discovered.Kind = devops.DiscoveredJobKindOrganizationFolder
+	}
+	for _, unknown := range unknowns {
+		field := unknown.Tag
+		if unknown.Path != "" {
+			field = unknown.Path + "/" + unknown.Tag
+		}
+		discovered.LossyFields = append(discovered.LossyFields, field)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+func TestDiscoverProjectJobs(t *testing.T) {
+	deployConfig, err := createPipelineConfigXml(&devopsv1alpha3.NoScmPipeline{Jenkinsfile: "node{echo 'hello'}"})
+	assert.Nil(t, err)
+	deployConfig = strings.Replace(deployConfig, "<disabled>", "<keepDependencies>true</keepDependencies>\n  <disabled>", 1)
+	buildConfig, err := createMultiBranchPipelineConfigXml("fake-project", &devopsv1alpha3.MultiBranchPipeline{
+		SourceType: devopsv1alpha3.SourceTypeGit,
+		GitSource:  &devopsv1alpha3.GitSource{Url: "https://github.com/kubesphere/ks-devops"},
+	})
+	assert.Nil(t, err)
+
+	jenkins, _ := newFakeJenkinsWithResponses(t, http.StatusOK, map[string]string{
+		"/job/fake-project/api/json": `{"jobs":[
+			{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"deploy"},
+			{"_class":"com.cloudbees.hudson.plugins.folder.Folder","name":"team"},
+			{"_class":"hudson.model.FreeStyleProject","name":"legacy"}]}`,
+		"/job/fake-project/job/deploy/api/json":    `{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"deploy"}`,
+		"/job/fake-project/job/deploy/config.xml/": deployConfig,
+		"/job/fake-project/job/team/api/json": `{"jobs":[
+			{"_class":"org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject","name":"build"},
+			{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"broken"},
+			{"_class":"hudson.matrix.MatrixProject","name":"matrix"}]}`,
+		"/job/fake-project/job/team/job/build/api/json":     `{"_class":"org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject","name":"build"}`,
+		"/job/fake-project/job/team/job/build/config.xml/":  buildConfig,
+		"/job/fake-project/job/team/job/broken/api/json":    `{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"broken"}`,
+		"/job/fake-project/job/team/job/broken/config.xml/": "<flow-definition",
+	})
+
+	jobs, err := jenkins.DiscoverProjectJobs("fake-project")
+	assert.Nil(t, err)
+	if !assert.Len(t, jobs, 6) {
+		return
+	}
+
+	assert.Equal(t, "deploy", jobs[0].Path)
+	assert.Equal(t, devops.DiscoveredJobKindPipeline, jobs[0].Kind)
+	assert.True(t, jobs[0].Supported())
+	assert.Equal(t, "deploy", jobs[0].Pipeline.Name)
+	assert.Equal(t, "node{echo 'hello'}", jobs[0].Pipeline.Spec.Pipeline.Jenkinsfile)
+	assert.Equal(t, []string{"keepDependencies"}, jobs[0].LossyFields)
+
+	assert.Equal(t, &devops.DiscoveredJob{Path: "team", Class: FolderClass, Kind: devops.DiscoveredJobKindFolder}, jobs[1])
+
+	assert.Equal(t, "team/build", jobs[2].Path)
+	assert.Equal(t, devops.DiscoveredJobKindMultiBranchPipeline, jobs[2].Kind)
+	assert.Equal(t, "build", jobs[2].Pipeline.Spec.MultiBranchPipeline.Name)
+	assert.Equal(t, "https://github.com/kubesphere/ks-devops", jobs[2].Pipeline.Spec.MultiBranchPipeline.GitSource.Url)
+	assert.Empty(t, jobs[2].LossyFields)
+
+	assert.Equal(t, "team/broken", jobs[3].Path)
+	assert.Equal(t, devops.DiscoveredJobKindUnsupported, jobs[3].Kind)
+	assert.False(t, jobs[3].Supported())
+	assert.Contains(t, jobs[3].Reason, "invalid config")
+
+	assert.Equal(t, &devops.DiscoveredJob{Path: "team/matrix", Class: "hudson.matrix.MatrixProject",
+		Kind: devops.DiscoveredJobKindUnsupported, Reason: "unsupported job class hudson.matrix.MatrixProject"}, jobs[4])
+	assert.Equal(t, "legacy", jobs[5].Path)
+	assert.Equal(t, devops.DiscoveredJobKindFreestyle, jobs[5].Kind)
+
+	jenkins, _ = newFakeJenkins(t, http.StatusNotFound)
+	_, err = jenkins.DiscoverProjectJobs("missing-project")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, http.StatusNotFound, err.(restful.ServiceError).Code)
+	}
+}
+
+func TestConvertDiscoveredJob_BranchSources(t *testing.T) {
+	config, err := createMultiBranchPipelineConfigXml("fake-project", &devopsv1alpha3.MultiBranchPipeline{
+		SourceType: devopsv1alpha3.SourceTypeGit,
+		GitSource:  &devopsv1alpha3.GitSource{Url: "https://github.com/kubesphere/ks-devops"},
+	})
+	assert.Nil(t, err)
+	start := strings.Index(config, "<jenkins.branch.BranchSource>")
+	end := strings.Index(config, "</jenkins.branch.BranchSource>") + len("</jenkins.branch.BranchSource>")
+	config = config[:end] + config[start:end] + config[end:]
+
+	discovered := &devops.DiscoveredJob{Class: WorkflowMultiBranchProjectClass}
+	convertDiscoveredJob(discovered, "build", config)
+	assert.True(t, discovered.Supported())
+	assert.Equal(t, []string{branchSourcePath}, discovered.LossyFields)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/beevik/etree"
+	"github.com/emicklei/go-restful"
+
+	"github.com/opswave/go-jenkins/devops"
+	"github.com/opswave/go-jenkins/devops/jenkins/internal"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+const (
+	OrganizationFolderTag = "jenkins.branch.OrganizationFolder"
+
+	workflowMultiBranchProjectFactoryTag = "org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProjectFactory"
+	periodicFolderTriggerTag             = "com.cloudbees.hudson.plugins.folder.computed.PeriodicFolderTrigger"
+)
+
+// the blue ocean SCM ids of the navigators, they are used to list the repositories of an organization
+var navigatorSCMIds = map[string]string{
+	devopsv1alpha3.NavigatorTypeGithub:    "github",
+	devopsv1alpha3.NavigatorTypeGitlab:    "gitlab",
+	devopsv1alpha3.NavigatorTypeBitbucket: "bitbucket-server",
+}
+
+func createOrganizationFolderConfigXml(folder *devopsv1alpha3.OrganizationFolder) (string, error) {
+	doc := etree.NewDocument()
+	xmlString := `<?xml version='1.0' encoding='UTF-8'?>
+<jenkins.branch.OrganizationFolder plugin="branch-api">
+  <actions/>
+  <properties>
+    <jenkins.branch.OrganizationChildHealthMetricsProperty>
+      <templates>
+        <com.cloudbees.hudson.plugins.folder.health.WorstChildHealthMetric plugin="cloudbees-folder">
+          <nonRecursive>false</nonRecursive>
+        </com.cloudbees.hudson.plugins.folder.health.WorstChildHealthMetric>
+      </templates>
+    </jenkins.branch.OrganizationChildHealthMetricsProperty>
+    <jenkins.branch.OrganizationChildOrphanedItemsProperty>
+      <strategy class="jenkins.branch.OrganizationChildOrphanedItemsProperty$Inherit"/>
+    </jenkins.branch.OrganizationChildOrphanedItemsProperty>
+    <jenkins.branch.OrganizationChildTriggersProperty>
+      <templates>
+        <com.cloudbees.hudson.plugins.folder.computed.PeriodicFolderTrigger plugin="cloudbees-folder">
+          <spec>H H/4 * * *</spec>
+          <interval>86400000</interval>
+        </com.cloudbees.hudson.plugins.folder.computed.PeriodicFolderTrigger>
+      </templates>
+    </jenkins.branch.OrganizationChildTriggersProperty>
+  </properties>
+  <folderViews class="jenkins.branch.OrganizationFolderViewHolder">
+    <owner reference="../.."/>
+  </folderViews>
+  <healthMetrics/>
+  <icon class="jenkins.branch.MetadataActionFolderIcon">
+    <owner class="jenkins.branch.OrganizationFolder" reference="../.."/>
+  </icon>
+</jenkins.branch.OrganizationFolder>`
+	if err := doc.ReadFromString(xmlString); err != nil {
+		return "", err
+	}
+
+	project := doc.SelectElement(OrganizationFolderTag)
+	project.CreateElement("description").SetText(folder.Description)
+
+	orphanedItemStrategy := project.CreateElement("orphanedItemStrategy")
+	orphanedItemStrategy.CreateAttr("class", "com.cloudbees.hudson.plugins.folder.computed.DefaultOrphanedItemStrategy")
+	orphanedItemStrategy.CreateAttr("plugin", "cloudbees-folder")
+	if folder.OrphanedItemPolicy != nil {
+		orphanedItemStrategy.CreateElement("pruneDeadBranches").SetText("true")
+		orphanedItemStrategy.CreateElement("daysToKeep").SetText(folder.OrphanedItemPolicy.DaysToKeep)
+		orphanedItemStrategy.CreateElement("numToKeep").SetText(folder.OrphanedItemPolicy.NumToKeep)
+	} else {
+		orphanedItemStrategy.CreateElement("pruneDeadBranches").SetText("false")
+	}
+
+	triggers := project.CreateElement("triggers")
+	if folder.TimerTrigger != nil {
+		timeTrigger := triggers.CreateElement(periodicFolderTriggerTag)
+		timeTrigger.CreateAttr("plugin", "cloudbees-folder")
+		millis, err := strconv.ParseInt(folder.TimerTrigger.Interval, 10, 64)
+		if err != nil {
+			return "", err
+		}
+		timeTrigger.CreateElement("spec").SetText(toCrontab(millis))
+		timeTrigger.CreateElement("interval").SetText(folder.TimerTrigger.Interval)
+	}
+	project.CreateElement("disabled").SetText("false")
+
+	if err := internal.AppendNavigatorToEtree(project.CreateElement("navigators"), folder); err != nil {
+		return "", err
+	}
+
+	factory := project.CreateElement("projectFactories").CreateElement(workflowMultiBranchProjectFactoryTag)
+	factory.CreateAttr("plugin", "workflow-multibranch")
+	factory.CreateElement("scriptPath").SetText(folder.ScriptPath)
+
+	project.CreateElement("buildStrategies")
+	strategy := project.CreateElement("strategy")
+	strategy.CreateAttr("class", "jenkins.branch.DefaultBranchPropertyStrategy")
+	strategy.CreateElement("properties").CreateAttr("class", "empty-list")
+
+	doc.Indent(2)
+	stringXml, err := doc.WriteToString()
+	return replaceXmlVersion(stringXml, "1.0", "1.1"), err
+}
+
+func parseOrganizationFolderConfigXml(config string) (*devopsv1alpha3.OrganizationFolder, error) {
+	folder := &devopsv1alpha3.OrganizationFolder{}
+	config = replaceXmlVersion(config, "1.1", "1.0")
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(config); err != nil {
+		return nil, err
+	}
+	project := doc.SelectElement(OrganizationFolderTag)
+	if project == nil {
+		return nil, fmt.Errorf("can not parse organization folder config")
+	}
+	folder.Description = getElementTextValueOrEmpty(project, "description")
+
+	if orphanedItemStrategy := project.SelectElement("orphanedItemStrategy"); orphanedItemStrategy != nil {
+		if getElementTextValueOrEmpty(orphanedItemStrategy, "pruneDeadBranches") == "true" {
+			folder.OrphanedItemPolicy = &devopsv1alpha3.DiscarderProperty{
+				DaysToKeep: getElementTextValueOrEmpty(orphanedItemStrategy, "daysToKeep"),
+				NumToKeep:  getElementTextValueOrEmpty(orphanedItemStrategy, "numToKeep"),
+			}
+		}
+	}
+	if triggers := project.SelectElement("triggers"); triggers != nil {
+		if timerTrigger := triggers.SelectElement(periodicFolderTriggerTag); timerTrigger != nil {
+			folder.TimerTrigger = &devopsv1alpha3.TimerTrigger{
+				Interval: getElementTextValueOrEmpty(timerTrigger, "interval"),
+			}
+		}
+	}
+
+	if !internal.GetNavigatorFromEtree(project.SelectElement("navigators"), folder) {
+		return nil, fmt.Errorf("can not find a supported navigator in organization folder config")
+	}
+
+	if factory := project.FindElement("projectFactories/" + workflowMultiBranchProjectFactoryTag); factory != nil {
+		folder.ScriptPath = getElementTextValueOrEmpty(factory, "scriptPath")
+	}
+	return folder, nil
+}
+
+// GetOrganizationFolderRepositories returns the names of the repositories which would be scanned by the organization folder,
+// the repositories come from the same data as GetOrgRepo
+func (j *Jenkins) GetOrganizationFolderRepositories(folder *devopsv1alpha3.OrganizationFolder, httpParameters *devops.HttpParameters) ([]string, error) {
+	scmId, ok := navigatorSCMIds[folder.Navigator]
+	if !ok {
+		err := fmt.Errorf("unsupport navigator type: %s", folder.Navigator)
+		return nil, restful.NewError(http.StatusBadRequest, err.Error())
+	}
+	var filter *regexp.Regexp
+	if folder.RepositoryFilter != "" {
+		var err error
+		// Jenkins matches the whole repository name
+		if filter, err = regexp.Compile("^(?:" + folder.RepositoryFilter + ")$"); err != nil {
+			return nil, restful.NewError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	orgRepo, err := j.GetOrgRepo(scmId, folder.Owner, httpParameters)
+	if err != nil {
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return filterOrgRepositories(orgRepo, filter), nil
+}
+
+func filterOrgRepositories(orgRepo devops.OrgRepo, filter *regexp.Regexp) []string {
+	repositories := make([]string, 0, len(orgRepo.Repositories.Items))
+	for _, item := range orgRepo.Repositories.Items {
+		if filter != nil && !filter.MatchString(item.Name) {
+			continue
+		}
+		repositories = append(repositories, item.Name)
+	}
+	return repositories
+}
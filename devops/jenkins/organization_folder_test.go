@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+func Test_OrganizationFolderConfig(t *testing.T) {
+	inputs := []*devopsv1alpha3.OrganizationFolder{
+		{
+			Description:      "for test",
+			Navigator:        devopsv1alpha3.NavigatorTypeGithub,
+			Owner:            "kubesphere",
+			CredentialId:     "github",
+			RepositoryFilter: "ks-.*",
+			ScriptPath:       "Jenkinsfile",
+			Traits: &devopsv1alpha3.OrganizationFolderTraits{
+				DiscoverBranches:     1,
+				DiscoverPRFromOrigin: 2,
+				DiscoverPRFromForks: &devopsv1alpha3.DiscoverPRFromForks{
+					Strategy: 1,
+					Trust:    1,
+				},
+				DiscoverTags: true,
+				CloneOption: &devopsv1alpha3.GitCloneOption{
+					Shallow: true,
+					Depth:   1,
+					Timeout: 10,
+				},
+				CheckoutOption: &devopsv1alpha3.GitCheckoutOption{
+					LFS: true,
+				},
+				RegexFilter: "master|release-.*",
+			},
+			TimerTrigger: &devopsv1alpha3.TimerTrigger{
+				Interval: "86400000",
+			},
+			OrphanedItemPolicy: &devopsv1alpha3.DiscarderProperty{
+				DaysToKeep: "7",
+				NumToKeep:  "10",
+			},
+		},
+		{
+			Navigator:    devopsv1alpha3.NavigatorTypeGitlab,
+			ServerName:   "default-gitlab",
+			Owner:        "devops",
+			CredentialId: "gitlab",
+			ScriptPath:   "ci/Jenkinsfile",
+			Traits: &devopsv1alpha3.OrganizationFolderTraits{
+				DiscoverBranches: 3,
+				DiscoverPRFromForks: &devopsv1alpha3.DiscoverPRFromForks{
+					Strategy: 2,
+					Trust:    3,
+				},
+			},
+		},
+		{
+			Navigator:    devopsv1alpha3.NavigatorTypeBitbucket,
+			ApiUri:       "https://bitbucket.example.com",
+			Owner:        "PROJ",
+			CredentialId: "bitbucket",
+			ScriptPath:   "Jenkinsfile",
+			Traits: &devopsv1alpha3.OrganizationFolderTraits{
+				DiscoverPRFromForks: &devopsv1alpha3.DiscoverPRFromForks{
+					Strategy: 1,
+					Trust:    2,
+				},
+			},
+		},
+		{
+			Navigator:  devopsv1alpha3.NavigatorTypeGithub,
+			ApiUri:     "https://github.example.com/api/v3",
+			Owner:      "linuxsuren",
+			ScriptPath: "Jenkinsfile",
+		},
+	}
+
+	for _, input := range inputs {
+		config, err := createOrganizationFolderConfigXml(input)
+		assert.Nil(t, err)
+		output, err := parseOrganizationFolderConfigXml(config)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+	}
+
+	_, err := createOrganizationFolderConfigXml(&devopsv1alpha3.OrganizationFolder{Navigator: "fake"})
+	assert.NotNil(t, err)
+
+	_, err = parseOrganizationFolderConfigXml(`<?xml version='1.1' encoding='UTF-8'?>
+<org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject/>`)
+	assert.NotNil(t, err)
+
+	_, err = parseOrganizationFolderConfigXml(`<?xml version='1.1' encoding='UTF-8'?>
+<jenkins.branch.OrganizationFolder><navigators/></jenkins.branch.OrganizationFolder>`)
+	assert.NotNil(t, err)
+}
+
+func TestGetProjectPipelineConfig_OrganizationFolder(t *testing.T) {
+	config, err := createOrganizationFolderConfigXml(&devopsv1alpha3.OrganizationFolder{
+		Navigator:    devopsv1alpha3.NavigatorTypeGithub,
+		Owner:        "kubesphere",
+		CredentialId: "github",
+		ScriptPath:   "Jenkinsfile",
+	})
+	assert.Nil(t, err)
+
+	jenkins, _ := newFakeJenkinsWithResponses(t, http.StatusOK, map[string]string{
+		"/job/fake-project/job/kubesphere/api/json":    `{"_class":"jenkins.branch.OrganizationFolder","name":"kubesphere"}`,
+		"/job/fake-project/job/kubesphere/config.xml/": config,
+	})
+	pipeline, err := jenkins.GetProjectPipelineConfig("fake-project", "kubesphere")
+	assert.Nil(t, err)
+	assert.Equal(t, devopsv1alpha3.OrganizationFolderPipelineType, pipeline.Spec.Type)
+	assert.Equal(t, "kubesphere", pipeline.Spec.OrganizationFolder.Name)
+	assert.Equal(t, "github", pipeline.Spec.OrganizationFolder.CredentialId)
+}
+
+func TestGetOrganizationFolderRepositories(t *testing.T) {
+	jenkins, requests := newFakeJenkinsWithResponses(t, http.StatusOK, map[string]string{
+		"/blue/rest/organizations/jenkins/scm/github/organizations/kubesphere/repositories/": `{"repositories":{"items":[
+			{"name":"ks-devops"},{"name":"kubesphere"},{"name":"ks-installer"},{"name":"fake-ks-devops"}]}}`,
+	})
+	httpParameters := &devops.HttpParameters{Method: http.MethodGet, Url: &url.URL{RawQuery: "pageNumber=1&pageSize=100"}}
+
+	repositories, err := jenkins.GetOrganizationFolderRepositories(&devopsv1alpha3.OrganizationFolder{
+		Navigator:        devopsv1alpha3.NavigatorTypeGithub,
+		Owner:            "kubesphere",
+		RepositoryFilter: "ks-.*",
+	}, httpParameters)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ks-devops", "ks-installer"}, repositories)
+	assert.Equal(t, "/blue/rest/organizations/jenkins/scm/github/organizations/kubesphere/repositories/", (*requests)[0].path)
+
+	repositories, err = jenkins.GetOrganizationFolderRepositories(&devopsv1alpha3.OrganizationFolder{
+		Navigator: devopsv1alpha3.NavigatorTypeGithub,
+		Owner:     "kubesphere",
+	}, httpParameters)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ks-devops", "kubesphere", "ks-installer", "fake-ks-devops"}, repositories)
+
+	_, err = jenkins.GetOrganizationFolderRepositories(&devopsv1alpha3.OrganizationFolder{
+		Navigator: "fake",
+	}, httpParameters)
+	assert.Equal(t, http.StatusBadRequest, err.(restful.ServiceError).Code)
+
+	_, err = jenkins.GetOrganizationFolderRepositories(&devopsv1alpha3.OrganizationFolder{
+		Navigator:        devopsv1alpha3.NavigatorTypeGithub,
+		RepositoryFilter: "(",
+	}, httpParameters)
+	assert.Equal(t, http.StatusBadRequest, err.(restful.ServiceError).Code)
+}
@@ -31,6 +31,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/opswave/go-jenkins/devops"
+	"github.com/opswave/go-jenkins/devops/jenkins/cron"
 )
 
 type Pipeline struct {
@@ -85,6 +86,10 @@ const (
 	CheckCronUrl         = "/job/%s/descriptorByName/hudson.triggers.TimerTrigger/checkSpec?%s"
 
 	cronJobLayout = "Monday, January 2, 2006 15:04:05 PM"
+	// cronMessageLayout is the layout of the times in the message of a cron check, the same as Jenkins
+	cronMessageLayout = "Monday, January 2, 2006 3:04:05 PM MST"
+	defaultCronCount  = 5
+	maxCronCount      = 100
 
 	CheckPipelineName = "/job/%s/checkJobName?"
 )
@@ -880,6 +885,52 @@ func parseCronJobTime(msg string) (string, string, error) {
 	return last, next, nil
 }
 
+// checkCronLocally checks the cron in the same way as the checkSpec of hudson.triggers.TimerTrigger without Jenkins,
+// the H fields are hashed by the full name of the pipeline, or the project if the pipeline haven't created
+func checkCronLocally(projectName string, data *devops.CronData, now time.Time) *devops.CheckCronRes {
+	seed := projectName
+	if data.PipelineName != "" {
+		seed = projectName + "/" + data.PipelineName
+	}
+	schedule, err := cron.Parse(data.Cron, seed)
+	if err != nil {
+		return &devops.CheckCronRes{Result: "error", Message: err.Error()}
+	}
+
+	count := data.Count
+	if count <= 0 {
+		count = defaultCronCount
+	} else if count > maxCronCount {
+		count = maxCronCount
+	}
+	res := &devops.CheckCronRes{Result: "ok"}
+	for _, next := range schedule.NextN(now, count) {
+		res.NextTimes = append(res.NextTimes, next.Format(time.RFC3339))
+	}
+	if len(res.NextTimes) == 0 {
+		res.Result = "warning"
+		res.Message = "No schedules so will never run"
+		return res
+	}
+
+	next := schedule.Next(now)
+	res.NextTime = next.Format(time.RFC3339)
+	last := schedule.Prev(now)
+	if !last.IsZero() {
+		res.LastTime = last.Format(time.RFC3339)
+	}
+	if warning := schedule.Warning(); warning != "" {
+		res.Result = "warning"
+		res.Message = warning
+	} else if last.IsZero() {
+		res.Message = fmt.Sprintf("Would next run at %s.", next.Format(cronMessageLayout))
+	} else {
+		res.Message = fmt.Sprintf("Would last have run at %s; would next run at %s.",
+			last.Format(cronMessageLayout), next.Format(cronMessageLayout))
+	}
+	return res
+}
+
 func (p *Pipeline) ToJenkinsfile() (*devops.ResJenkinsfile, error) {
 	res, err := p.Jenkins.SendPureRequest(p.Path, p.HttpParameters)
 	if err != nil {
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+// ListDownstreamPipelines walks the upstream triggers of the pipelines in a project breadth first, the pipelines
+// in other folders are not included. Each pipeline is reported once, with its shortest distance from the given one
+func (j *Jenkins) ListDownstreamPipelines(projectId, pipelineId string) ([]*devops.PipelineDependency, error) {
+	// the pipelines which cannot be read are logged by listProjectPipelines, their triggers are not followed
+	pipelines, unreadable, err := j.listProjectPipelines(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, found := unreadable[pipelineId]
+	// the pipelines triggered by the key
+	downstreams := map[string][]*devops.PipelineDependency{}
+	for _, pipeline := range pipelines {
+		if pipeline.Name == pipelineId {
+			found = true
+		}
+		trigger := upstreamTriggerOf(pipeline)
+		if trigger == nil {
+			continue
+		}
+		threshold := trigger.Threshold
+		if threshold == "" {
+			threshold = devopsv1alpha3.UpstreamThresholdSuccess
+		}
+		for _, project := range trigger.Projects {
+			upstream, ok := resolveUpstreamProject(projectId, project)
+			if !ok {
+				continue
+			}
+			downstreams[upstream] = append(downstreams[upstream], &devops.PipelineDependency{
+				Pipeline:  pipeline.Name,
+				Upstream:  upstream,
+				Threshold: threshold,
+			})
+		}
+	}
+	if !found {
+		return nil, restful.NewError(http.StatusNotFound, fmt.Sprintf("pipeline %s not found in project %s", pipelineId, projectId))
+	}
+
+	var result []*devops.PipelineDependency
+	visited := map[string]bool{pipelineId: true}
+	current := []string{pipelineId}
+	for depth := 1; len(current) > 0; depth++ {
+		var next []string
+		for _, upstream := range current {
+			dependencies := downstreams[upstream]
+			sort.Slice(dependencies, func(i, k int) bool {
+				return dependencies[i].Pipeline < dependencies[k].Pipeline
+			})
+			for _, dependency := range dependencies {
+				if visited[dependency.Pipeline] {
+					continue
+				}
+				visited[dependency.Pipeline] = true
+				dependency.Depth = depth
+				result = append(result, dependency)
+				next = append(next, dependency.Pipeline)
+			}
+		}
+		current = next
+	}
+	return result, nil
+}
+
+func upstreamTriggerOf(pipeline *devopsv1alpha3.Pipeline) *devopsv1alpha3.UpstreamTrigger {
+	if pipeline.Spec.Type != devopsv1alpha3.NoScmPipelineType || pipeline.Spec.Pipeline == nil {
+		return nil
+	}
+	return pipeline.Spec.Pipeline.UpstreamTrigger
+}
+
+// resolveUpstreamProject returns the name of the upstream pipeline in the project, Jenkins resolves the names
+// relative to the folder of the downstream job first, then from the root
+func resolveUpstreamProject(projectId, project string) (string, bool) {
+	project = strings.TrimSpace(project)
+	if strings.HasPrefix(project, "/") {
+		project = strings.TrimPrefix(project, "/")
+		if !strings.HasPrefix(project, projectId+"/") {
+			return "", false
+		}
+	}
+	project = strings.TrimPrefix(project, projectId+"/")
+	if project == "" || strings.Contains(project, "/") {
+		return "", false
+	}
+	return project, true
+}
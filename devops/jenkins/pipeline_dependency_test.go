@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+func newFakeDependencyConfig(t *testing.T, upstream *devopsv1alpha3.UpstreamTrigger) string {
+	config, err := createPipelineConfigXml(&devopsv1alpha3.NoScmPipeline{
+		Jenkinsfile:     "node{echo 'hello'}",
+		UpstreamTrigger: upstream,
+	})
+	assert.Nil(t, err)
+	return config
+}
+
+func TestListDownstreamPipelines(t *testing.T) {
+	configs := map[string]string{
+		"build": newFakeDependencyConfig(t, nil),
+		"test":  newFakeDependencyConfig(t, &devopsv1alpha3.UpstreamTrigger{Projects: []string{"build"}}),
+		"scan": newFakeDependencyConfig(t, &devopsv1alpha3.UpstreamTrigger{
+			Projects:  []string{"/p/build"},
+			Threshold: devopsv1alpha3.UpstreamThresholdUnstable,
+		}),
+		// triggered by both test and scan, it is reported once
+		"deploy": newFakeDependencyConfig(t, &devopsv1alpha3.UpstreamTrigger{Projects: []string{"p/test", "scan"}}),
+		// the cycle does not loop forever
+		"notify": newFakeDependencyConfig(t, &devopsv1alpha3.UpstreamTrigger{Projects: []string{"deploy", "notify"}}),
+		// the pipelines in other projects are ignored
+		"other": newFakeDependencyConfig(t, &devopsv1alpha3.UpstreamTrigger{Projects: []string{"/q/build"}}),
+		// the pipeline which cannot be read is skipped
+		"broken": `<flow-definition plugin="workflow-job">`,
+	}
+	jenkins, _ := newStatefulFakeJenkins(t, "p", configs)
+
+	dependencies, err := jenkins.ListDownstreamPipelines("p", "build")
+	assert.Nil(t, err)
+	assert.Equal(t, []*devops.PipelineDependency{
+		{Pipeline: "scan", Upstream: "build", Threshold: devopsv1alpha3.UpstreamThresholdUnstable, Depth: 1},
+		{Pipeline: "test", Upstream: "build", Threshold: devopsv1alpha3.UpstreamThresholdSuccess, Depth: 1},
+		{Pipeline: "deploy", Upstream: "scan", Threshold: devopsv1alpha3.UpstreamThresholdSuccess, Depth: 2},
+		{Pipeline: "notify", Upstream: "deploy", Threshold: devopsv1alpha3.UpstreamThresholdSuccess, Depth: 3},
+	}, dependencies)
+
+	dependencies, err = jenkins.ListDownstreamPipelines("p", "notify")
+	assert.Nil(t, err)
+	assert.Empty(t, dependencies)
+
+	dependencies, err = jenkins.ListDownstreamPipelines("p", "broken")
+	assert.Nil(t, err)
+	assert.Empty(t, dependencies)
+
+	_, err = jenkins.ListDownstreamPipelines("p", "missing")
+	assert.Equal(t, http.StatusNotFound, err.(restful.ServiceError).Code)
+}
+
+func TestResolveUpstreamProject(t *testing.T) {
+	for project, expected := range map[string]string{
+		"build":        "build",
+		" build ":      "build",
+		"p/build":      "build",
+		"/p/build":     "build",
+		"/q/build":     "",
+		"q/build":      "",
+		"p/folder/job": "",
+		"":             "",
+	} {
+		name, ok := resolveUpstreamProject("p", project)
+		assert.Equal(t, expected, name, project)
+		assert.Equal(t, expected != "", ok, project)
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/opswave/go-jenkins/devops"
+	"github.com/opswave/go-jenkins/devops/util/reflectutils"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+// DiffProjectPipeline compares a pipeline with the config of its job in Jenkins, the job is missing if it does not exist
+func (j *Jenkins) DiffProjectPipeline(projectId string, pipeline *devopsv1alpha3.Pipeline) (*devops.PipelineDrift, error) {
+	actual, err := j.GetProjectPipelineConfig(projectId, pipeline.Name)
+	if err != nil {
+		if serviceErr, ok := err.(restful.ServiceError); ok && serviceErr.Code == http.StatusNotFound {
+			return &devops.PipelineDrift{Pipeline: pipeline.Name, Missing: true}, nil
+		}
+		return nil, err
+	}
+	return diffPipeline(pipeline, actual)
+}
+
+// ScanProjectPipelineDrift compares all pipelines of a project with the jobs in Jenkins, every pipeline is reported
+// in the given order, followed by the jobs which have no pipeline
+func (j *Jenkins) ScanProjectPipelineDrift(projectId string, pipelines []*devopsv1alpha3.Pipeline) ([]*devops.PipelineDrift, error) {
+	jobs, unreadable, err := j.listProjectPipelines(projectId)
+	if err != nil {
+		return nil, err
+	}
+	actualPipelines := make(map[string]*devopsv1alpha3.Pipeline, len(jobs))
+	for _, job := range jobs {
+		actualPipelines[job.Name] = job
+	}
+
+	drifts := make([]*devops.PipelineDrift, 0, len(jobs))
+	managed := make(map[string]bool, len(pipelines))
+	for _, pipeline := range pipelines {
+		managed[pipeline.Name] = true
+		if readErr, ok := unreadable[pipeline.Name]; ok {
+			drifts = append(drifts, &devops.PipelineDrift{Pipeline: pipeline.Name, Error: readErr.Error()})
+			continue
+		}
+		actual, ok := actualPipelines[pipeline.Name]
+		if !ok {
+			drifts = append(drifts, &devops.PipelineDrift{Pipeline: pipeline.Name, Missing: true})
+			continue
+		}
+		drift, err := diffPipeline(pipeline, actual)
+		if err != nil {
+			return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+		}
+		drifts = append(drifts, drift)
+	}
+	for _, job := range jobs {
+		if !managed[job.Name] {
+			drifts = append(drifts, &devops.PipelineDrift{Pipeline: job.Name, Unmanaged: true})
+		}
+	}
+	for _, name := range unreadablePipelineNames(unreadable) {
+		if !managed[name] {
+			drifts = append(drifts, &devops.PipelineDrift{Pipeline: name, Unmanaged: true, Error: unreadable[name].Error()})
+		}
+	}
+	return drifts, nil
+}
+
+// diffPipeline returns the field level differences between the spec of the desired and the actual pipeline
+func diffPipeline(desired, actual *devopsv1alpha3.Pipeline) (*devops.PipelineDrift, error) {
+	desiredSpec, err := normalizePipelineSpec(desired.Name, &desired.Spec)
+	if err != nil {
+		return nil, err
+	}
+	actualSpec, err := normalizePipelineSpec(desired.Name, &actual.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := &devops.PipelineDrift{Pipeline: desired.Name}
+	for _, diff := range reflectutils.Differences(desiredSpec, actualSpec) {
+		drift.Diffs = append(drift.Diffs, devops.PipelineFieldDiff{
+			Field:   diff.Path,
+			Desired: diff.A,
+			Actual:  diff.B,
+		})
+	}
+	return drift, nil
+}
+
+// normalizePipelineSpec makes the specs comparable: the name comes from the pipeline, the unknown elements only
+// exist in Jenkins, and the empty slices are the same as nil ones after a JSON round trip
+func normalizePipelineSpec(name string, spec *devopsv1alpha3.PipelineSpec) (*devopsv1alpha3.PipelineSpec, error) {
+	normalized := spec.DeepCopy()
+	if normalized.Pipeline != nil {
+		normalized.Pipeline.Name = name
+		normalized.Pipeline.UnknownElements = nil
+	}
+	if normalized.MultiBranchPipeline != nil {
+		normalized.MultiBranchPipeline.Name = name
+		normalized.MultiBranchPipeline.UnknownElements = nil
+	}
+	if normalized.OrganizationFolder != nil {
+		normalized.OrganizationFolder.Name = name
+	}
+
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, err
+	}
+	result := &devopsv1alpha3.PipelineSpec{}
+	if err = json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
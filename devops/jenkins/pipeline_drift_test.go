@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+func newFakeDesiredPipelines() (deploy, build *devopsv1alpha3.Pipeline) {
+	deploy = &devopsv1alpha3.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "fake-project"},
+		Spec: devopsv1alpha3.PipelineSpec{
+			Type: devopsv1alpha3.NoScmPipelineType,
+			Pipeline: &devopsv1alpha3.NoScmPipeline{
+				Name:        "deploy",
+				Jenkinsfile: `withCredentials([kubeconfigContent(credentialsId: 'kubeconfig', variable: 'VARIABLE')]) { sh 'kubectl apply' }`,
+				Parameters:  []devopsv1alpha3.ParameterDefinition{},
+			},
+		},
+	}
+	build = &devopsv1alpha3.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "fake-project"},
+		Spec: devopsv1alpha3.PipelineSpec{
+			Type: devopsv1alpha3.MultiBranchPipelineType,
+			MultiBranchPipeline: &devopsv1alpha3.MultiBranchPipeline{
+				SourceType: devopsv1alpha3.SourceTypeGit,
+				GitSource:  &devopsv1alpha3.GitSource{Url: "https://github.com/kubesphere/ks-devops", CredentialId: "github"},
+			},
+		},
+	}
+	return
+}
+
+func TestDiffProjectPipeline(t *testing.T) {
+	jenkins, _ := newFakeJenkinsWithResponses(t, http.StatusOK, newFakeProjectResponses(t))
+	deploy, build := newFakeDesiredPipelines()
+
+	drift, err := jenkins.DiffProjectPipeline("fake-project", deploy)
+	assert.Nil(t, err)
+	assert.Equal(t, &devops.PipelineDrift{Pipeline: "deploy"}, drift)
+	assert.False(t, drift.Drifted())
+
+	build.Spec.MultiBranchPipeline.GitSource.CredentialId = "gitlab"
+	build.Spec.MultiBranchPipeline.ScriptPath = "ci/Jenkinsfile"
+	drift, err = jenkins.DiffProjectPipeline("fake-project", build)
+	assert.Nil(t, err)
+	assert.True(t, drift.Drifted())
+	assert.ElementsMatch(t, []devops.PipelineFieldDiff{{
+		Field:   "MultiBranchPipeline.ScriptPath",
+		Desired: "ci/Jenkinsfile",
+		Actual:  "",
+	}, {
+		Field:   "MultiBranchPipeline.GitSource.CredentialId",
+		Desired: "gitlab",
+		Actual:  "github",
+	}}, drift.Diffs)
+}
+
+func TestScanProjectPipelineDrift(t *testing.T) {
+	jenkins, _ := newFakeJenkinsWithResponses(t, http.StatusOK, newFakeProjectResponses(t))
+	_, build := newFakeDesiredPipelines()
+	missing := &devopsv1alpha3.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "fake-project"},
+		Spec: devopsv1alpha3.PipelineSpec{
+			Type:     devopsv1alpha3.NoScmPipelineType,
+			Pipeline: &devopsv1alpha3.NoScmPipeline{Name: "missing"},
+		},
+	}
+
+	drifts, err := jenkins.ScanProjectPipelineDrift("fake-project", []*devopsv1alpha3.Pipeline{build, missing})
+	assert.Nil(t, err)
+	assert.Equal(t, []*devops.PipelineDrift{
+		{Pipeline: "build"},
+		{Pipeline: "missing", Missing: true},
+		{Pipeline: "deploy", Unmanaged: true},
+	}, drifts)
+
+	t.Run("unreadable pipeline", func(t *testing.T) {
+		jenkins, _ := newFakeJenkinsWithResponses(t, http.StatusOK, newFakeUnreadableProjectResponses(t))
+		broken := &devopsv1alpha3.Pipeline{
+			ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "fake-project"},
+			Spec: devopsv1alpha3.PipelineSpec{
+				Type:     devopsv1alpha3.NoScmPipelineType,
+				Pipeline: &devopsv1alpha3.NoScmPipeline{Name: "broken"},
+			},
+		}
+
+		drifts, err := jenkins.ScanProjectPipelineDrift("fake-project", []*devopsv1alpha3.Pipeline{build, broken})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(drifts))
+		assert.Equal(t, &devops.PipelineDrift{Pipeline: "build"}, drifts[0])
+		assert.Equal(t, "broken", drifts[1].Pipeline)
+		assert.False(t, drifts[1].Missing)
+		assert.NotEmpty(t, drifts[1].Error)
+		assert.Equal(t, &devops.PipelineDrift{Pipeline: "deploy", Unmanaged: true}, drifts[2])
+
+		drifts, err = jenkins.ScanProjectPipelineDrift("fake-project", []*devopsv1alpha3.Pipeline{build})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(drifts))
+		assert.Equal(t, "broken", drifts[2].Pipeline)
+		assert.True(t, drifts[2].Unmanaged)
+		assert.NotEmpty(t, drifts[2].Error)
+	})
+}
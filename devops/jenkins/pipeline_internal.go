@@ -59,7 +59,10 @@ func createPipelineConfigXml(pipeline *devopsv1alpha3.NoScmPipeline) (string, er
 	properties := flow.CreateElement("properties")
 
 	if pipeline.DisableConcurrent {
-		properties.CreateElement("org.jenkinsci.plugins.workflow.job.properties.DisableConcurrentBuildsJobProperty")
+		concurrent := properties.CreateElement("org.jenkinsci.plugins.workflow.job.properties.DisableConcurrentBuildsJobProperty")
+		if pipeline.AbortPrevious {
+			concurrent.CreateElement(AbortPreviousTag).SetText("true")
+		}
 	}
 
 	if pipeline.Discarder != nil {
@@ -68,40 +71,51 @@ func createPipelineConfigXml(pipeline *devopsv1alpha3.NoScmPipeline) (string, er
 		strategy.CreateAttr("class", "hudson.tasks.LogRotator")
 		strategy.CreateElement("daysToKeep").SetText(pipeline.Discarder.DaysToKeep)
 		strategy.CreateElement("numToKeep").SetText(pipeline.Discarder.NumToKeep)
-		strategy.CreateElement("artifactDaysToKeep").SetText("-1")
-		strategy.CreateElement("artifactNumToKeep").SetText("-1")
+		strategy.CreateElement("artifactDaysToKeep").SetText(artifactDiscarderValue(pipeline.Discarder.ArtifactDaysToKeep))
+		strategy.CreateElement("artifactNumToKeep").SetText(artifactDiscarderValue(pipeline.Discarder.ArtifactNumToKeep))
 	}
+	replaceJobPropertiesInEtree(properties, pipeline)
 	if pipeline.Parameters != nil {
 		replaceParametersInEtree(properties, pipeline.Parameters)
 	}
 
 	// create trigger xml structure
-	if pipeline.TimerTrigger != nil || pipeline.GenericWebhook != nil {
+	if hasPipelineTriggers(pipeline) {
 		triggersEle := properties.
 			CreateElement("org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty").
 			CreateElement("triggers")
 
 		if pipeline.TimerTrigger != nil {
 			triggersEle.CreateElement("hudson.triggers.TimerTrigger").CreateElement("spec").
-				SetText(pipeline.TimerTrigger.Cron)
+				SetText(timerTriggerSpec(pipeline.TimerTrigger))
 		}
 
 		triggers.CreateGenericWebhookXML(triggersEle, pipeline.GenericWebhook)
+		triggers.CreateUpstreamTriggerXML(triggersEle, pipeline.UpstreamTrigger)
+		triggers.CreateScmTriggerXML(triggersEle, pipeline.ScmTrigger)
+		triggers.CreateGithubPushTriggerXML(triggersEle, pipeline.GithubPushTrigger)
+		triggers.CreateGitlabPushTriggerXML(triggersEle, pipeline.GitlabPushTrigger)
 	}
 
-	pipelineDefine := flow.CreateElement("definition")
-	pipelineDefine.CreateAttr("class", "org.jenkinsci.plugins.workflow.cps.CpsFlowDefinition")
-	pipelineDefine.CreateAttr("plugin", "workflow-cps")
-	pipelineDefine.CreateElement("script").SetText(pipeline.Jenkinsfile)
-
-	pipelineDefine.CreateElement("sandbox").SetText("true")
+	pipelineDefine, err := createPipelineDefinition(pipeline)
+	if err != nil {
+		return "", err
+	}
+	flow.AddChild(pipelineDefine)
 
 	flow.CreateElement("triggers")
 
+	if pipeline.QuietPeriod != nil {
+		flow.CreateElement(QuietPeriodTag).SetText(strconv.Itoa(*pipeline.QuietPeriod))
+	}
 	if pipeline.RemoteTrigger != nil {
 		flow.CreateElement("authToken").SetText(pipeline.RemoteTrigger.Token)
 	}
-	flow.CreateElement("disabled").SetText("false")
+	flow.CreateElement(DisabledTag).SetText(strconv.FormatBool(pipeline.Disabled))
+
+	if err := appendUnknownElements(flow, pipeline.UnknownElements); err != nil {
+		return "", err
+	}
 
 	doc.Indent(2)
 	stringXml, err := doc.WriteToString()
@@ -123,7 +137,13 @@ func updatePipelineConfigXml(config string, pipeline *devopsv1alpha3.NoScmPipeli
 	// update properties
 	properties := flow.SelectElement(PropertiesTag)
 	if pipeline.DisableConcurrent {
-		addOrUpdateElement(properties, DisableConcurrentJobTag, StringNull)
+		concurrent := addOrUpdateElement(properties, DisableConcurrentJobTag, StringNull)
+		if pipeline.AbortPrevious {
+			addOrUpdateElement(concurrent, AbortPreviousTag, "true")
+		} else if abortPrevious := concurrent.SelectElement(AbortPreviousTag); abortPrevious != nil {
+			// newer Jenkins writes the default value as well
+			abortPrevious.SetText("false")
+		}
 	} else {
 		removeChildElement(properties, DisableConcurrentJobTag)
 	}
@@ -136,11 +156,12 @@ func updatePipelineConfigXml(config string, pipeline *devopsv1alpha3.NoScmPipeli
 		replaceAttr(strategy, ClassKey, "hudson.tasks.LogRotator")
 		addOrUpdateElement(strategy, DaysToKeepTag, pipeline.Discarder.DaysToKeep)
 		addOrUpdateElement(strategy, NumToKeepTag, pipeline.Discarder.NumToKeep)
-		addOrUpdateElement(strategy, ArtiDaysToKeepTag, "-1")
-		addOrUpdateElement(strategy, ArtiNumToKeepTag, "-1")
+		addOrUpdateElement(strategy, ArtiDaysToKeepTag, artifactDiscarderValue(pipeline.Discarder.ArtifactDaysToKeep))
+		addOrUpdateElement(strategy, ArtiNumToKeepTag, artifactDiscarderValue(pipeline.Discarder.ArtifactNumToKeep))
 	} else {
 		removeChildElement(properties, BuildDiscarderTag)
 	}
+	replaceJobPropertiesInEtree(properties, pipeline)
 
 	if pipeline.Parameters != nil { // overwrite parameters
 		replaceParametersInEtree(properties, pipeline.Parameters)
@@ -148,42 +169,96 @@ func updatePipelineConfigXml(config string, pipeline *devopsv1alpha3.NoScmPipeli
 		removeChildElement(properties, ParamDefiPropTag)
 	}
 
-	// update triggers xml structure
-	var pipelineTriggerEle, triggersEle *etree.Element
-	pipelineTriggerEle = addOrUpdateElement(properties, PipelineTriggersJobTag, StringNull)
-	triggersEle = addOrUpdateElement(pipelineTriggerEle, TriggersTag, StringNull)
+	// update triggers xml structure, the trigger property is created only when there is a trigger
+	// to keep the config same as the one created by createPipelineConfigXml
+	if hasPipelineTriggers(pipeline) || properties.SelectElement(PipelineTriggersJobTag) != nil {
+		var pipelineTriggerEle, triggersEle *etree.Element
+		pipelineTriggerEle = addOrUpdateElement(properties, PipelineTriggersJobTag, StringNull)
+		triggersEle = addOrUpdateElement(pipelineTriggerEle, TriggersTag, StringNull)
 
-	if pipeline.TimerTrigger != nil {
-		timerTriggerEle := addOrUpdateElement(triggersEle, TimerTriggerTag, StringNull)
-		addOrUpdateElement(timerTriggerEle, "spec", pipeline.TimerTrigger.Cron)
-	} else {
-		removeChildElement(triggersEle, TimerTriggerTag)
-	}
+		if pipeline.TimerTrigger != nil {
+			timerTriggerEle := addOrUpdateElement(triggersEle, TimerTriggerTag, StringNull)
+			addOrUpdateElement(timerTriggerEle, "spec", timerTriggerSpec(pipeline.TimerTrigger))
+		} else {
+			removeChildElement(triggersEle, TimerTriggerTag)
+		}
 
-	if pipeline.GenericWebhook != nil {
-		// TODO issue: if support GenericWebhook in console, need to delete GenericWebhook tag when pipeline.GenericWebhook is nil;
-		triggers.CreateGenericWebhookXML(triggersEle, pipeline.GenericWebhook)
+		if pipeline.GenericWebhook != nil {
+			// TODO issue: if support GenericWebhook in console, need to delete GenericWebhook tag when pipeline.GenericWebhook is nil;
+			triggers.CreateGenericWebhookXML(triggersEle, pipeline.GenericWebhook)
+		}
+
+		if pipeline.UpstreamTrigger != nil {
+			triggers.CreateUpstreamTriggerXML(triggersEle, pipeline.UpstreamTrigger)
+		} else {
+			removeChildElement(triggersEle, triggers.ReverseBuildTriggerTag)
+		}
+		if pipeline.ScmTrigger != nil {
+			triggers.CreateScmTriggerXML(triggersEle, pipeline.ScmTrigger)
+		} else {
+			removeChildElement(triggersEle, triggers.SCMTriggerTag)
+		}
+		if pipeline.GithubPushTrigger != nil {
+			triggers.CreateGithubPushTriggerXML(triggersEle, pipeline.GithubPushTrigger)
+		} else {
+			removeChildElement(triggersEle, triggers.GithubPushTriggerTag)
+		}
+		if pipeline.GitlabPushTrigger != nil {
+			triggers.CreateGitlabPushTriggerXML(triggersEle, pipeline.GitlabPushTrigger)
+		} else {
+			removeChildElement(triggersEle, triggers.GitlabPushTriggerTag)
+		}
 	}
 
 	// ------------------------------------------------
-	// replace definition(all fields could update from console)
-	removeChildElement(flow, DefinitionTag)
-	pipelineDefine := flow.CreateElement(DefinitionTag)
-	pipelineDefine.CreateAttr(ClassKey, "org.jenkinsci.plugins.workflow.cps.CpsFlowDefinition")
-	pipelineDefine.CreateAttr(PluginKey, "workflow-cps")
-	pipelineDefine.CreateElement(ScriptTag).SetText(pipeline.Jenkinsfile)
-	pipelineDefine.CreateElement(SandboxTag).SetText("true")
+	// replace definition(all fields could update from console), the position of it is kept.
+	// The definition loading the Jenkinsfile from an unsupported scm is kept as it is
+	if definition := flow.SelectElement(DefinitionTag); definition == nil || !isOpaqueScmDefinition(pipeline, definition) {
+		definitionIndex := -1
+		if definition != nil {
+			definitionIndex = definition.Index()
+			flow.RemoveChild(definition)
+		}
+		pipelineDefine, err := createPipelineDefinition(pipeline)
+		if err != nil {
+			return "", err
+		}
+		if definitionIndex >= 0 {
+			flow.InsertChildAt(definitionIndex, pipelineDefine)
+		} else {
+			flow.AddChild(pipelineDefine)
+		}
+	}
 
 	// ------------------------------------------------
 	// update others
 	if flow.SelectElement(TriggersTag) == nil {
 		flow.CreateElement(TriggersTag)
 	}
+	if pipeline.QuietPeriod != nil {
+		quietPeriod := flow.SelectElement(QuietPeriodTag)
+		if quietPeriod == nil {
+			quietPeriod = etree.NewElement(QuietPeriodTag)
+			if disabled := flow.SelectElement(DisabledTag); disabled != nil {
+				flow.InsertChildAt(disabled.Index(), quietPeriod)
+			} else {
+				flow.AddChild(quietPeriod)
+			}
+		}
+		quietPeriod.SetText(strconv.Itoa(*pipeline.QuietPeriod))
+	} else {
+		removeChildElement(flow, QuietPeriodTag)
+	}
 	// TODO issue: if support RemoteTrigger in console, need to delete GenericWebhook tag when pipeline.GenericWebhook is nil;
 	if pipeline.RemoteTrigger != nil {
 		addOrUpdateElement(flow, AuthTokenTag, pipeline.RemoteTrigger.Token)
 	}
-	addOrUpdateElement(flow, DisabledTag, "false")
+	addOrUpdateElement(flow, DisabledTag, strconv.FormatBool(pipeline.Disabled))
+
+	// the unknown elements in the config are kept, so only the missing ones are added
+	if err := appendUnknownElements(flow, pipeline.UnknownElements); err != nil {
+		return "", err
+	}
 
 	// format xml string
 	doc.Indent(2)
@@ -209,22 +284,27 @@ func parsePipelineConfigXml(config string) (*devopsv1alpha3.NoScmPipeline, error
 	if node := flow.SelectElement("description"); node != nil {
 		pipeline.Description = node.Text()
 	}
+	pipeline.Disabled = getElementTextValueOrEmpty(flow, DisabledTag) == "true"
 
 	properties := flow.SelectElement("properties")
-	if properties.
+	if concurrent := properties.
 		SelectElement(
-			"org.jenkinsci.plugins.workflow.job.properties.DisableConcurrentBuildsJobProperty") != nil {
+			"org.jenkinsci.plugins.workflow.job.properties.DisableConcurrentBuildsJobProperty"); concurrent != nil {
 		pipeline.DisableConcurrent = true
+		pipeline.AbortPrevious = getElementTextValueOrEmpty(concurrent, AbortPreviousTag) == "true"
 	}
 	if properties.SelectElement("jenkins.model.BuildDiscarderProperty") != nil {
 		strategy := properties.
 			SelectElement("jenkins.model.BuildDiscarderProperty").
 			SelectElement("strategy")
 		pipeline.Discarder = &devopsv1alpha3.DiscarderProperty{
-			DaysToKeep: getElementTextValueOrEmpty(strategy, "daysToKeep"),
-			NumToKeep:  getElementTextValueOrEmpty(strategy, "numToKeep"),
+			DaysToKeep:         getElementTextValueOrEmpty(strategy, "daysToKeep"),
+			NumToKeep:          getElementTextValueOrEmpty(strategy, "numToKeep"),
+			ArtifactDaysToKeep: parseArtifactDiscarderValue(getElementTextValueOrEmpty(strategy, ArtiDaysToKeepTag)),
+			ArtifactNumToKeep:  parseArtifactDiscarderValue(getElementTextValueOrEmpty(strategy, ArtiNumToKeepTag)),
 		}
 	}
+	getJobPropertiesFromEtree(properties, pipeline)
 
 	pipeline.Parameters = getParametersfromEtree(properties)
 	if len(pipeline.Parameters) == 0 {
@@ -236,9 +316,7 @@ func parsePipelineConfigXml(config string) (*devopsv1alpha3.NoScmPipeline, error
 			"org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty"); triggerProperty != nil {
 		triggersEle := triggerProperty.SelectElement("triggers")
 		if timerTrigger := triggersEle.SelectElement("hudson.triggers.TimerTrigger"); timerTrigger != nil {
-			pipeline.TimerTrigger = &devopsv1alpha3.TimerTrigger{
-				Cron: getElementTextValueOrEmpty(timerTrigger, "spec"),
-			}
+			pipeline.TimerTrigger = parseTimerTriggerSpec(getElementTextValueOrEmpty(timerTrigger, "spec"))
 		}
 
 		if genericWebhookEle := triggersEle.SelectElement("org.jenkinsci.plugins.gwt.GenericTrigger"); genericWebhookEle != nil {
@@ -246,6 +324,15 @@ func parsePipelineConfigXml(config string) (*devopsv1alpha3.NoScmPipeline, error
 		} else if pipeline.GenericWebhook != nil {
 			pipeline.GenericWebhook.Enable = false
 		}
+		pipeline.UpstreamTrigger = triggers.ParseUpstreamTriggerXML(triggersEle.SelectElement(triggers.ReverseBuildTriggerTag))
+		pipeline.ScmTrigger = triggers.ParseScmTriggerXML(triggersEle.SelectElement(triggers.SCMTriggerTag))
+		pipeline.GithubPushTrigger = triggers.ParseGithubPushTriggerXML(triggersEle.SelectElement(triggers.GithubPushTriggerTag))
+		pipeline.GitlabPushTrigger = triggers.ParseGitlabPushTriggerXML(triggersEle.SelectElement(triggers.GitlabPushTriggerTag))
+	}
+	if quietPeriod := flow.SelectElement(QuietPeriodTag); quietPeriod != nil {
+		if value, err := strconv.Atoi(strings.TrimSpace(quietPeriod.Text())); err == nil {
+			pipeline.QuietPeriod = &value
+		}
 	}
 	if authToken := flow.SelectElement("authToken"); authToken != nil {
 		pipeline.RemoteTrigger = &devopsv1alpha3.RemoteTrigger{
@@ -253,51 +340,253 @@ func parsePipelineConfigXml(config string) (*devopsv1alpha3.NoScmPipeline, error
 		}
 	}
 	if definition := flow.SelectElement("definition"); definition != nil {
-		if script := definition.SelectElement("script"); script != nil {
+		if definition.SelectAttrValue(ClassKey, "") == CpsScmFlowDefinitionClass {
+			pipeline.ScmDefinition = getScmFlowDefinitionFromEtree(definition)
+		} else if script := definition.SelectElement("script"); script != nil {
 			pipeline.Jenkinsfile = script.Text()
 		}
 	}
+
+	if regenerated, err := createPipelineConfigXml(pipeline); err == nil {
+		regeneratedDoc := etree.NewDocument()
+		if err = regeneratedDoc.ReadFromString(replaceXmlVersion(regenerated, "1.1", "1.0")); err != nil {
+			return nil, err
+		}
+		if pipeline.UnknownElements, err = findUnknownElements(flow, regeneratedDoc.Root(), pipelineUnknownContainers); err != nil {
+			return nil, err
+		}
+	}
 	return pipeline, nil
 }
 
+// timerTriggerSpec returns the spec of a timer trigger, the time zone is given by the TZ= line as Jenkins does
+func timerTriggerSpec(trigger *devopsv1alpha3.TimerTrigger) string {
+	if trigger.Timezone == "" {
+		return trigger.Cron
+	}
+	return "TZ=" + trigger.Timezone + "\n" + trigger.Cron
+}
+
+func parseTimerTriggerSpec(spec string) *devopsv1alpha3.TimerTrigger {
+	trigger := &devopsv1alpha3.TimerTrigger{Cron: spec}
+	if strings.HasPrefix(spec, "TZ=") {
+		timezone, cron, _ := strings.Cut(spec, "\n")
+		trigger.Timezone = strings.TrimSpace(strings.TrimPrefix(timezone, "TZ="))
+		trigger.Cron = cron
+	}
+	return trigger
+}
+
+// hasPipelineTriggers returns true if any trigger under PipelineTriggersJobProperty is set
+func hasPipelineTriggers(pipeline *devopsv1alpha3.NoScmPipeline) bool {
+	return pipeline.TimerTrigger != nil || pipeline.GenericWebhook != nil || pipeline.UpstreamTrigger != nil ||
+		pipeline.ScmTrigger != nil || pipeline.GithubPushTrigger != nil || pipeline.GitlabPushTrigger != nil
+}
+
+// the defaults of the pipeline script from scm, they are written if the fields are empty and
+// parsed back to empty fields, so the pipelines without them round-trip
+const (
+	defaultScmDefinitionBranch     = "*/master"
+	defaultScmDefinitionScriptPath = "Jenkinsfile"
+)
+
+// createPipelineDefinition creates the definition with the inline Jenkinsfile, or the one loading the Jenkinsfile
+// from scm if the scm definition is given
+func createPipelineDefinition(pipeline *devopsv1alpha3.NoScmPipeline) (*etree.Element, error) {
+	pipelineDefine := etree.NewElement(DefinitionTag)
+	if scmDefinition := pipeline.ScmDefinition; scmDefinition != nil {
+		pipelineDefine.CreateAttr(ClassKey, CpsScmFlowDefinitionClass)
+		pipelineDefine.CreateAttr(PluginKey, "workflow-cps")
+		scm := pipelineDefine.CreateElement("scm")
+		switch scmDefinition.SourceType {
+		case devopsv1alpha3.SourceTypeGit:
+			if scmDefinition.GitSource == nil {
+				return nil, fmt.Errorf("git source is required by the pipeline script from scm")
+			}
+			branch := scmDefinition.Branch
+			if branch == "" {
+				branch = defaultScmDefinitionBranch
+			}
+			internal.AppendGitSCMToEtree(scm, scmDefinition.GitSource, branch)
+		case devopsv1alpha3.SourceTypeSVN:
+			if scmDefinition.SvnSource == nil {
+				return nil, fmt.Errorf("svn source is required by the pipeline script from scm")
+			}
+			internal.AppendSubversionSCMToEtree(scm, scmDefinition.SvnSource)
+		default:
+			return nil, fmt.Errorf("unsupported scm type %s of the pipeline script from scm", scmDefinition.SourceType)
+		}
+		scriptPath := scmDefinition.ScriptPath
+		if scriptPath == "" {
+			scriptPath = defaultScmDefinitionScriptPath
+		}
+		pipelineDefine.CreateElement(ScriptPathTag).SetText(scriptPath)
+		pipelineDefine.CreateElement(LightweightTag).SetText(strconv.FormatBool(scmDefinition.Lightweight))
+		return pipelineDefine, nil
+	}
+	pipelineDefine.CreateAttr(ClassKey, CpsFlowDefinitionClass)
+	pipelineDefine.CreateAttr(PluginKey, "workflow-cps")
+	pipelineDefine.CreateElement(ScriptTag).SetText(pipeline.Jenkinsfile)
+	pipelineDefine.CreateElement(SandboxTag).SetText("true")
+	return pipelineDefine, nil
+}
+
+// isOpaqueScmDefinition returns true if the definition loads the Jenkinsfile from an scm which is not supported,
+// it is parsed as a scm definition without the source type
+func isOpaqueScmDefinition(pipeline *devopsv1alpha3.NoScmPipeline, definition *etree.Element) bool {
+	return pipeline.ScmDefinition != nil && pipeline.ScmDefinition.SourceType == "" &&
+		definition.SelectAttrValue(ClassKey, "") == CpsScmFlowDefinitionClass
+}
+
+func getScmFlowDefinitionFromEtree(definition *etree.Element) *devopsv1alpha3.ScmFlowDefinition {
+	scmDefinition := &devopsv1alpha3.ScmFlowDefinition{
+		ScriptPath:  getElementTextValueOrEmpty(definition, ScriptPathTag),
+		Lightweight: getElementTextValueOrEmpty(definition, LightweightTag) == "true",
+	}
+	if scmDefinition.ScriptPath == defaultScmDefinitionScriptPath {
+		scmDefinition.ScriptPath = ""
+	}
+	if scm := definition.SelectElement("scm"); scm != nil {
+		switch scm.SelectAttrValue(ClassKey, "") {
+		case "hudson.plugins.git.GitSCM":
+			scmDefinition.SourceType = devopsv1alpha3.SourceTypeGit
+			scmDefinition.GitSource, scmDefinition.Branch = internal.GetGitSCMFromEtree(scm)
+			if scmDefinition.Branch == defaultScmDefinitionBranch {
+				scmDefinition.Branch = ""
+			}
+		case "hudson.scm.SubversionSCM":
+			scmDefinition.SourceType = devopsv1alpha3.SourceTypeSVN
+			scmDefinition.SvnSource = internal.GetSubversionSCMFromEtree(scm)
+		}
+	}
+	return scmDefinition
+}
+
 func replaceParametersInEtree(properties *etree.Element, parameters []devopsv1alpha3.ParameterDefinition) {
 	var paramDefiPropsE, paramDefiE *etree.Element
+	var previous []*etree.Element
 	if paramDefiPropsE = properties.SelectElement(ParamDefiPropTag); paramDefiPropsE == nil {
 		paramDefiE = properties.CreateElement(ParamDefiPropTag).CreateElement(ParamDefiTag)
 	} else {
 		if paramDefiE = paramDefiPropsE.SelectElement(ParamDefiTag); paramDefiE != nil {
+			previous = paramDefiE.ChildElements()
 			paramDefiPropsE.RemoveChild(paramDefiE)
 		}
 		paramDefiE = paramDefiPropsE.CreateElement(ParamDefiTag)
 	}
 
 	for _, parameter := range parameters {
-		for className, typeName := range ParameterTypeMap {
-			if typeName == parameter.Type {
-				paramDefine := paramDefiE.CreateElement(className)
-				paramDefine.CreateElement("name").SetText(parameter.Name)
-				paramDefine.CreateElement("description").SetText(parameter.Description)
-				switch parameter.Type {
-				case "choice":
-					choices := paramDefine.CreateElement("choices")
-					choices.CreateAttr("class", "java.util.Arrays$ArrayList")
-					// see also https://github.com/kubesphere/kubesphere/issues/3430
-					a := choices.CreateElement("a")
-					a.CreateAttr("class", "string-array")
-					choiceValues := strings.Split(parameter.DefaultValue, "\n")
-					for _, choiceValue := range choiceValues {
-						a.CreateElement("string").SetText(choiceValue)
-					}
-				case "file":
+		className := parameterClassName(parameter.Type)
+		if className == "" {
+			// keep the parameters which are not supported as they are, the type of them is the class name
+			for _, prev := range previous {
+				if prev.Tag == parameter.Type && getElementTextValueOrEmpty(prev, "name") == parameter.Name {
+					paramDefiE.AddChild(prev.Copy())
 					break
-				default:
-					paramDefine.CreateElement("defaultValue").SetText(parameter.DefaultValue)
 				}
 			}
+			continue
+		}
+		paramDefine := paramDefiE.CreateElement(className)
+		paramDefine.CreateElement("name").SetText(parameter.Name)
+		paramDefine.CreateElement("description").SetText(parameter.Description)
+		switch parameter.Type {
+		case "choice":
+			choices := paramDefine.CreateElement("choices")
+			choices.CreateAttr("class", "java.util.Arrays$ArrayList")
+			// see also https://github.com/kubesphere/kubesphere/issues/3430
+			a := choices.CreateElement("a")
+			a.CreateAttr("class", "string-array")
+			choiceValues := strings.Split(parameter.DefaultValue, "\n")
+			for _, choiceValue := range choiceValues {
+				a.CreateElement("string").SetText(choiceValue)
+			}
+		case "file":
+			break
+		case "string":
+			paramDefine.CreateElement("defaultValue").SetText(parameter.DefaultValue)
+			paramDefine.CreateElement("trim").SetText(strconv.FormatBool(parameter.Trim))
+		case "validating_string":
+			paramDefine.CreateElement("defaultValue").SetText(parameter.DefaultValue)
+			validation := parameter.Validation
+			if validation == nil {
+				validation = &devopsv1alpha3.StringParameterValidation{}
+			}
+			paramDefine.CreateElement("regex").SetText(validation.Regex)
+			paramDefine.CreateElement("failedValidationMessage").SetText(validation.FailedValidationMessage)
+		case "credentials":
+			option := parameter.Credentials
+			if option == nil {
+				option = &devopsv1alpha3.CredentialsParameterOption{}
+			}
+			paramDefine.CreateAttr(PluginKey, "credentials")
+			paramDefine.CreateElement("defaultValue").SetText(parameter.DefaultValue)
+			paramDefine.CreateElement("credentialType").SetText(option.CredentialType)
+			paramDefine.CreateElement("required").SetText(strconv.FormatBool(option.Required))
+		case "run":
+			option := parameter.Run
+			if option == nil {
+				option = &devopsv1alpha3.RunParameterOption{}
+			}
+			paramDefine.CreateElement("projectName").SetText(option.ProjectName)
+			filter := option.Filter
+			if filter == "" {
+				filter = "ALL"
+			}
+			paramDefine.CreateElement("filter").SetText(filter)
+		case "extended_choice":
+			appendExtendedChoiceParameterToEtree(paramDefine, parameter)
+		default:
+			paramDefine.CreateElement("defaultValue").SetText(parameter.DefaultValue)
 		}
 	}
 }
 
+// parameterClassName returns the class name of the given parameter type, it is empty if the type is not supported
+func parameterClassName(typeName string) string {
+	for className, name := range ParameterTypeMap {
+		if name == typeName {
+			return className
+		}
+	}
+	return ""
+}
+
+// extendedChoiceSelectTypes maps the select type of the extended choice parameter to the type in config.xml.
+var extendedChoiceSelectTypes = map[string]string{
+	devopsv1alpha3.ExtendedChoiceSingleSelect: "PT_SINGLE_SELECT",
+	devopsv1alpha3.ExtendedChoiceMultiSelect:  "PT_MULTI_SELECT",
+	devopsv1alpha3.ExtendedChoiceCheckbox:     "PT_CHECKBOX",
+	devopsv1alpha3.ExtendedChoiceRadio:        "PT_RADIO",
+}
+
+func appendExtendedChoiceParameterToEtree(paramDefine *etree.Element, parameter devopsv1alpha3.ParameterDefinition) {
+	option := parameter.ExtendedChoice
+	if option == nil {
+		option = &devopsv1alpha3.ExtendedChoiceParameterOption{}
+	}
+	selectType, ok := extendedChoiceSelectTypes[option.SelectType]
+	if !ok {
+		selectType = extendedChoiceSelectTypes[devopsv1alpha3.ExtendedChoiceSingleSelect]
+	}
+	delimiter := option.Delimiter
+	if delimiter == "" {
+		delimiter = ","
+	}
+	visibleItemCount := option.VisibleItemCount
+	if visibleItemCount <= 0 {
+		visibleItemCount = len(option.Choices)
+	}
+	paramDefine.CreateAttr(PluginKey, "extended-choice-parameter")
+	paramDefine.CreateElement("quoteValue").SetText("false")
+	paramDefine.CreateElement("saveJSONParameterToFile").SetText("false")
+	paramDefine.CreateElement("visibleItemCount").SetText(strconv.Itoa(visibleItemCount))
+	paramDefine.CreateElement("type").SetText(selectType)
+	paramDefine.CreateElement("value").SetText(strings.Join(option.Choices, ","))
+	paramDefine.CreateElement("defaultValue").SetText(parameter.DefaultValue)
+	paramDefine.CreateElement("multiSelectDelimiter").SetText(delimiter)
+}
+
 func getElementTextValueOrEmpty(element *etree.Element, name string) string {
 	subEle := element.SelectElement(name)
 	if subEle != nil {
@@ -311,47 +600,21 @@ func getParametersfromEtree(properties *etree.Element) []devopsv1alpha3.Paramete
 	if parametersProperty := properties.SelectElement("hudson.model.ParametersDefinitionProperty"); parametersProperty != nil {
 		params := parametersProperty.SelectElement("parameterDefinitions").ChildElements()
 		for _, param := range params {
+			parameter := devopsv1alpha3.ParameterDefinition{
+				Name:         getElementTextValueOrEmpty(param, "name"),
+				Description:  getElementTextValueOrEmpty(param, "description"),
+				DefaultValue: getElementTextValueOrEmpty(param, "defaultValue"),
+				Type:         ParameterTypeMap[param.Tag],
+			}
 			switch param.Tag {
 			case "hudson.model.StringParameterDefinition":
-				parameters = append(parameters, devopsv1alpha3.ParameterDefinition{
-					Name:         getElementTextValueOrEmpty(param, "name"),
-					Description:  getElementTextValueOrEmpty(param, "description"),
-					DefaultValue: getElementTextValueOrEmpty(param, "defaultValue"),
-					Type:         ParameterTypeMap["hudson.model.StringParameterDefinition"],
-				})
-			case "hudson.model.BooleanParameterDefinition":
-				parameters = append(parameters, devopsv1alpha3.ParameterDefinition{
-					Name:         getElementTextValueOrEmpty(param, "name"),
-					Description:  getElementTextValueOrEmpty(param, "description"),
-					DefaultValue: getElementTextValueOrEmpty(param, "defaultValue"),
-					Type:         ParameterTypeMap["hudson.model.BooleanParameterDefinition"],
-				})
-			case "hudson.model.TextParameterDefinition":
-				parameters = append(parameters, devopsv1alpha3.ParameterDefinition{
-					Name:         getElementTextValueOrEmpty(param, "name"),
-					Description:  getElementTextValueOrEmpty(param, "description"),
-					DefaultValue: getElementTextValueOrEmpty(param, "defaultValue"),
-					Type:         ParameterTypeMap["hudson.model.TextParameterDefinition"],
-				})
+				parameter.Trim = getElementTextValueOrEmpty(param, "trim") == "true"
+			case "hudson.model.BooleanParameterDefinition", "hudson.model.TextParameterDefinition",
+				"hudson.model.PasswordParameterDefinition":
 			case "hudson.model.FileParameterDefinition":
-				parameters = append(parameters, devopsv1alpha3.ParameterDefinition{
-					Name:        getElementTextValueOrEmpty(param, "name"),
-					Description: getElementTextValueOrEmpty(param, "description"),
-					Type:        ParameterTypeMap["hudson.model.FileParameterDefinition"],
-				})
-			case "hudson.model.PasswordParameterDefinition":
-				parameters = append(parameters, devopsv1alpha3.ParameterDefinition{
-					Name:         getElementTextValueOrEmpty(param, "name"),
-					Description:  getElementTextValueOrEmpty(param, "description"),
-					DefaultValue: getElementTextValueOrEmpty(param, "name"),
-					Type:         ParameterTypeMap["hudson.model.PasswordParameterDefinition"],
-				})
+				parameter.DefaultValue = ""
 			case "hudson.model.ChoiceParameterDefinition":
-				choiceParameter := devopsv1alpha3.ParameterDefinition{
-					Name:        getElementTextValueOrEmpty(param, "name"),
-					Description: getElementTextValueOrEmpty(param, "description"),
-					Type:        ParameterTypeMap["hudson.model.ChoiceParameterDefinition"],
-				}
+				parameter.DefaultValue = ""
 				choicesEle := param.SelectElement("choices")
 				var choices []*etree.Element
 				// the child element is a in the simple pipeline, the child is string list in the multi-branch pipeline
@@ -363,23 +626,56 @@ func getParametersfromEtree(properties *etree.Element) []devopsv1alpha3.Paramete
 					choices = choiceAnchor.SelectElements("string")
 				}
 				for _, choice := range choices {
-					choiceParameter.DefaultValue += fmt.Sprintf("%s\n", choice.Text())
+					parameter.DefaultValue += fmt.Sprintf("%s\n", choice.Text())
+				}
+				parameter.DefaultValue = strings.TrimSpace(parameter.DefaultValue)
+			case ValidatingStringParameterClass:
+				parameter.Validation = &devopsv1alpha3.StringParameterValidation{
+					Regex:                   getElementTextValueOrEmpty(param, "regex"),
+					FailedValidationMessage: getElementTextValueOrEmpty(param, "failedValidationMessage"),
+				}
+			case CredentialsParameterClass:
+				parameter.Credentials = &devopsv1alpha3.CredentialsParameterOption{
+					CredentialType: getElementTextValueOrEmpty(param, "credentialType"),
+					Required:       getElementTextValueOrEmpty(param, "required") == "true",
 				}
-				choiceParameter.DefaultValue = strings.TrimSpace(choiceParameter.DefaultValue)
-				parameters = append(parameters, choiceParameter)
+			case RunParameterClass:
+				parameter.DefaultValue = ""
+				parameter.Run = &devopsv1alpha3.RunParameterOption{
+					ProjectName: getElementTextValueOrEmpty(param, "projectName"),
+					Filter:      getElementTextValueOrEmpty(param, "filter"),
+				}
+			case ExtendedChoiceParameterClass:
+				parameter.ExtendedChoice = getExtendedChoiceParameterFromEtree(param)
 			default:
-				parameters = append(parameters, devopsv1alpha3.ParameterDefinition{
-					Name:         getElementTextValueOrEmpty(param, "name"),
-					Description:  getElementTextValueOrEmpty(param, "description"),
-					DefaultValue: "unknown",
-					Type:         param.Tag,
-				})
+				parameter.DefaultValue = "unknown"
+				parameter.Type = param.Tag
 			}
+			parameters = append(parameters, parameter)
 		}
 	}
 	return parameters
 }
 
+func getExtendedChoiceParameterFromEtree(param *etree.Element) *devopsv1alpha3.ExtendedChoiceParameterOption {
+	option := &devopsv1alpha3.ExtendedChoiceParameterOption{
+		Delimiter: getElementTextValueOrEmpty(param, "multiSelectDelimiter"),
+	}
+	selectType := getElementTextValueOrEmpty(param, "type")
+	for name, value := range extendedChoiceSelectTypes {
+		if value == selectType {
+			option.SelectType = name
+		}
+	}
+	if value := getElementTextValueOrEmpty(param, "value"); value != "" {
+		option.Choices = strings.Split(value, ",")
+	}
+	if count, err := strconv.Atoi(getElementTextValueOrEmpty(param, "visibleItemCount")); err == nil {
+		option.VisibleItemCount = count
+	}
+	return option
+}
+
 func appendMultiBranchJobTriggerToEtree(properties *etree.Element, s *devopsv1alpha3.MultiBranchJobTrigger) {
 	triggerProperty := properties.CreateElement("org.jenkinsci.plugins.workflow.multibranch.PipelineTriggerProperty")
 	triggerProperty.CreateAttr("plugin", "multibranch-action-triggers")
@@ -459,6 +755,7 @@ func createMultiBranchPipelineConfigXml(projectName string, pipeline *devopsv1al
 
 		triggers.CreateElement("disabled").SetText("false")
 	}
+	project.CreateElement(DisabledTag).SetText(strconv.FormatBool(pipeline.Disabled))
 
 	sources := project.CreateElement("sources")
 	sources.CreateAttr("class", "jenkins.branch.MultiBranchProject$BranchSourceList")
@@ -468,10 +765,8 @@ func createMultiBranchPipelineConfigXml(projectName string, pipeline *devopsv1al
 	sourcesOwner.CreateAttr("reference", "../..")
 
 	branchSource := sources.CreateElement("data").CreateElement("jenkins.branch.BranchSource")
-	branchSourceStrategy := branchSource.CreateElement("strategy")
-	branchSourceStrategy.CreateAttr("class", "jenkins.branch.NamedExceptionsBranchPropertyStrategy")
-	branchSourceStrategy.CreateElement("defaultProperties").CreateAttr("class", "empty-list")
-	branchSourceStrategy.CreateElement("namedExceptions").CreateAttr("class", "empty-list")
+	internal.AppendBranchPropertyStrategyToEtree(branchSource.CreateElement("strategy"), pipeline.BranchPropertyStrategy)
+	internal.AppendBuildStrategiesToEtree(branchSource, pipeline.BuildStrategies)
 	source := branchSource.CreateElement("source")
 
 	switch pipeline.SourceType {
@@ -487,6 +782,10 @@ func createMultiBranchPipelineConfigXml(projectName string, pipeline *devopsv1al
 		internal.AppendSingleSvnSourceToEtree(source, pipeline.SingleSvnSource)
 	case devopsv1alpha3.SourceTypeBitbucket:
 		internal.AppendBitbucketServerSourceToEtree(source, pipeline.BitbucketServerSource)
+	case devopsv1alpha3.SourceTypeBitbucketCloud:
+		internal.AppendBitbucketCloudSourceToEtree(source, pipeline.BitbucketCloudSource)
+	case devopsv1alpha3.SourceTypeGitea:
+		internal.AppendGiteaSourceToEtree(source, pipeline.GiteaSource)
 
 	default:
 		return "", fmt.Errorf("unsupport source type: %s", pipeline.SourceType)
@@ -499,6 +798,10 @@ func createMultiBranchPipelineConfigXml(projectName string, pipeline *devopsv1al
 	factoryOwner.CreateAttr("reference", "../..")
 	factory.CreateElement("scriptPath").SetText(pipeline.ScriptPath)
 
+	if err := appendUnknownElements(project, pipeline.UnknownElements); err != nil {
+		return "", err
+	}
+
 	doc.Indent(2)
 	stringXml, err := doc.WriteToString()
 	return replaceXmlVersion(stringXml, "1.0", "1.1"), err
@@ -525,6 +828,7 @@ func parseMultiBranchPipelineConfigXml(config string) (*devopsv1alpha3.MultiBran
 	if project.SelectElement("description") != nil {
 		pipeline.Description = getElementTextValueOrEmpty(project, "description")
 	}
+	pipeline.Disabled = getElementTextValueOrEmpty(project, DisabledTag) == "true"
 
 	if discarder := project.SelectElement("orphanedItemStrategy"); discarder != nil {
 		if getElementTextValueOrEmpty(discarder, "pruneDeadBranches") == "true" {
@@ -546,14 +850,24 @@ func parseMultiBranchPipelineConfigXml(config string) (*devopsv1alpha3.MultiBran
 	if sources := project.SelectElement("sources"); sources != nil {
 		if sourcesData := sources.SelectElement("data"); sourcesData != nil {
 			if branchSource := sourcesData.SelectElement("jenkins.branch.BranchSource"); branchSource != nil {
+				pipeline.BranchPropertyStrategy = internal.GetBranchPropertyStrategyFromEtree(branchSource.SelectElement("strategy"))
+				pipeline.BuildStrategies = internal.GetBuildStrategiesFromEtree(branchSource)
 				source := branchSource.SelectElement("source")
 				switch source.SelectAttr("class").Value {
 				case "org.jenkinsci.plugins.github_branch_source.GitHubSCMSource":
 					pipeline.GitHubSource = internal.GetGithubSourcefromEtree(source)
 					pipeline.SourceType = devopsv1alpha3.SourceTypeGithub
 				case "com.cloudbees.jenkins.plugins.bitbucket.BitbucketSCMSource":
-					pipeline.BitbucketServerSource = internal.GetBitbucketServerSourceFromEtree(source)
-					pipeline.SourceType = devopsv1alpha3.SourceTypeBitbucket
+					if internal.IsBitbucketCloudSource(source) {
+						pipeline.BitbucketCloudSource = internal.GetBitbucketCloudSourceFromEtree(source)
+						pipeline.SourceType = devopsv1alpha3.SourceTypeBitbucketCloud
+					} else {
+						pipeline.BitbucketServerSource = internal.GetBitbucketServerSourceFromEtree(source)
+						pipeline.SourceType = devopsv1alpha3.SourceTypeBitbucket
+					}
+				case "org.jenkinsci.plugin.gitea.GiteaSCMSource":
+					pipeline.GiteaSource = internal.GetGiteaSourceFromEtree(source)
+					pipeline.SourceType = devopsv1alpha3.SourceTypeGitea
 				case "io.jenkins.plugins.gitlabbranchsource.GitLabSCMSource":
 					pipeline.GitlabSource = internal.GetGitlabSourceFromEtree(source)
 					pipeline.SourceType = devopsv1alpha3.SourceTypeGitlab
@@ -580,9 +894,32 @@ func parseMultiBranchPipelineConfigXml(config string) (*devopsv1alpha3.MultiBran
 		// see also https://github.com/jenkinsci/pipeline-multibranch-defaults-plugin
 		pipeline.ScriptPath = scriptPathEle.Text()
 	}
+
+	if regenerated, err := createMultiBranchPipelineConfigXml("", pipeline); err == nil {
+		regeneratedDoc := etree.NewDocument()
+		if err = regeneratedDoc.ReadFromString(replaceXmlVersion(regenerated, "1.1", "1.0")); err != nil {
+			return nil, err
+		}
+		if pipeline.UnknownElements, err = findUnknownElements(project, regeneratedDoc.Root(),
+			multiBranchPipelineUnknownContainers); err != nil {
+			return nil, err
+		}
+	}
 	return pipeline, nil
 }
 
+// updateMultiBranchPipelineConfigXml regenerates the config from the pipeline, the unknown elements of the current
+// config are carried over
+func updateMultiBranchPipelineConfigXml(config, projectName string, pipeline *devopsv1alpha3.MultiBranchPipeline) (string, error) {
+	current, err := parseMultiBranchPipelineConfigXml(config)
+	if err != nil {
+		return "", err
+	}
+	updated := pipeline.DeepCopy()
+	updated.UnknownElements = append(updated.UnknownElements, current.UnknownElements...)
+	return createMultiBranchPipelineConfigXml(projectName, updated)
+}
+
 func toCrontab(millis int64) string {
 	if millis*time.Millisecond.Nanoseconds() <= 5*time.Minute.Nanoseconds() {
 		return "* * * * *"
@@ -21,6 +21,7 @@ import (
 	"github.com/opswave/go-jenkins/devops/jenkins/internal"
 	"github.com/stretchr/testify/assert"
 	"reflect"
+	"strings"
 	"testing"
 
 	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
@@ -44,6 +45,12 @@ func Test_NoScmPipelineConfig(t *testing.T) {
 			Jenkinsfile:       "node{echo 'hello'}",
 			DisableConcurrent: true,
 		},
+		{
+			Name:        "",
+			Description: "",
+			Jenkinsfile: "node{echo 'hello'}",
+			Disabled:    true,
+		},
 	}
 	for _, input := range inputs {
 		outputString, err := createPipelineConfigXml(input)
@@ -205,6 +212,15 @@ func Test_NoScmPipelineConfig_Trigger(t *testing.T) {
 				Token: "abc",
 			},
 		},
+		{
+			Name:        "",
+			Description: "for test",
+			Jenkinsfile: "node{echo 'hello'}",
+			TimerTrigger: &devopsv1alpha3.TimerTrigger{
+				Cron:     "H 2 * * *\nH 14 * * 1-5",
+				Timezone: "Asia/Shanghai",
+			},
+		},
 	}
 
 	for index, input := range inputs {
@@ -261,6 +277,14 @@ func Test_MultiBranchPipelineConfig(t *testing.T) {
 			SourceType:   "gitlab",
 			GitlabSource: &devopsv1alpha3.GitlabSource{},
 		},
+		{
+			Name:        "",
+			Description: "for test",
+			Disabled:    true,
+			ScriptPath:  "Jenkinsfile",
+			SourceType:  "git",
+			GitSource:   &devopsv1alpha3.GitSource{},
+		},
 	}
 	for _, input := range inputs {
 		outputString, err := createMultiBranchPipelineConfigXml("", input)
@@ -464,6 +488,73 @@ func Test_MultiBranchPipelineConfig_Source(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:        "",
+			Description: "for test",
+			ScriptPath:  "Jenkinsfile",
+			SourceType:  "bitbucket_cloud",
+			TimerTrigger: &devopsv1alpha3.TimerTrigger{
+				Interval: "12345566",
+			},
+			BitbucketCloudSource: &devopsv1alpha3.BitbucketCloudSource{
+				Owner:                "kubesphere",
+				Repo:                 "devops",
+				CredentialId:         "bitbucket",
+				DiscoverBranches:     1,
+				DiscoverPRFromOrigin: 2,
+				DiscoverTags:         true,
+				DiscoverPRFromForks: &devopsv1alpha3.DiscoverPRFromForks{
+					Strategy: 1,
+					Trust:    2,
+				},
+				CloneOption: &devopsv1alpha3.GitCloneOption{
+					Timeout: 10,
+					Depth:   10,
+				},
+				RegexFilter:               "*-dev",
+				AcceptJenkinsNotification: true,
+			},
+		},
+		{
+			Name:        "",
+			Description: "for test",
+			ScriptPath:  "Jenkinsfile",
+			SourceType:  "gitea",
+			TimerTrigger: &devopsv1alpha3.TimerTrigger{
+				Interval: "12345566",
+			},
+			GiteaSource: &devopsv1alpha3.GiteaSource{
+				ServerUrl:            "https://gitea.example.com",
+				Owner:                "kubesphere",
+				Repo:                 "devops",
+				CredentialId:         "gitea",
+				DiscoverBranches:     1,
+				DiscoverPRFromOrigin: 2,
+				DiscoverTags:         true,
+				DiscoverPRFromForks: &devopsv1alpha3.DiscoverPRFromForks{
+					Strategy: 1,
+					Trust:    4,
+				},
+				CloneOption: &devopsv1alpha3.GitCloneOption{
+					Timeout: 10,
+					Depth:   10,
+				},
+				RegexFilter: "*-dev",
+			},
+		},
+		{
+			Name:        "",
+			Description: "for test",
+			ScriptPath:  "Jenkinsfile",
+			SourceType:  "gitea",
+			GiteaSource: &devopsv1alpha3.GiteaSource{
+				ServerUrl: "https://gitea.example.com",
+				DiscoverPRFromForks: &devopsv1alpha3.DiscoverPRFromForks{
+					Strategy: 2,
+					Trust:    1,
+				},
+			},
+		},
 
 		{
 			Name:        "",
@@ -652,6 +743,94 @@ func Test_MultiBranchPipelineRegexFilter(t *testing.T) {
 
 }
 
+func Test_MultiBranchPipelineBranchStrategies(t *testing.T) {
+
+	inputs := []*devopsv1alpha3.MultiBranchPipeline{
+		{
+			Name:        "",
+			Description: "for test",
+			ScriptPath:  "Jenkinsfile",
+			SourceType:  "git",
+			GitSource: &devopsv1alpha3.GitSource{
+				Url:              "https://github.com/kubesphere/devops",
+				DiscoverBranches: true,
+				DiscoverTags:     true,
+			},
+			BranchPropertyStrategy: &devopsv1alpha3.BranchPropertyStrategy{
+				DefaultProperties: &devopsv1alpha3.BranchProperties{
+					DurabilityHint: devopsv1alpha3.DurabilityHintPerformanceOptimized,
+				},
+				NamedExceptions: []devopsv1alpha3.NamedBranchProperties{{
+					Name: "feature-*,fix-*",
+					Properties: devopsv1alpha3.BranchProperties{
+						SuppressAutomaticTriggering: true,
+						UntrustedBranchProtection:   true,
+					},
+				}, {
+					Name: "master",
+					Properties: devopsv1alpha3.BranchProperties{
+						DurabilityHint: devopsv1alpha3.DurabilityHintMaxSurvivability,
+					},
+				}},
+			},
+			BuildStrategies: &devopsv1alpha3.BranchBuildStrategies{
+				Tags: &devopsv1alpha3.TagBuildStrategy{
+					AtMostDays: 7,
+				},
+				ChangeRequests: &devopsv1alpha3.ChangeRequestBuildStrategy{
+					IgnoreTargetOnlyChanges: true,
+				},
+				RegularBranches:  true,
+				SkipInitialBuild: true,
+			},
+		},
+		{
+			Name:        "",
+			Description: "for test",
+			ScriptPath:  "Jenkinsfile",
+			SourceType:  "github",
+			GitHubSource: &devopsv1alpha3.GithubSource{
+				Owner:            "kubesphere",
+				Repo:             "devops",
+				DiscoverBranches: 1,
+			},
+			BranchPropertyStrategy: &devopsv1alpha3.BranchPropertyStrategy{
+				NamedExceptions: []devopsv1alpha3.NamedBranchProperties{{
+					Name: "feature-*",
+					Properties: devopsv1alpha3.BranchProperties{
+						SuppressAutomaticTriggering: true,
+					},
+				}},
+			},
+			BuildStrategies: &devopsv1alpha3.BranchBuildStrategies{
+				Tags: &devopsv1alpha3.TagBuildStrategy{
+					AtLeastDays: 1,
+					AtMostDays:  30,
+				},
+			},
+		},
+	}
+
+	for _, input := range inputs {
+		outputString, err := createMultiBranchPipelineConfigXml("", input)
+		if err != nil {
+			t.Fatalf("should not get error %+v", err)
+		}
+		output, err := parseMultiBranchPipelineConfigXml(outputString)
+
+		if err != nil {
+			t.Fatalf("should not get error %+v", err)
+		}
+		assert.Equal(t, input, output)
+	}
+
+	// the tags within a week are built
+	outputString, err := createMultiBranchPipelineConfigXml("", inputs[0])
+	assert.Nil(t, err)
+	assert.Contains(t, outputString, "<atMostMillis>604800000</atMostMillis>")
+	assert.Contains(t, outputString, "<atLeastMillis>-1</atLeastMillis>")
+}
+
 func Test_MultiBranchPipelineMultibranchTrigger(t *testing.T) {
 
 	inputs := []*devopsv1alpha3.MultiBranchPipeline{
@@ -762,15 +941,15 @@ func TestForkPullRequestDiscoveryTrait(t *testing.T) {
 	assert.Equal(t, "github", pipeline.SourceType)
 	assert.Equal(t, internal.PRDiscoverTrustEveryone.Value(), pipeline.GitHubSource.DiscoverPRFromForks.Trust)
 
-	// for bitbucket cases
+	// for bitbucket cases, both jobs point to https://bitbucket.org
 	pipeline, err = parseMultiBranchPipelineConfigXml(noTrustForBitbucketJobXML)
 	assert.Nil(t, err)
-	assert.Equal(t, "bitbucket_server", pipeline.SourceType)
+	assert.Equal(t, "bitbucket_cloud", pipeline.SourceType)
 
 	pipeline, err = parseMultiBranchPipelineConfigXml(withTrustForBitbucketJobXML)
 	assert.Nil(t, err)
-	assert.Equal(t, "bitbucket_server", pipeline.SourceType)
-	assert.Equal(t, internal.BitbucketPRDiscoverTrustTeamForks.Value(), pipeline.BitbucketServerSource.DiscoverPRFromForks.Trust)
+	assert.Equal(t, "bitbucket_cloud", pipeline.SourceType)
+	assert.Equal(t, internal.BitbucketPRDiscoverTrustTeamForks.Value(), pipeline.BitbucketCloudSource.DiscoverPRFromForks.Trust)
 }
 
 var noTrustForGitlabJobXML = `<?xml version='1.1' encoding='UTF-8'?>
@@ -1232,3 +1411,439 @@ func Test_getElementTextValueOrEmpty(t *testing.T) {
 		})
 	}
 }
+
+func Test_NoScmPipelineConfig_UnknownElements(t *testing.T) {
+	pipeline := &devopsv1alpha3.NoScmPipeline{
+		Name:         "",
+		Description:  "for test",
+		Jenkinsfile:  "node{echo 'hello'}",
+		TimerTrigger: &devopsv1alpha3.TimerTrigger{Cron: "H 1 * * *"},
+	}
+	config, err := createPipelineConfigXml(pipeline)
+	assert.Nil(t, err)
+
+	// the elements which are created by plugins out of this client
+	doc := etree.NewDocument()
+	assert.Nil(t, doc.ReadFromString(replaceXmlVersion(config, "1.1", "1.0")))
+	flow := doc.Root()
+	flow.CreateElement("keepDependencies").SetText("true")
+	flow.SelectElement("properties").CreateElement("jenkins.model.BuildDiscarderProperty2").CreateElement("days").SetText("3")
+	flow.FindElement("properties/org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty/triggers").
+		CreateElement("org.jenkinsci.plugins.parameterizedscheduler.ParameterizedTimerTrigger").CreateElement("spec").SetText("H/5 * * * *")
+	doc.Indent(2)
+	config, err = doc.WriteToString()
+	assert.Nil(t, err)
+	config = replaceXmlVersion(config, "1.0", "1.1")
+
+	parsed, err := parsePipelineConfigXml(config)
+	assert.Nil(t, err)
+	assert.Equal(t, []devopsv1alpha3.UnknownElement{{
+		Path: "",
+		Tag:  "keepDependencies",
+		XML:  "<keepDependencies>true</keepDependencies>",
+	}, {
+		Path: "properties",
+		Tag:  "jenkins.model.BuildDiscarderProperty2",
+		XML:  "<jenkins.model.BuildDiscarderProperty2><days>3</days></jenkins.model.BuildDiscarderProperty2>",
+	}, {
+		Path: "properties/org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty/triggers",
+		Tag:  "org.jenkinsci.plugins.parameterizedscheduler.ParameterizedTimerTrigger",
+		XML:  "<org.jenkinsci.plugins.parameterizedscheduler.ParameterizedTimerTrigger><spec>H/5 * * * *</spec></org.jenkinsci.plugins.parameterizedscheduler.ParameterizedTimerTrigger>",
+	}}, parsed.UnknownElements)
+
+	// the config is not changed if the pipeline is not changed
+	updated, err := updatePipelineConfigXml(config, parsed)
+	assert.Nil(t, err)
+	assert.Equal(t, config, updated)
+
+	// the unknown elements survive an update which does not carry them
+	parsed.UnknownElements = nil
+	parsed.Jenkinsfile = "node{echo 'world'}"
+	updated, err = updatePipelineConfigXml(config, parsed)
+	assert.Nil(t, err)
+	reparsed, err := parsePipelineConfigXml(updated)
+	assert.Nil(t, err)
+	assert.Equal(t, "node{echo 'world'}", reparsed.Jenkinsfile)
+	assert.Len(t, reparsed.UnknownElements, 3)
+
+	// the unknown elements can be carried to a new config
+	created, err := createPipelineConfigXml(reparsed)
+	assert.Nil(t, err)
+	assert.Equal(t, updated, created)
+}
+
+func Test_NoScmPipelineConfig_Idempotent(t *testing.T) {
+	inputs := []*devopsv1alpha3.NoScmPipeline{{
+		Name:        "",
+		Description: "for test",
+		Jenkinsfile: "node{echo 'hello'}",
+	}, {
+		Name:              "",
+		Jenkinsfile:       "node{echo 'hello'}",
+		DisableConcurrent: true,
+		TimerTrigger:      &devopsv1alpha3.TimerTrigger{Cron: "H 1 * * *"},
+		Discarder:         &devopsv1alpha3.DiscarderProperty{DaysToKeep: "3", NumToKeep: "5"},
+	}}
+	for _, input := range inputs {
+		config, err := createPipelineConfigXml(input)
+		assert.Nil(t, err)
+		parsed, err := parsePipelineConfigXml(config)
+		assert.Nil(t, err)
+		assert.Nil(t, parsed.UnknownElements)
+		updated, err := updatePipelineConfigXml(config, parsed)
+		assert.Nil(t, err)
+		assert.Equal(t, config, updated)
+	}
+}
+
+func Test_MultiBranchPipelineConfig_UnknownElements(t *testing.T) {
+	pipeline := &devopsv1alpha3.MultiBranchPipeline{
+		Name:       "",
+		SourceType: devopsv1alpha3.SourceTypeGit,
+		ScriptPath: "Jenkinsfile",
+		GitSource: &devopsv1alpha3.GitSource{
+			Url:              "https://github.com/kubesphere/devops",
+			DiscoverBranches: true,
+		},
+	}
+	config, err := createMultiBranchPipelineConfigXml("", pipeline)
+	assert.Nil(t, err)
+	parsed, err := parseMultiBranchPipelineConfigXml(config)
+	assert.Nil(t, err)
+	assert.Nil(t, parsed.UnknownElements)
+
+	doc := etree.NewDocument()
+	assert.Nil(t, doc.ReadFromString(replaceXmlVersion(config, "1.1", "1.0")))
+	project := doc.Root()
+	project.SelectElement("properties").CreateElement("org.jenkinsci.plugins.docker.workflow.declarative.FolderConfig").
+		CreateElement("dockerLabel").SetText("docker")
+	project.FindElement("sources/data/jenkins.branch.BranchSource/source/traits").
+		CreateElement("jenkins.plugins.git.traits.AuthorInChangelogTrait")
+	doc.Indent(2)
+	config, err = doc.WriteToString()
+	assert.Nil(t, err)
+	config = replaceXmlVersion(config, "1.0", "1.1")
+
+	parsed, err = parseMultiBranchPipelineConfigXml(config)
+	assert.Nil(t, err)
+	assert.Equal(t, []devopsv1alpha3.UnknownElement{{
+		Path: "properties",
+		Tag:  "org.jenkinsci.plugins.docker.workflow.declarative.FolderConfig",
+		XML:  "<org.jenkinsci.plugins.docker.workflow.declarative.FolderConfig><dockerLabel>docker</dockerLabel></org.jenkinsci.plugins.docker.workflow.declarative.FolderConfig>",
+	}, {
+		Path: "sources/data/jenkins.branch.BranchSource/source/traits",
+		Tag:  "jenkins.plugins.git.traits.AuthorInChangelogTrait",
+		XML:  "<jenkins.plugins.git.traits.AuthorInChangelogTrait/>",
+	}}, parsed.UnknownElements)
+
+	updated, err := updateMultiBranchPipelineConfigXml(config, "", pipeline)
+	assert.Nil(t, err)
+	assert.Equal(t, config, updated)
+}
+
+func Test_appendUnknownElements(t *testing.T) {
+	root := etree.NewElement("flow-definition")
+	err := appendUnknownElements(root, []devopsv1alpha3.UnknownElement{{Tag: "quietPeriod", XML: "<other/>"}})
+	assert.NotNil(t, err)
+
+	err = appendUnknownElements(root, []devopsv1alpha3.UnknownElement{{Tag: "quietPeriod", XML: "<quietPeriod"}})
+	assert.NotNil(t, err)
+
+	err = appendUnknownElements(root, []devopsv1alpha3.UnknownElement{
+		{Path: "properties", Tag: "foo", XML: "<foo>1</foo>"},
+		{Path: "properties", Tag: "foo", XML: "<foo>2</foo>"},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, root.FindElements("properties/foo"), 1)
+	assert.Equal(t, "1", root.FindElement("properties/foo").Text())
+}
+
+func Test_NoScmPipelineConfig_TypedParam(t *testing.T) {
+	input := &devopsv1alpha3.NoScmPipeline{
+		Name:        "",
+		Description: "for test",
+		Jenkinsfile: "node{echo 'hello'}",
+		Parameters: []devopsv1alpha3.ParameterDefinition{{
+			Name:         "a",
+			DefaultValue: "abc",
+			Type:         "string",
+			Trim:         true,
+		}, {
+			Name:         "b",
+			DefaultValue: "secret",
+			Type:         "password",
+		}, {
+			Name:         "c",
+			DefaultValue: "v1.0.0",
+			Type:         "validating_string",
+			Validation: &devopsv1alpha3.StringParameterValidation{
+				Regex:                   `v\d+\.\d+\.\d+`,
+				FailedValidationMessage: "not a version",
+			},
+		}, {
+			Name:         "d",
+			DefaultValue: "github",
+			Type:         "credentials",
+			Credentials: &devopsv1alpha3.CredentialsParameterOption{
+				CredentialType: "com.cloudbees.plugins.credentials.impl.UsernamePasswordCredentialsImpl",
+				Required:       true,
+			},
+		}, {
+			Name: "e",
+			Type: "run",
+			Run: &devopsv1alpha3.RunParameterOption{
+				ProjectName: "project/build",
+				Filter:      "SUCCESSFUL",
+			},
+		}, {
+			Name:         "f",
+			DefaultValue: "amd64,arm64",
+			Type:         "extended_choice",
+			ExtendedChoice: &devopsv1alpha3.ExtendedChoiceParameterOption{
+				SelectType:       devopsv1alpha3.ExtendedChoiceMultiSelect,
+				Choices:          []string{"amd64", "arm64", "s390x"},
+				Delimiter:        ";",
+				VisibleItemCount: 3,
+			},
+		}},
+	}
+	config, err := createPipelineConfigXml(input)
+	assert.Nil(t, err)
+	output, err := parsePipelineConfigXml(config)
+	assert.Nil(t, err)
+	assert.Equal(t, input, output)
+
+	updated, err := updatePipelineConfigXml(config, output)
+	assert.Nil(t, err)
+	assert.Equal(t, config, updated)
+}
+
+func Test_NoScmPipelineConfig_UnsupportedParam(t *testing.T) {
+	config := `<?xml version='1.0' encoding='UTF-8'?>
+<flow-definition plugin="workflow-job">
+  <description>for test</description>
+  <properties>
+    <hudson.model.ParametersDefinitionProperty>
+      <parameterDefinitions>
+        <hudson.model.StringParameterDefinition>
+          <name>a</name>
+          <description></description>
+          <defaultValue>abc</defaultValue>
+          <trim>false</trim>
+        </hudson.model.StringParameterDefinition>
+        <org.example.UnknownParameterDefinition plugin="example">
+          <name>u</name>
+          <description>unknown</description>
+          <options>x</options>
+        </org.example.UnknownParameterDefinition>
+      </parameterDefinitions>
+    </hudson.model.ParametersDefinitionProperty>
+  </properties>
+  <definition class="org.jenkinsci.plugins.workflow.cps.CpsFlowDefinition" plugin="workflow-cps">
+    <script>node{echo 'hello'}</script>
+    <sandbox>true</sandbox>
+  </definition>
+  <disabled>false</disabled>
+</flow-definition>`
+	pipeline, err := parsePipelineConfigXml(config)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(pipeline.Parameters))
+	assert.Equal(t, "org.example.UnknownParameterDefinition", pipeline.Parameters[1].Type)
+
+	pipeline.Parameters[0].DefaultValue = "def"
+	updated, err := updatePipelineConfigXml(config, pipeline)
+	assert.Nil(t, err)
+	assert.Contains(t, updated, "<defaultValue>def</defaultValue>")
+	assert.Contains(t, updated, `<org.example.UnknownParameterDefinition plugin="example">`)
+	assert.Contains(t, updated, "<options>x</options>")
+
+	// the unsupported parameter is removed when it is absent from the pipeline
+	pipeline.Parameters = pipeline.Parameters[:1]
+	updated, err = updatePipelineConfigXml(config, pipeline)
+	assert.Nil(t, err)
+	assert.NotContains(t, updated, "UnknownParameterDefinition")
+}
+
+func Test_NoScmPipelineConfig_ScmDefinition(t *testing.T) {
+	inputs := []*devopsv1alpha3.NoScmPipeline{{
+		Description: "for test",
+		ScmDefinition: &devopsv1alpha3.ScmFlowDefinition{
+			SourceType: devopsv1alpha3.SourceTypeGit,
+			GitSource: &devopsv1alpha3.GitSource{
+				Url:          "https://github.com/opswave/go-jenkins.git",
+				CredentialId: "github",
+				CloneOption:  &devopsv1alpha3.GitCloneOption{Shallow: true, Depth: 1, Timeout: 20},
+				CheckoutOption: &devopsv1alpha3.GitCheckoutOption{
+					LFS:                 true,
+					SparseCheckoutPaths: []string{"ci"},
+					RefSpecs:            []string{"+refs/heads/main:refs/remotes/origin/main"},
+				},
+			},
+			Branch:      "*/main",
+			ScriptPath:  "ci/Jenkinsfile",
+			Lightweight: true,
+		},
+	}, {
+		Description: "for test",
+		ScmDefinition: &devopsv1alpha3.ScmFlowDefinition{
+			SourceType: devopsv1alpha3.SourceTypeGit,
+			GitSource:  &devopsv1alpha3.GitSource{Url: "https://github.com/opswave/go-jenkins.git"},
+		},
+	}, {
+		Description: "for test",
+		ScmDefinition: &devopsv1alpha3.ScmFlowDefinition{
+			SourceType: devopsv1alpha3.SourceTypeSVN,
+			SvnSource: &devopsv1alpha3.SingleSvnSource{
+				Remote:       "https://svn.example.com/repo/trunk",
+				CredentialId: "svn",
+			},
+		},
+	}}
+	for _, input := range inputs {
+		config, err := createPipelineConfigXml(input)
+		assert.Nil(t, err)
+		assert.Contains(t, config, CpsScmFlowDefinitionClass)
+		assert.NotContains(t, config, "<script>")
+
+		output, err := parsePipelineConfigXml(config)
+		assert.Nil(t, err)
+		assert.Nil(t, output.UnknownElements)
+		assert.Equal(t, input, output)
+
+		updated, err := updatePipelineConfigXml(config, output)
+		assert.Nil(t, err)
+		assert.Equal(t, config, updated)
+	}
+}
+
+func Test_NoScmPipelineConfig_SwitchToScmDefinition(t *testing.T) {
+	config, err := createPipelineConfigXml(&devopsv1alpha3.NoScmPipeline{Jenkinsfile: "node{echo 'hello'}"})
+	assert.Nil(t, err)
+
+	pipeline := &devopsv1alpha3.NoScmPipeline{
+		ScmDefinition: &devopsv1alpha3.ScmFlowDefinition{
+			SourceType: devopsv1alpha3.SourceTypeGit,
+			GitSource:  &devopsv1alpha3.GitSource{Url: "https://github.com/opswave/go-jenkins.git"},
+		},
+	}
+	updated, err := updatePipelineConfigXml(config, pipeline)
+	assert.Nil(t, err)
+	output, err := parsePipelineConfigXml(updated)
+	assert.Nil(t, err)
+	assert.Equal(t, "", output.Jenkinsfile)
+	assert.Contains(t, updated, "<name>*/master</name>")
+	assert.Contains(t, updated, "<scriptPath>Jenkinsfile</scriptPath>")
+	// the defaults are parsed back to empty fields
+	assert.Equal(t, "", output.ScmDefinition.Branch)
+	assert.Equal(t, "", output.ScmDefinition.ScriptPath)
+	assert.Equal(t, "https://github.com/opswave/go-jenkins.git", output.ScmDefinition.GitSource.Url)
+
+	// and back to the inline Jenkinsfile
+	updated, err = updatePipelineConfigXml(updated, &devopsv1alpha3.NoScmPipeline{Jenkinsfile: "node{echo 'world'}"})
+	assert.Nil(t, err)
+	output, err = parsePipelineConfigXml(updated)
+	assert.Nil(t, err)
+	assert.Nil(t, output.ScmDefinition)
+	assert.Equal(t, "node{echo 'world'}", output.Jenkinsfile)
+}
+
+func Test_NoScmPipelineConfig_UnsupportedScm(t *testing.T) {
+	config := `<?xml version='1.1' encoding='UTF-8'?>
+<flow-definition plugin="workflow-job">
+  <description>for test</description>
+  <keepDependencies>false</keepDependencies>
+  <properties/>
+  <definition class="org.jenkinsci.plugins.workflow.cps.CpsScmFlowDefinition" plugin="workflow-cps">
+    <scm class="hudson.plugins.mercurial.MercurialSCM" plugin="mercurial">
+      <source>https://hg.example.com/repo</source>
+      <clean>false</clean>
+    </scm>
+    <scriptPath>ci/Jenkinsfile</scriptPath>
+    <lightweight>true</lightweight>
+  </definition>
+  <triggers/>
+  <disabled>false</disabled>
+</flow-definition>
+`
+	pipeline, err := parsePipelineConfigXml(config)
+	assert.Nil(t, err)
+	assert.Equal(t, "for test", pipeline.Description)
+	assert.Equal(t, &devopsv1alpha3.ScmFlowDefinition{ScriptPath: "ci/Jenkinsfile", Lightweight: true}, pipeline.ScmDefinition)
+
+	// the definition is kept as it is
+	pipeline.Disabled = true
+	updated, err := updatePipelineConfigXml(config, pipeline)
+	assert.Nil(t, err)
+	assert.Contains(t, updated, "<disabled>true</disabled>")
+	assert.Contains(t, updated, `<scm class="hudson.plugins.mercurial.MercurialSCM" plugin="mercurial">`)
+	assert.Contains(t, updated, "<source>https://hg.example.com/repo</source>")
+
+	// but it cannot be created
+	_, err = createPipelineConfigXml(pipeline)
+	assert.NotNil(t, err)
+}
+
+func Test_NoScmPipelineConfig_InvalidScmDefinition(t *testing.T) {
+	config, err := createPipelineConfigXml(&devopsv1alpha3.NoScmPipeline{Jenkinsfile: "node{echo 'hello'}"})
+	assert.Nil(t, err)
+
+	definitions := []*devopsv1alpha3.ScmFlowDefinition{
+		{SourceType: devopsv1alpha3.SourceTypeGit},
+		{SourceType: devopsv1alpha3.SourceTypeSVN},
+		{SourceType: "mercurial", GitSource: &devopsv1alpha3.GitSource{Url: "https://github.com/opswave/go-jenkins.git"}},
+	}
+	for _, definition := range definitions {
+		pipeline := &devopsv1alpha3.NoScmPipeline{ScmDefinition: definition}
+		_, err := createPipelineConfigXml(pipeline)
+		assert.NotNil(t, err)
+		_, err = updatePipelineConfigXml(config, pipeline)
+		assert.NotNil(t, err)
+	}
+}
+
+func Test_NoScmPipelineConfig_Triggers(t *testing.T) {
+	input := &devopsv1alpha3.NoScmPipeline{
+		Description:  "for test",
+		Jenkinsfile:  "node{echo 'hello'}",
+		TimerTrigger: &devopsv1alpha3.TimerTrigger{Cron: "H 1 * * *"},
+		UpstreamTrigger: &devopsv1alpha3.UpstreamTrigger{
+			Projects:  []string{"build", "p/test"},
+			Threshold: devopsv1alpha3.UpstreamThresholdUnstable,
+		},
+		ScmTrigger:        &devopsv1alpha3.ScmTrigger{Schedule: "H/5 * * * *", IgnorePostCommitHooks: true},
+		GithubPushTrigger: &devopsv1alpha3.GithubPushTrigger{},
+		GitlabPushTrigger: &devopsv1alpha3.GitlabPushTrigger{
+			TriggerOnPush:         true,
+			TriggerOnMergeRequest: true,
+			IncludeBranches:       "main, release",
+			SecretToken:           "secret",
+		},
+	}
+	config, err := createPipelineConfigXml(input)
+	assert.Nil(t, err)
+	assert.Contains(t, config, "<upstreamProjects>build, p/test</upstreamProjects>")
+	assert.Contains(t, config, "<branchFilterType>NameBasedFilter</branchFilterType>")
+
+	output, err := parsePipelineConfigXml(config)
+	assert.Nil(t, err)
+	assert.Nil(t, output.UnknownElements)
+	assert.Equal(t, input, output)
+
+	updated, err := updatePipelineConfigXml(config, output)
+	assert.Nil(t, err)
+	assert.Equal(t, config, updated)
+
+	// the triggers are replaced in place and removed when they are unset
+	output.UpstreamTrigger.Threshold = devopsv1alpha3.UpstreamThresholdFailure
+	output.ScmTrigger = nil
+	output.GithubPushTrigger = nil
+	updated, err = updatePipelineConfigXml(config, output)
+	assert.Nil(t, err)
+	assert.NotContains(t, updated, "SCMTrigger")
+	assert.NotContains(t, updated, "GitHubPushTrigger")
+	assert.Less(t, strings.Index(updated, "TimerTrigger"), strings.Index(updated, "ReverseBuildTrigger"))
+	assert.Less(t, strings.Index(updated, "ReverseBuildTrigger"), strings.Index(updated, "GitLabPushTrigger"))
+	reparsed, err := parsePipelineConfigXml(updated)
+	assert.Nil(t, err)
+	assert.Equal(t, output, reparsed)
+}
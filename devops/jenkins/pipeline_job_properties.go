@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+// artifactDiscarderValue returns the artifact retention written to the LogRotator, -1 means the artifacts
+// are kept as long as the builds
+func artifactDiscarderValue(value string) string {
+	if value == "" {
+		return "-1"
+	}
+	return value
+}
+
+func parseArtifactDiscarderValue(value string) string {
+	if value == "-1" {
+		return ""
+	}
+	return value
+}
+
+// replaceJobPropertiesInEtree updates the job properties in place, so the order of the existing properties is kept
+func replaceJobPropertiesInEtree(properties *etree.Element, pipeline *devopsv1alpha3.NoScmPipeline) {
+	if pipeline.DurabilityHint != "" {
+		property := addOrUpdateJobProperty(properties, DurabilityHintJobTag, "workflow-job")
+		setChildElementText(property, "hint", pipeline.DurabilityHint)
+	} else {
+		removeChildElement(properties, DurabilityHintJobTag)
+	}
+
+	if pipeline.DisableResume {
+		addOrUpdateJobProperty(properties, DisableResumeJobTag, "workflow-job")
+	} else {
+		removeChildElement(properties, DisableResumeJobTag)
+	}
+
+	if pipeline.GithubProject != nil {
+		property := addOrUpdateJobProperty(properties, GithubProjectTag, "github")
+		setChildElementText(property, "projectUrl", pipeline.GithubProject.ProjectURL)
+		setChildElementText(property, "displayName", pipeline.GithubProject.DisplayName)
+	} else {
+		removeChildElement(properties, GithubProjectTag)
+	}
+
+	if pipeline.GitlabConnection != nil {
+		property := addOrUpdateJobProperty(properties, GitlabConnectionTag, "gitlab-plugin")
+		setChildElementText(property, "gitLabConnection", pipeline.GitlabConnection.Connection)
+	} else {
+		removeChildElement(properties, GitlabConnectionTag)
+	}
+
+	if pipeline.RateLimit != nil {
+		durationName := pipeline.RateLimit.DurationName
+		if durationName == "" {
+			durationName = "hour"
+		}
+		property := addOrUpdateJobProperty(properties, RateLimitJobTag, "branch-api")
+		setChildElementText(property, "durationName", durationName)
+		setChildElementText(property, "count", strconv.Itoa(pipeline.RateLimit.Count))
+		setChildElementText(property, "userBoost", strconv.FormatBool(pipeline.RateLimit.UserBoost))
+	} else {
+		removeChildElement(properties, RateLimitJobTag)
+	}
+
+	if pipeline.LockableResources != nil {
+		resources := pipeline.LockableResources
+		quantity := ""
+		if resources.Quantity > 0 {
+			quantity = strconv.Itoa(resources.Quantity)
+		}
+		property := addOrUpdateJobProperty(properties, LockableResourcesTag, "lockable-resources")
+		setChildElementText(property, "resourceNames", strings.Join(resources.ResourceNames, " "))
+		setChildElementText(property, "resourceNamesVar", resources.Variable)
+		setChildElementText(property, "resourceNumber", quantity)
+		setChildElementText(property, "labelName", resources.Label)
+	} else {
+		removeChildElement(properties, LockableResourcesTag)
+	}
+}
+
+func getJobPropertiesFromEtree(properties *etree.Element, pipeline *devopsv1alpha3.NoScmPipeline) {
+	if property := properties.SelectElement(DurabilityHintJobTag); property != nil {
+		pipeline.DurabilityHint = getElementTextValueOrEmpty(property, "hint")
+	}
+	pipeline.DisableResume = properties.SelectElement(DisableResumeJobTag) != nil
+
+	if property := properties.SelectElement(GithubProjectTag); property != nil {
+		pipeline.GithubProject = &devopsv1alpha3.GithubProjectProperty{
+			ProjectURL:  getElementTextValueOrEmpty(property, "projectUrl"),
+			DisplayName: getElementTextValueOrEmpty(property, "displayName"),
+		}
+	}
+	if property := properties.SelectElement(GitlabConnectionTag); property != nil {
+		pipeline.GitlabConnection = &devopsv1alpha3.GitlabConnectionProperty{
+			Connection: getElementTextValueOrEmpty(property, "gitLabConnection"),
+		}
+	}
+	if property := properties.SelectElement(RateLimitJobTag); property != nil {
+		count, _ := strconv.Atoi(getElementTextValueOrEmpty(property, "count"))
+		pipeline.RateLimit = &devopsv1alpha3.RateLimitProperty{
+			Count:        count,
+			DurationName: getElementTextValueOrEmpty(property, "durationName"),
+			UserBoost:    getElementTextValueOrEmpty(property, "userBoost") == "true",
+		}
+	}
+	if property := properties.SelectElement(LockableResourcesTag); property != nil {
+		quantity, _ := strconv.Atoi(getElementTextValueOrEmpty(property, "resourceNumber"))
+		pipeline.LockableResources = &devopsv1alpha3.LockableResourcesProperty{
+			ResourceNames: strings.Fields(getElementTextValueOrEmpty(property, "resourceNames")),
+			Label:         getElementTextValueOrEmpty(property, "labelName"),
+			Quantity:      quantity,
+			Variable:      getElementTextValueOrEmpty(property, "resourceNamesVar"),
+		}
+	}
+}
+
+func addOrUpdateJobProperty(properties *etree.Element, tag, plugin string) *etree.Element {
+	property := properties.SelectElement(tag)
+	if property == nil {
+		property = properties.CreateElement(tag)
+		property.CreateAttr(PluginKey, plugin)
+	}
+	return property
+}
+
+// setChildElementText sets the text of the child element, unlike addOrUpdateElement the text could be empty
+func setChildElementText(parent *etree.Element, tag, text string) {
+	addOrUpdateElement(parent, tag, StringNull).SetText(text)
+}
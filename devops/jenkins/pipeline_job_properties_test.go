@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+func newJobPropertiesPipeline() *devopsv1alpha3.NoScmPipeline {
+	quietPeriod := 0
+	return &devopsv1alpha3.NoScmPipeline{
+		Description:       "for test",
+		Jenkinsfile:       "node{echo 'hello'}",
+		DisableConcurrent: true,
+		AbortPrevious:     true,
+		QuietPeriod:       &quietPeriod,
+		DurabilityHint:    devopsv1alpha3.DurabilityHintPerformanceOptimized,
+		DisableResume:     true,
+		Discarder: &devopsv1alpha3.DiscarderProperty{
+			DaysToKeep:         "7",
+			NumToKeep:          "10",
+			ArtifactDaysToKeep: "1",
+			ArtifactNumToKeep:  "2",
+		},
+		GithubProject: &devopsv1alpha3.GithubProjectProperty{
+			ProjectURL:  "https://github.com/opswave/go-jenkins/",
+			DisplayName: "ci",
+		},
+		GitlabConnection: &devopsv1alpha3.GitlabConnectionProperty{Connection: "gitlab"},
+		RateLimit: &devopsv1alpha3.RateLimitProperty{
+			Count:        5,
+			DurationName: "minute",
+			UserBoost:    true,
+		},
+		LockableResources: &devopsv1alpha3.LockableResourcesProperty{
+			ResourceNames: []string{"db", "cache"},
+			Quantity:      1,
+			Variable:      "LOCKED",
+		},
+	}
+}
+
+func Test_NoScmPipelineConfig_JobProperties(t *testing.T) {
+	input := newJobPropertiesPipeline()
+	config, err := createPipelineConfigXml(input)
+	assert.Nil(t, err)
+	assert.Contains(t, config, "<artifactDaysToKeep>1</artifactDaysToKeep>")
+	assert.Contains(t, config, "<abortPrevious>true</abortPrevious>")
+	assert.Contains(t, config, "<resourceNames>db cache</resourceNames>")
+
+	output, err := parsePipelineConfigXml(config)
+	assert.Nil(t, err)
+	assert.Nil(t, output.UnknownElements)
+	assert.Equal(t, input, output)
+
+	updated, err := updatePipelineConfigXml(config, output)
+	assert.Nil(t, err)
+	assert.Equal(t, config, updated)
+}
+
+func Test_NoScmPipelineConfig_UpdateJobProperties(t *testing.T) {
+	config, err := createPipelineConfigXml(&devopsv1alpha3.NoScmPipeline{
+		Jenkinsfile: "node{echo 'hello'}",
+		Discarder:   &devopsv1alpha3.DiscarderProperty{DaysToKeep: "7", NumToKeep: "10"},
+	})
+	assert.Nil(t, err)
+	assert.Contains(t, config, "<artifactNumToKeep>-1</artifactNumToKeep>")
+
+	// the properties are added to the existing config, the description is not a part of config update
+	input := newJobPropertiesPipeline()
+	input.Description = ""
+	updated, err := updatePipelineConfigXml(config, input)
+	assert.Nil(t, err)
+	assert.Less(t, strings.Index(updated, "<quietPeriod>"), strings.Index(updated, "<disabled>"))
+	output, err := parsePipelineConfigXml(updated)
+	assert.Nil(t, err)
+	assert.Equal(t, input, output)
+
+	// and removed when they are unset
+	updated, err = updatePipelineConfigXml(updated, &devopsv1alpha3.NoScmPipeline{
+		Jenkinsfile:       "node{echo 'hello'}",
+		DisableConcurrent: true,
+	})
+	assert.Nil(t, err)
+	for _, tag := range []string{QuietPeriodTag, DurabilityHintJobTag, DisableResumeJobTag, GithubProjectTag,
+		GitlabConnectionTag, RateLimitJobTag, LockableResourcesTag, BuildDiscarderTag} {
+		assert.NotContains(t, updated, tag)
+	}
+	output, err = parsePipelineConfigXml(updated)
+	assert.Nil(t, err)
+	assert.True(t, output.DisableConcurrent)
+	assert.False(t, output.AbortPrevious)
+	assert.Nil(t, output.QuietPeriod)
+}
@@ -0,0 +1,371 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/opswave/go-jenkins/devops"
+	"github.com/opswave/go-jenkins/devops/jenkins/triggers"
+	"github.com/opswave/go-jenkins/devops/util"
+)
+
+const (
+	pipelineTriggerPropertyTag = "org.jenkinsci.plugins.workflow.multibranch.PipelineTriggerProperty"
+	credentialsIdTag           = "credentialsId"
+)
+
+// MoveProjectPipeline moves a pipeline to another project by the move action of the folder plugin, the builds and
+// the config history are moved together. The credentials are replaced as the mapping of the options after moving,
+// and the relative names in the triggers of the pipeline are rebased to the previous project
+func (j *Jenkins) MoveProjectPipeline(projectId, pipelineId, targetProjectId string,
+	options *devops.PipelineRelocateOptions) (*devops.PipelineRelocateResult, error) {
+	if projectId == targetProjectId {
+		return nil, restful.NewError(http.StatusBadRequest,
+			fmt.Sprintf("pipeline %s is already in project %s", pipelineId, targetProjectId))
+	}
+	job, err := j.checkPipelineRelocation(projectId, pipelineId, targetProjectId, pipelineId)
+	if err != nil {
+		return nil, err
+	}
+
+	query := map[string]string{"destination": "/" + targetProjectId}
+	if _, err = j.Requester.Post(job.Base+"/move/move", nil, nil, query); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+
+	oldName, newName := projectId+"/"+pipelineId, targetProjectId+"/"+pipelineId
+	j.moveConfigHistory(oldName, newName)
+
+	result := &devops.PipelineRelocateResult{ProjectId: targetProjectId, Pipeline: pipelineId}
+	if result.MissingCredentials, err = j.updateMovedPipeline(projectId, targetProjectId, pipelineId, options); err != nil {
+		return nil, err
+	}
+	for _, project := range []string{projectId, targetProjectId} {
+		updated, err := j.renamePipelineInTriggers(project, oldName, newName)
+		if err != nil {
+			return nil, err
+		}
+		result.UpdatedTriggers = append(result.UpdatedTriggers, updated...)
+	}
+	return result, nil
+}
+
+// RenameProjectPipeline renames a pipeline with its config history, the pipelines of the project whose triggers name
+// it are updated
+func (j *Jenkins) RenameProjectPipeline(projectId, pipelineId, newName string) (*devops.PipelineRelocateResult, error) {
+	if newName == pipelineId {
+		return nil, restful.NewError(http.StatusBadRequest, fmt.Sprintf("pipeline %s is already named %s", pipelineId, newName))
+	}
+	job, err := j.checkPipelineRelocation(projectId, pipelineId, projectId, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = j.Requester.Post(job.Base+"/confirmRename", nil, nil, map[string]string{"newName": newName}); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+
+	j.moveConfigHistory(projectId+"/"+pipelineId, projectId+"/"+newName)
+
+	result := &devops.PipelineRelocateResult{ProjectId: projectId, Pipeline: newName}
+	if result.UpdatedTriggers, err = j.renamePipelineInTriggers(projectId,
+		projectId+"/"+pipelineId, projectId+"/"+newName); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CopyProjectPipeline creates a pipeline in the target project with the config of the given one. The credentials
+// are replaced as the mapping of the options, the copy references them instead of the credentials in the source
+func (j *Jenkins) CopyProjectPipeline(projectId, pipelineId, targetProjectId, targetName string,
+	options *devops.PipelineRelocateOptions) (*devops.PipelineRelocateResult, error) {
+	job, err := j.checkPipelineRelocation(projectId, pipelineId, targetProjectId, targetName)
+	if err != nil {
+		return nil, err
+	}
+	config, err := job.GetConfig()
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	if options != nil && len(options.CredentialMapping) > 0 {
+		if config, err = replaceCredentialsInConfig(config, options.CredentialMapping); err != nil {
+			klog.Errorf("%+v", err)
+			return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	// create the copy with the config instead of the copy mode of Jenkins,
+	// the builds of a copied job are held off until its config is saved again
+	if _, err = j.CreateJobInFolder(config, targetName, targetProjectId); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+
+	result := &devops.PipelineRelocateResult{ProjectId: targetProjectId, Pipeline: targetName}
+	if result.MissingCredentials, err = j.findMissingCredentials(targetProjectId, job.Raw.Class, targetName, config); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkPipelineRelocation returns the job of the pipeline to be relocated,
+// it fails if the target project does not exist or it already has an item with the target name
+func (j *Jenkins) checkPipelineRelocation(projectId, pipelineId, targetProjectId, targetName string) (*Job, error) {
+	job, err := j.GetJob(pipelineId, projectId)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	if !isPipelineJobClass(job.Raw.Class) {
+		err = fmt.Errorf("unsupported job class %s", job.Raw.Class)
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(http.StatusBadRequest, err.Error())
+	}
+	if _, err = j.GetJob(targetProjectId); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+
+	_, err = j.GetJob(targetName, targetProjectId)
+	if err == nil {
+		return nil, restful.NewError(http.StatusConflict,
+			fmt.Sprintf("pipeline %s already exists in project %s", targetName, targetProjectId))
+	}
+	if code := devops.GetDevOpsStatusCode(err); code != http.StatusNotFound {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(code, err.Error())
+	}
+	return job, nil
+}
+
+// updateMovedPipeline replaces the credentials of a pipeline moved from the previous project as the mapping, and
+// rebases the relative names in its triggers. The config is saved only if it is changed. It returns the credentials
+// referenced by the pipeline but not found in its project
+func (j *Jenkins) updateMovedPipeline(previousProjectId, projectId, pipelineId string,
+	options *devops.PipelineRelocateOptions) ([]string, error) {
+	job, err := j.GetJob(pipelineId, projectId)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	config, err := job.GetConfig()
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	replaced := config
+	if options != nil && len(options.CredentialMapping) > 0 {
+		if replaced, err = replaceCredentialsInConfig(replaced, options.CredentialMapping); err != nil {
+			klog.Errorf("%+v", err)
+			return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+		}
+	}
+	if replaced, _, err = rebaseTriggerConfig(replaced, previousProjectId); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	if replaced != config {
+		if err = job.UpdateConfig(replaced); err != nil {
+			klog.Errorf("%+v", err)
+			return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+		config = replaced
+	}
+	return j.findMissingCredentials(projectId, job.Raw.Class, pipelineId, config)
+}
+
+// findMissingCredentials returns the sorted credentials referenced by the config of a pipeline which are not found
+// in the project. They are not necessarily broken references, because global credentials are not listed
+func (j *Jenkins) findMissingCredentials(projectId, class, pipelineId, config string) ([]string, error) {
+	pipeline, err := parseJobConfig(class, pipelineId, config)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	references := util.FindCredentialReferences(pipeline)
+	if len(references) == 0 {
+		return nil, nil
+	}
+
+	credentials, err := j.ListCredentialsInProject(projectId)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]bool{}
+	for _, credential := range credentials {
+		existing[credential.Id] = true
+	}
+	var missing []string
+	for id := range references {
+		if !existing[id] {
+			missing = append(missing, id)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// replaceCredentialsInConfig replaces the credentials of the credentialsId elements and the Jenkinsfile of a job
+func replaceCredentialsInConfig(config string, mapping map[string]string) (string, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(replaceXmlVersion(config, "1.1", "1.0")); err != nil {
+		return "", err
+	}
+	for _, element := range doc.FindElements("//" + credentialsIdTag) {
+		if id, ok := mapping[strings.TrimSpace(element.Text())]; ok {
+			element.SetText(id)
+		}
+	}
+	for _, element := range doc.FindElements("//" + ScriptTag) {
+		element.SetText(util.ReplaceCredentialsInJenkinsfile(element.Text(), mapping))
+	}
+	stringXml, err := doc.WriteToString()
+	if err != nil {
+		return "", err
+	}
+	return replaceXmlVersion(stringXml, "1.0", "1.1"), nil
+}
+
+// renamePipelineInTriggers updates the pipelines of a project whose triggers name the old pipeline, both the trigger
+// property of the multi-branch pipelines and the upstream triggers are checked. The names are full names, a name in
+// a trigger could be relative to the project of the pipeline. It returns the full names of the updated pipelines
+func (j *Jenkins) renamePipelineInTriggers(projectId, oldName, newName string) ([]string, error) {
+	folder, err := j.GetFolder(projectId)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+
+	var updated []string
+	for _, item := range folder.Raw.Jobs {
+		if !isPipelineJobClass(item.Class) {
+			continue
+		}
+		job, err := j.GetJob(item.Name, projectId)
+		if err != nil {
+			klog.Errorf("%+v", err)
+			return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+		config, err := job.GetConfig()
+		if err != nil {
+			klog.Errorf("%+v", err)
+			return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+		replaced, changed, err := renameJobInTriggerConfig(config, projectId, oldName, newName)
+		if err != nil {
+			klog.Errorf("%+v", err)
+			return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+		}
+		if !changed {
+			continue
+		}
+		if err = job.UpdateConfig(replaced); err != nil {
+			klog.Errorf("%+v", err)
+			return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+		updated = append(updated, projectId+"/"+item.Name)
+	}
+	return updated, nil
+}
+
+// renameJobInTriggerConfig replaces the old job in the triggers of a pipeline in the project.
+// A relative name is kept relative if the new job is still in the project
+func renameJobInTriggerConfig(config, projectId, oldName, newName string) (string, bool, error) {
+	return replaceTriggerNamesInConfig(config, func(name string) string {
+		fullName := strings.TrimPrefix(name, "/")
+		if !strings.Contains(fullName, "/") {
+			fullName = projectId + "/" + fullName
+		}
+		if fullName != oldName {
+			return name
+		}
+		if !strings.Contains(name, "/") && strings.HasPrefix(newName, projectId+"/") {
+			return strings.TrimPrefix(newName, projectId+"/")
+		}
+		return newName
+	})
+}
+
+// rebaseTriggerConfig makes the relative names in the triggers of a pipeline absolute, they are relative to the
+// project the pipeline was in
+func rebaseTriggerConfig(config, projectId string) (string, bool, error) {
+	return replaceTriggerNamesInConfig(config, func(name string) string {
+		if strings.Contains(name, "/") {
+			return name
+		}
+		return "/" + projectId + "/" + name
+	})
+}
+
+// replaceTriggerNamesInConfig replaces the names of the jobs in the trigger property of a multi-branch pipeline,
+// and the upstream projects of the upstream trigger
+func replaceTriggerNamesInConfig(config string, replace func(name string) string) (string, bool, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(replaceXmlVersion(config, "1.1", "1.0")); err != nil {
+		return "", false, err
+	}
+	var elements []*etree.Element
+	if property := doc.FindElement("//" + pipelineTriggerPropertyTag); property != nil {
+		for _, tag := range []string{"createActionJobsToTrigger", "deleteActionJobsToTrigger"} {
+			if element := property.SelectElement(tag); element != nil {
+				elements = append(elements, element)
+			}
+		}
+	}
+	elements = append(elements, doc.FindElements("//"+triggers.ReverseBuildTriggerTag+"/upstreamProjects")...)
+
+	changed := false
+	for _, element := range elements {
+		if element.Text() == "" {
+			continue
+		}
+		names := strings.Split(element.Text(), ",")
+		elementChanged := false
+		for i, name := range names {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			if replaced := replace(name); replaced != name {
+				names[i] = replaced
+				elementChanged = true
+			}
+		}
+		if elementChanged {
+			element.SetText(strings.Join(names, ","))
+			changed = true
+		}
+	}
+	if !changed {
+		return config, false, nil
+	}
+	stringXml, err := doc.WriteToString()
+	if err != nil {
+		return "", false, err
+	}
+	return replaceXmlVersion(stringXml, "1.0", "1.1"), true, nil
+}
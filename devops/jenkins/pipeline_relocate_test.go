@@ -0,0 +1,369 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opswave/go-jenkins/devops"
+	"github.com/opswave/go-jenkins/devops/history"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+// newFakeProjectsJenkins starts a fake Jenkins of the projects, which are the job configs keyed by the names.
+// The projects and credentials are changed by the requests, the operations are recorded
+func newFakeProjectsJenkins(t *testing.T, projects map[string]map[string]string, credentials map[string][]string) (*Jenkins, *[]string) {
+	var mutex sync.Mutex
+	operations := &[]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/job/"), "/", 2)
+		configs, ok := projects[parts[0]]
+		if !ok || len(parts) < 2 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		projectId, path := parts[0], parts[1]
+		switch path {
+		case "api/json":
+			var jobs []string
+			for name, config := range configs {
+				jobs = append(jobs, `{"_class":"`+configClass(config)+`","name":"`+name+`"}`)
+			}
+			_, _ = w.Write([]byte(`{"_class":"` + FolderClass + `","name":"` + projectId + `","jobs":[` + strings.Join(jobs, ",") + `]}`))
+			return
+		case "credentials/store/folder/api/json":
+			_, _ = w.Write([]byte(`{"domains":{"_":{}}}`))
+			return
+		case "credentials/store/folder/domain/_/api/json":
+			var items []string
+			for _, id := range credentials[projectId] {
+				items = append(items, `{"id":"`+id+`"}`)
+			}
+			_, _ = w.Write([]byte(`{"credentials":[` + strings.Join(items, ",") + `]}`))
+			return
+		case "createItem":
+			body, _ := io.ReadAll(r.Body)
+			configs[r.URL.Query().Get("name")] = string(body)
+			*operations = append(*operations, "create "+projectId+"/"+r.URL.Query().Get("name"))
+			return
+		}
+
+		name := strings.Split(strings.TrimPrefix(path, "job/"), "/")[0]
+		config, ok := configs[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/move/move"):
+			target := strings.TrimPrefix(r.URL.Query().Get("destination"), "/")
+			delete(configs, name)
+			projects[target][name] = config
+			*operations = append(*operations, "move "+projectId+"/"+name+" "+target)
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/confirmRename"):
+			newName := r.URL.Query().Get("newName")
+			delete(configs, name)
+			configs[newName] = config
+			*operations = append(*operations, "rename "+projectId+"/"+name+" "+newName)
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/config.xml"):
+			body, _ := io.ReadAll(r.Body)
+			configs[name] = string(body)
+			*operations = append(*operations, "update "+projectId+"/"+name)
+		case strings.HasSuffix(path, "/config.xml/"):
+			_, _ = w.Write([]byte(config))
+		case strings.HasSuffix(path, "/api/json"):
+			_, _ = w.Write([]byte(`{"_class":"` + configClass(config) + `","name":"` + name + `"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return CreateJenkins(nil, server.URL, 0, "admin", "password"), operations
+}
+
+func newFakeRelocateProjects(t *testing.T) map[string]map[string]string {
+	deploy, err := createPipelineConfigXml(&devopsv1alpha3.NoScmPipeline{
+		Name: "deploy",
+		Jenkinsfile: `withCredentials([usernamePassword(credentialsId: 'docker', passwordVariable: 'PASS', usernameVariable: 'USER')]) {
+  sh 'kubectl apply'
+}
+withCredentials([kubeconfigContent(credentialsId: 'kubeconfig', variable: 'CONFIG')]) {
+  sh 'docker push'
+}`,
+	})
+	assert.Nil(t, err)
+	build, err := createMultiBranchPipelineConfigXml("fake-project", &devopsv1alpha3.MultiBranchPipeline{
+		Name:       "build",
+		SourceType: devopsv1alpha3.SourceTypeGit,
+		GitSource:  &devopsv1alpha3.GitSource{Url: "https://github.com/kubesphere/devops.git", CredentialId: "git"},
+		MultiBranchJobTrigger: &devopsv1alpha3.MultiBranchJobTrigger{
+			CreateActionJobsToTrigger: "deploy,notify",
+			DeleteActionJobsToTrigger: "fake-project/deploy",
+		},
+	})
+	assert.Nil(t, err)
+	return map[string]map[string]string{
+		"fake-project":  {"deploy": deploy, "build": build},
+		"other-project": {},
+	}
+}
+
+func TestMoveProjectPipeline(t *testing.T) {
+	projects := newFakeRelocateProjects(t)
+	jenkins, operations := newFakeProjectsJenkins(t, projects, map[string][]string{"other-project": {"registry"}})
+
+	result, err := jenkins.MoveProjectPipeline("fake-project", "deploy", "other-project", &devops.PipelineRelocateOptions{
+		CredentialMapping: map[string]string{"docker": "registry"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &devops.PipelineRelocateResult{
+		ProjectId:          "other-project",
+		Pipeline:           "deploy",
+		MissingCredentials: []string{"kubeconfig"},
+		UpdatedTriggers:    []string{"fake-project/build"},
+	}, result)
+	assert.Equal(t, []string{
+		"move fake-project/deploy other-project",
+		"update other-project/deploy",
+		"update fake-project/build",
+	}, *operations)
+
+	assert.NotContains(t, projects["fake-project"], "deploy")
+	assert.Contains(t, projects["other-project"]["deploy"], "credentialsId: &apos;registry&apos;")
+	pipeline, err := parseMultiBranchPipelineConfigXml(projects["fake-project"]["build"])
+	assert.Nil(t, err)
+	assert.Equal(t, &devopsv1alpha3.MultiBranchJobTrigger{
+		CreateActionJobsToTrigger: "other-project/deploy,notify",
+		DeleteActionJobsToTrigger: "other-project/deploy",
+	}, pipeline.MultiBranchJobTrigger)
+
+	t.Run("conflict", func(t *testing.T) {
+		projects["fake-project"]["deploy"] = projects["other-project"]["deploy"]
+		_, err := jenkins.MoveProjectPipeline("fake-project", "deploy", "other-project", nil)
+		assert.Equal(t, http.StatusConflict, err.(restful.ServiceError).Code)
+	})
+
+	t.Run("target not found", func(t *testing.T) {
+		_, err := jenkins.MoveProjectPipeline("fake-project", "deploy", "missing-project", nil)
+		assert.Equal(t, http.StatusNotFound, err.(restful.ServiceError).Code)
+	})
+}
+
+func TestMoveMultiBranchProjectPipeline(t *testing.T) {
+	projects := newFakeRelocateProjects(t)
+	jenkins, operations := newFakeProjectsJenkins(t, projects, map[string][]string{"other-project": {"git"}})
+	jenkins.ConfigHistory = history.NewMemoryStore()
+	_, err := jenkins.ConfigHistory.Add("fake-project/build", &devops.ConfigVersion{Config: "<old/>"})
+	assert.Nil(t, err)
+
+	result, err := jenkins.MoveProjectPipeline("fake-project", "build", "other-project", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, &devops.PipelineRelocateResult{ProjectId: "other-project", Pipeline: "build"}, result)
+	assert.Equal(t, []string{"move fake-project/build other-project", "update other-project/build"}, *operations)
+
+	// the relative names are relative to the previous project
+	pipeline, err := parseMultiBranchPipelineConfigXml(projects["other-project"]["build"])
+	assert.Nil(t, err)
+	assert.Equal(t, &devopsv1alpha3.MultiBranchJobTrigger{
+		CreateActionJobsToTrigger: "/fake-project/deploy,/fake-project/notify",
+		DeleteActionJobsToTrigger: "fake-project/deploy",
+	}, pipeline.MultiBranchJobTrigger)
+
+	// the history is moved with the pipeline, followed by the version before rebasing the triggers
+	versions, err := jenkins.ConfigHistory.List("other-project/build")
+	assert.Nil(t, err)
+	if assert.Len(t, versions, 2) {
+		assert.Equal(t, "<old/>", versions[0].Config)
+	}
+	versions, err = jenkins.ConfigHistory.List("fake-project/build")
+	assert.Nil(t, err)
+	assert.Empty(t, versions)
+}
+
+func TestMoveProjectPipelineWithUpstreamTriggers(t *testing.T) {
+	deploy, err := createPipelineConfigXml(&devopsv1alpha3.NoScmPipeline{
+		Jenkinsfile:     "node{echo 'deploy'}",
+		UpstreamTrigger: &devopsv1alpha3.UpstreamTrigger{Projects: []string{"build", "/other-project/scan"}},
+	})
+	assert.Nil(t, err)
+	verify, err := createPipelineConfigXml(&devopsv1alpha3.NoScmPipeline{
+		Jenkinsfile:     "node{echo 'verify'}",
+		UpstreamTrigger: &devopsv1alpha3.UpstreamTrigger{Projects: []string{"deploy", "build"}},
+	})
+	assert.Nil(t, err)
+	projects := map[string]map[string]string{
+		"fake-project":  {"deploy": deploy, "verify": verify},
+		"other-project": {},
+	}
+	jenkins, operations := newFakeProjectsJenkins(t, projects, nil)
+
+	result, err := jenkins.MoveProjectPipeline("fake-project", "deploy", "other-project", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"fake-project/verify"}, result.UpdatedTriggers)
+	assert.Equal(t, []string{
+		"move fake-project/deploy other-project",
+		"update other-project/deploy",
+		"update fake-project/verify",
+	}, *operations)
+
+	// the upstream projects of the moved pipeline are relative to the previous project
+	pipeline, err := parsePipelineConfigXml(projects["other-project"]["deploy"])
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/fake-project/build", "/other-project/scan"}, pipeline.UpstreamTrigger.Projects)
+	// the pipelines triggered by the moved pipeline follow it
+	pipeline, err = parsePipelineConfigXml(projects["fake-project"]["verify"])
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"other-project/deploy", "build"}, pipeline.UpstreamTrigger.Projects)
+}
+
+func TestRebaseTriggerConfig(t *testing.T) {
+	config, err := createMultiBranchPipelineConfigXml("fake-project", &devopsv1alpha3.MultiBranchPipeline{
+		Name:       "build",
+		SourceType: devopsv1alpha3.SourceTypeGit,
+		GitSource:  &devopsv1alpha3.GitSource{Url: "https://github.com/kubesphere/devops.git"},
+		MultiBranchJobTrigger: &devopsv1alpha3.MultiBranchJobTrigger{
+			CreateActionJobsToTrigger: "deploy,,/fake-project/test,other-project/deploy",
+		},
+	})
+	assert.Nil(t, err)
+
+	rebased, changed, err := rebaseTriggerConfig(config, "fake-project")
+	assert.Nil(t, err)
+	assert.True(t, changed)
+	pipeline, err := parseMultiBranchPipelineConfigXml(rebased)
+	assert.Nil(t, err)
+	assert.Equal(t, "/fake-project/deploy,,/fake-project/test,other-project/deploy",
+		pipeline.MultiBranchJobTrigger.CreateActionJobsToTrigger)
+
+	unchanged, changed, err := rebaseTriggerConfig(rebased, "other-project")
+	assert.Nil(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, rebased, unchanged)
+
+	// the upstream projects are rebased as well
+	config, err = createPipelineConfigXml(&devopsv1alpha3.NoScmPipeline{
+		Jenkinsfile:     "node{echo 'hello'}",
+		UpstreamTrigger: &devopsv1alpha3.UpstreamTrigger{Projects: []string{"build", "/fake-project/test"}},
+	})
+	assert.Nil(t, err)
+	rebased, changed, err = rebaseTriggerConfig(config, "fake-project")
+	assert.Nil(t, err)
+	assert.True(t, changed)
+	noScmPipeline, err := parsePipelineConfigXml(rebased)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/fake-project/build", "/fake-project/test"}, noScmPipeline.UpstreamTrigger.Projects)
+}
+
+func TestRenameProjectPipeline(t *testing.T) {
+	projects := newFakeRelocateProjects(t)
+	jenkins, operations := newFakeProjectsJenkins(t, projects, nil)
+	jenkins.ConfigHistory = history.NewMemoryStore()
+	_, err := jenkins.ConfigHistory.Add("fake-project/deploy", &devops.ConfigVersion{Config: "<old/>"})
+	assert.Nil(t, err)
+
+	result, err := jenkins.RenameProjectPipeline("fake-project", "deploy", "release")
+	assert.Nil(t, err)
+	version, err := jenkins.ConfigHistory.Get("fake-project/release", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "<old/>", version.Config)
+	assert.Equal(t, &devops.PipelineRelocateResult{
+		ProjectId:       "fake-project",
+		Pipeline:        "release",
+		UpdatedTriggers: []string{"fake-project/build"},
+	}, result)
+	assert.Equal(t, []string{"rename fake-project/deploy release", "update fake-project/build"}, *operations)
+
+	pipeline, err := parseMultiBranchPipelineConfigXml(projects["fake-project"]["build"])
+	assert.Nil(t, err)
+	assert.Equal(t, &devopsv1alpha3.MultiBranchJobTrigger{
+		CreateActionJobsToTrigger: "release,notify",
+		DeleteActionJobsToTrigger: "fake-project/release",
+	}, pipeline.MultiBranchJobTrigger)
+
+	_, err = jenkins.RenameProjectPipeline("fake-project", "release", "build")
+	assert.Equal(t, http.StatusConflict, err.(restful.ServiceError).Code)
+	_, err = jenkins.RenameProjectPipeline("fake-project", "deploy", "release")
+	assert.Equal(t, http.StatusNotFound, err.(restful.ServiceError).Code)
+}
+
+func TestCopyProjectPipeline(t *testing.T) {
+	projects := newFakeRelocateProjects(t)
+	jenkins, operations := newFakeProjectsJenkins(t, projects, map[string][]string{
+		"fake-project":  {"docker", "kubeconfig"},
+		"other-project": {"registry", "kubeconfig"},
+	})
+
+	result, err := jenkins.CopyProjectPipeline("fake-project", "deploy", "other-project", "deploy-copy", &devops.PipelineRelocateOptions{
+		CredentialMapping: map[string]string{"docker": "registry"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &devops.PipelineRelocateResult{ProjectId: "other-project", Pipeline: "deploy-copy"}, result)
+	assert.Equal(t, []string{"create other-project/deploy-copy"}, *operations)
+	assert.Contains(t, projects["fake-project"]["deploy"], "credentialsId: &apos;docker&apos;")
+	assert.Contains(t, projects["other-project"]["deploy-copy"], "credentialsId: &apos;registry&apos;")
+
+	// the credentials are not mapped without options
+	result, err = jenkins.CopyProjectPipeline("fake-project", "build", "other-project", "build", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"git"}, result.MissingCredentials)
+	assert.Equal(t, projects["fake-project"]["build"], projects["other-project"]["build"])
+}
+
+func TestRenameJobInTriggerConfig(t *testing.T) {
+	config, err := createMultiBranchPipelineConfigXml("fake-project", &devopsv1alpha3.MultiBranchPipeline{
+		Name:       "build",
+		SourceType: devopsv1alpha3.SourceTypeGit,
+		GitSource:  &devopsv1alpha3.GitSource{Url: "https://github.com/kubesphere/devops.git"},
+		MultiBranchJobTrigger: &devopsv1alpha3.MultiBranchJobTrigger{
+			CreateActionJobsToTrigger: "deploy, /fake-project/deploy,other-project/deploy",
+		},
+	})
+	assert.Nil(t, err)
+
+	replaced, changed, err := renameJobInTriggerConfig(config, "fake-project", "fake-project/deploy", "fake-project/release")
+	assert.Nil(t, err)
+	assert.True(t, changed)
+	pipeline, err := parseMultiBranchPipelineConfigXml(replaced)
+	assert.Nil(t, err)
+	assert.Equal(t, "release,fake-project/release,other-project/deploy", pipeline.MultiBranchJobTrigger.CreateActionJobsToTrigger)
+
+	unchanged, changed, err := renameJobInTriggerConfig(config, "fake-project", "fake-project/missing", "fake-project/release")
+	assert.Nil(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, config, unchanged)
+
+	// the upstream projects are renamed as well
+	config, err = createPipelineConfigXml(&devopsv1alpha3.NoScmPipeline{
+		Jenkinsfile:     "node{echo 'hello'}",
+		UpstreamTrigger: &devopsv1alpha3.UpstreamTrigger{Projects: []string{"deploy", "build"}},
+	})
+	assert.Nil(t, err)
+	replaced, changed, err = renameJobInTriggerConfig(config, "fake-project", "fake-project/deploy", "fake-project/release")
+	assert.Nil(t, err)
+	assert.True(t, changed)
+	noScmPipeline, err := parsePipelineConfigXml(replaced)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"release", "build"}, noScmPipeline.UpstreamTrigger.Projects)
+}
@@ -21,6 +21,7 @@ import (
 	"net/url"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -119,6 +120,42 @@ func TestParsePaging(t *testing.T) {
 	}
 }
 
+func TestCheckCronLocally(t *testing.T) {
+	now := time.Date(2024, 2, 27, 10, 30, 0, 0, time.UTC)
+
+	res := checkCronLocally("project", &devops.CronData{Cron: "TZ=UTC\nH(15-15) 10-12/2 * * *", Count: 2}, now)
+	assert.Equal(t, "ok", res.Result)
+	assert.Equal(t, "2024-02-27T10:15:00Z", res.LastTime)
+	assert.Equal(t, "2024-02-27T12:15:00Z", res.NextTime)
+	assert.Equal(t, []string{"2024-02-27T12:15:00Z", "2024-02-28T10:15:00Z"}, res.NextTimes)
+	assert.Equal(t, "Would last have run at Tuesday, February 27, 2024 10:15:00 AM UTC; "+
+		"would next run at Tuesday, February 27, 2024 12:15:00 PM UTC.", res.Message)
+
+	res = checkCronLocally("project", &devops.CronData{Cron: "TZ=UTC\nH H * * *"}, now)
+	assert.Equal(t, "ok", res.Result)
+	assert.Equal(t, defaultCronCount, len(res.NextTimes))
+	// the count is capped
+	res = checkCronLocally("project", &devops.CronData{Cron: "TZ=UTC\nH H * * *", Count: 1 << 30}, now)
+	assert.Equal(t, maxCronCount, len(res.NextTimes))
+	res = checkCronLocally("project", &devops.CronData{Cron: "TZ=UTC\nH H * * *"}, now)
+	// H is hashed by the full name of the pipeline
+	other := checkCronLocally("project", &devops.CronData{Cron: "TZ=UTC\nH H * * *", PipelineName: "pipeline"}, now)
+	assert.NotEqual(t, res.NextTimes, other.NextTimes)
+
+	res = checkCronLocally("project", &devops.CronData{Cron: "* * * * *"}, now)
+	assert.Equal(t, "warning", res.Result)
+	assert.Contains(t, res.Message, "every minute")
+	assert.NotEmpty(t, res.NextTime)
+
+	res = checkCronLocally("project", &devops.CronData{Cron: "0 0 31 2 *"}, now)
+	assert.Equal(t, "warning", res.Result)
+	assert.Equal(t, "No schedules so will never run", res.Message)
+
+	res = checkCronLocally("project", &devops.CronData{Cron: "0 0 * *"}, now)
+	assert.Equal(t, "error", res.Result)
+	assert.NotEmpty(t, res.Message)
+}
+
 func printTestMessage(index int, message string) string {
 	return fmt.Sprintf("index: %d, message: %s", index, message)
 }
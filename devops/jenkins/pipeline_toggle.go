@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/opswave/go-jenkins/devops"
+)
+
+// disabledReasonPrefix starts the line of the description which records why a pipeline is disabled
+const disabledReasonPrefix = "[disabled] "
+
+// EnableProjectPipeline enables a pipeline or a multi-branch pipeline
+func (j *Jenkins) EnableProjectPipeline(projectId, pipelineId string) error {
+	_, err := j.togglePipeline(projectId, pipelineId, false, "")
+	return err
+}
+
+// DisableProjectPipeline disables a pipeline or a multi-branch pipeline
+func (j *Jenkins) DisableProjectPipeline(projectId, pipelineId, reason string) error {
+	_, err := j.togglePipeline(projectId, pipelineId, true, reason)
+	return err
+}
+
+// ToggleProjectPipelines disables or enables the pipelines and multi-branch pipelines of a project one by one,
+// it stops at the first failure and returns the results of the toggled ones with the error. The organization
+// folders are not toggled
+func (j *Jenkins) ToggleProjectPipelines(projectId string, disabled bool, reason string) ([]*devops.PipelineToggleResult, error) {
+	// GetFolder does not keep the status code, so a missing project is checked at first
+	if _, err := j.GetJob(projectId); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	folder, err := j.GetFolder(projectId)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+
+	results := make([]*devops.PipelineToggleResult, 0)
+	for _, job := range folder.Raw.Jobs {
+		if !isToggleablePipelineClass(job.Class) {
+			continue
+		}
+		changed, err := j.togglePipeline(projectId, job.Name, disabled, reason)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, &devops.PipelineToggleResult{
+			Pipeline: job.Name,
+			Disabled: disabled,
+			Changed:  changed,
+		})
+	}
+	return results, nil
+}
+
+// isToggleablePipelineClass returns true if the pipelines of the class could be disabled
+func isToggleablePipelineClass(class string) bool {
+	return class == WorkflowJobClass || class == WorkflowMultiBranchProjectClass
+}
+
+// togglePipeline saves the disabled flag and the reason in the config of a pipeline,
+// it returns false if the config is not changed
+func (j *Jenkins) togglePipeline(projectId, pipelineId string, disabled bool, reason string) (bool, error) {
+	job, err := j.GetJob(pipelineId, projectId)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return false, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	if !isToggleablePipelineClass(job.Raw.Class) {
+		err = fmt.Errorf("unsupported job class %s", job.Raw.Class)
+		klog.Errorf("%+v", err)
+		return false, restful.NewError(http.StatusBadRequest, err.Error())
+	}
+	config, err := job.GetConfig()
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return false, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+
+	if !disabled {
+		reason = ""
+	}
+	toggled, changed, err := togglePipelineConfigXml(config, disabled, reason)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return false, restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	if !changed {
+		return false, nil
+	}
+	if err = job.UpdateConfig(toggled); err != nil {
+		klog.Errorf("%+v", err)
+		return false, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return true, nil
+}
+
+// togglePipelineConfigXml sets the disabled flag of a pipeline or multi-branch pipeline config,
+// the reason replaces the previous one in the description
+func togglePipelineConfigXml(config string, disabled bool, reason string) (string, bool, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(replaceXmlVersion(config, "1.1", "1.0")); err != nil {
+		return "", false, err
+	}
+	root := doc.Root()
+	if root == nil {
+		return "", false, fmt.Errorf("can not find the root of the config")
+	}
+
+	description := getElementTextValueOrEmpty(root, "description")
+	toggledDescription := withDisabledReason(description, reason)
+	if (getElementTextValueOrEmpty(root, DisabledTag) == "true") == disabled && toggledDescription == description {
+		return config, false, nil
+	}
+	addOrUpdateElement(root, DisabledTag, strconv.FormatBool(disabled))
+	if toggledDescription != description {
+		addOrUpdateElement(root, "description", "").SetText(toggledDescription)
+	}
+
+	doc.Indent(2)
+	stringXml, err := doc.WriteToString()
+	if err != nil {
+		return "", false, err
+	}
+	return replaceXmlVersion(stringXml, "1.0", "1.1"), true, nil
+}
+
+// withDisabledReason returns the description whose reason of disabling is replaced,
+// the reason is removed if the new one is empty
+func withDisabledReason(description, reason string) string {
+	var lines []string
+	for _, line := range strings.Split(description, "\n") {
+		if !strings.HasPrefix(line, disabledReasonPrefix) {
+			lines = append(lines, line)
+		}
+	}
+	if removed := strings.Join(lines, "\n"); removed != description {
+		// the blank lines before the reason are removed too
+		description = strings.TrimRight(removed, " \n")
+	}
+
+	// keep the reason in one line, so it could be found and removed
+	reason = strings.Join(strings.Fields(reason), " ")
+	if reason == "" {
+		return description
+	}
+	if description == "" {
+		return disabledReasonPrefix + reason
+	}
+	return description + "\n\n" + disabledReasonPrefix + reason
+}
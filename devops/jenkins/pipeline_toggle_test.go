@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+func TestToggleProjectPipelines(t *testing.T) {
+	projects := newFakeRelocateProjects(t)
+	projects["fake-project"]["tools"] = `<com.cloudbees.hudson.plugins.folder.Folder plugin="cloudbees-folder"/>`
+	jenkins, operations := newFakeProjectsJenkins(t, projects, nil)
+	original := map[string]string{}
+	for name, config := range projects["fake-project"] {
+		original[name] = config
+	}
+	sortResults := func(results []*devops.PipelineToggleResult) map[string]*devops.PipelineToggleResult {
+		sorted := map[string]*devops.PipelineToggleResult{}
+		for _, result := range results {
+			sorted[result.Pipeline] = result
+		}
+		return sorted
+	}
+
+	results, err := jenkins.ToggleProjectPipelines("fake-project", true, "release\nfreeze")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]*devops.PipelineToggleResult{
+		"deploy": {Pipeline: "deploy", Disabled: true, Changed: true},
+		"build":  {Pipeline: "build", Disabled: true, Changed: true},
+	}, sortResults(results))
+	assert.Len(t, *operations, 2)
+
+	deploy, err := parsePipelineConfigXml(projects["fake-project"]["deploy"])
+	assert.Nil(t, err)
+	assert.True(t, deploy.Disabled)
+	assert.Equal(t, "[disabled] release freeze", deploy.Description)
+	build, err := parseMultiBranchPipelineConfigXml(projects["fake-project"]["build"])
+	assert.Nil(t, err)
+	assert.True(t, build.Disabled)
+	assert.Equal(t, "[disabled] release freeze", build.Description)
+
+	// nothing is saved if the pipelines are already disabled with the same reason
+	results, err = jenkins.ToggleProjectPipelines("fake-project", true, "release freeze")
+	assert.Nil(t, err)
+	assert.False(t, sortResults(results)["deploy"].Changed)
+	assert.False(t, sortResults(results)["build"].Changed)
+	assert.Len(t, *operations, 2)
+
+	results, err = jenkins.ToggleProjectPipelines("fake-project", false, "ignored")
+	assert.Nil(t, err)
+	assert.True(t, sortResults(results)["deploy"].Changed)
+	assert.Len(t, *operations, 4)
+	deploy, err = parsePipelineConfigXml(projects["fake-project"]["deploy"])
+	assert.Nil(t, err)
+	assert.False(t, deploy.Disabled)
+	assert.Empty(t, deploy.Description)
+	assert.Equal(t, original["tools"], projects["fake-project"]["tools"])
+
+	_, err = jenkins.ToggleProjectPipelines("missing-project", true, "")
+	assert.Equal(t, http.StatusNotFound, err.(restful.ServiceError).Code)
+}
+
+func TestToggleProjectPipelinesPartially(t *testing.T) {
+	deploy, err := createPipelineConfigXml(&devopsv1alpha3.NoScmPipeline{Jenkinsfile: "node{echo 'hello'}"})
+	assert.Nil(t, err)
+	jenkins, requests := newFakeJenkinsWithResponses(t, http.StatusOK, map[string]string{
+		"/job/fake-project/api/json": `{"_class":"` + FolderClass + `","name":"fake-project","jobs":[` +
+			`{"_class":"` + WorkflowJobClass + `","name":"deploy"},{"_class":"` + WorkflowJobClass + `","name":"broken"}]}`,
+		"/job/fake-project/job/deploy/api/json":    `{"_class":"` + WorkflowJobClass + `","name":"deploy"}`,
+		"/job/fake-project/job/deploy/config.xml/": deploy,
+		"/job/fake-project/job/broken/api/json":    `{"_class":"` + WorkflowJobClass + `","name":"broken"}`,
+	})
+
+	// the config of the broken one is empty, the toggled ones are still returned
+	results, err := jenkins.ToggleProjectPipelines("fake-project", true, "")
+	assert.NotNil(t, err)
+	assert.Equal(t, []*devops.PipelineToggleResult{{Pipeline: "deploy", Disabled: true, Changed: true}}, results)
+	var updated []string
+	for _, request := range *requests {
+		if request.method == http.MethodPost {
+			updated = append(updated, request.path)
+		}
+	}
+	assert.Equal(t, []string{"/job/fake-project/job/deploy/config.xml"}, updated)
+}
+
+func TestEnableAndDisableProjectPipeline(t *testing.T) {
+	projects := newFakeRelocateProjects(t)
+	projects["fake-project"]["tools"] = `<com.cloudbees.hudson.plugins.folder.Folder plugin="cloudbees-folder"/>`
+	jenkins, operations := newFakeProjectsJenkins(t, projects, nil)
+
+	assert.Nil(t, jenkins.DisableProjectPipeline("fake-project", "build", ""))
+	build, err := parseMultiBranchPipelineConfigXml(projects["fake-project"]["build"])
+	assert.Nil(t, err)
+	assert.True(t, build.Disabled)
+	assert.Empty(t, build.Description)
+
+	assert.Nil(t, jenkins.EnableProjectPipeline("fake-project", "build"))
+	build, err = parseMultiBranchPipelineConfigXml(projects["fake-project"]["build"])
+	assert.Nil(t, err)
+	assert.False(t, build.Disabled)
+	assert.Equal(t, []string{"update fake-project/build", "update fake-project/build"}, *operations)
+
+	err = jenkins.DisableProjectPipeline("fake-project", "tools", "")
+	assert.Equal(t, http.StatusBadRequest, err.(restful.ServiceError).Code)
+	err = jenkins.EnableProjectPipeline("fake-project", "missing")
+	assert.Equal(t, http.StatusNotFound, err.(restful.ServiceError).Code)
+}
+
+func Test_withDisabledReason(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		reason      string
+		expected    string
+	}{
+		{name: "empty description", description: "", reason: "freeze", expected: "[disabled] freeze"},
+		{name: "append", description: "deploy to prod", reason: "freeze", expected: "deploy to prod\n\n[disabled] freeze"},
+		{name: "replace", description: "deploy to prod\n\n[disabled] freeze", reason: "incident", expected: "deploy to prod\n\n[disabled] incident"},
+		{name: "remove", description: "deploy to prod\n\n[disabled] freeze", reason: "", expected: "deploy to prod"},
+		{name: "keep", description: "deploy to prod\n", reason: "", expected: "deploy to prod\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, withDisabledReason(tt.description, tt.reason))
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beevik/etree"
+
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+// the containers whose unrecognized children are carried over, an empty path stands for the root element
+var (
+	pipelineUnknownContainers = []string{
+		"",
+		PropertiesTag,
+		PropertiesTag + "/" + PipelineTriggersJobTag + "/" + TriggersTag,
+	}
+	multiBranchPipelineUnknownContainers = []string{
+		"",
+		PropertiesTag,
+		TriggersTag,
+		"sources/data/jenkins.branch.BranchSource/source/traits",
+	}
+)
+
+// findUnknownElements compares the original config with the one regenerated from the parsed pipeline,
+// the children of the containers which cannot be regenerated are unknown. The parsers skip it if the config cannot
+// be regenerated at all, such as the one with an unsupported scm or source, so no unknown element is reported and
+// the parts which are not supported are kept as they are by the updates which edit the config in place
+func findUnknownElements(original, regenerated *etree.Element, containers []string) ([]devopsv1alpha3.UnknownElement, error) {
+	var unknowns []devopsv1alpha3.UnknownElement
+	for _, path := range containers {
+		originalContainer := selectContainer(original, path)
+		if originalContainer == nil {
+			continue
+		}
+		knownTags := map[string]bool{}
+		if regeneratedContainer := selectContainer(regenerated, path); regeneratedContainer != nil {
+			for _, child := range regeneratedContainer.ChildElements() {
+				knownTags[child.Tag] = true
+			}
+		}
+		// the nested containers are checked by themselves
+		for _, nested := range containers {
+			if tag, ok := nestedContainerTag(path, nested); ok {
+				knownTags[tag] = true
+			}
+		}
+
+		for _, child := range originalContainer.ChildElements() {
+			if knownTags[child.Tag] {
+				continue
+			}
+			doc := etree.NewDocument()
+			doc.SetRoot(child.Copy())
+			doc.Indent(etree.NoIndent)
+			xml, err := doc.WriteToString()
+			if err != nil {
+				return nil, err
+			}
+			unknowns = append(unknowns, devopsv1alpha3.UnknownElement{
+				Path: path,
+				Tag:  child.Tag,
+				XML:  xml,
+			})
+		}
+	}
+	return unknowns, nil
+}
+
+// appendUnknownElements writes the unknown elements back, the ones whose tag already exists in the container are skipped
+func appendUnknownElements(root *etree.Element, unknowns []devopsv1alpha3.UnknownElement) error {
+	for _, unknown := range unknowns {
+		doc := etree.NewDocument()
+		if err := doc.ReadFromString(unknown.XML); err != nil {
+			return fmt.Errorf("invalid unknown element [%s]: %v", unknown.Tag, err)
+		}
+		element := doc.Root()
+		if element == nil || element.Tag != unknown.Tag {
+			return fmt.Errorf("the root of unknown element [%s] does not match its tag", unknown.Tag)
+		}
+
+		container := root
+		if unknown.Path != "" {
+			for _, tag := range strings.Split(unknown.Path, "/") {
+				container = addOrUpdateElement(container, tag, StringNull)
+			}
+		}
+		if container.SelectElement(unknown.Tag) != nil {
+			continue
+		}
+		container.AddChild(element)
+	}
+	return nil
+}
+
+func selectContainer(root *etree.Element, path string) *etree.Element {
+	if path == "" {
+		return root
+	}
+	return root.FindElement(path)
+}
+
+// nestedContainerTag returns the tag of the child of the container which leads to the nested one
+func nestedContainerTag(container, nested string) (string, bool) {
+	if nested == container {
+		return "", false
+	}
+	prefix := ""
+	if container != "" {
+		prefix = container + "/"
+	}
+	if !strings.HasPrefix(nested, prefix) {
+		return "", false
+	}
+	return strings.Split(strings.TrimPrefix(nested, prefix), "/")[0], true
+}
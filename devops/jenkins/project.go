@@ -32,6 +32,7 @@ func (j *Jenkins) CreateDevOpsProject(projectId string) (string, error) {
 	return projectId, nil
 }
 
+// DeleteDevOpsProject deletes the folder of a project without any check, see also DeleteDevOpsProjectWithOptions
 func (j *Jenkins) DeleteDevOpsProject(projectId string) (err error) {
 	_, err = j.DeleteJob(projectId)
 	if err != nil {
@@ -49,3 +50,40 @@ func (j *Jenkins) GetDevOpsProject(projectId string) (string, error) {
 	}
 	return job.GetName(), nil
 }
+
+// ListDevOpsProjects lists the folders in the root of Jenkins, the pipelines are counted by one request with the tree
+func (j *Jenkins) ListDevOpsProjects() ([]*devops.DevOpsProject, error) {
+	var root struct {
+		Jobs []struct {
+			Class       string     `json:"_class"`
+			Name        string     `json:"name"`
+			DisplayName string     `json:"displayName"`
+			Description string     `json:"description"`
+			Jobs        []InnerJob `json:"jobs"`
+		} `json:"jobs"`
+	}
+	query := map[string]string{"tree": "jobs[_class,name,displayName,description,jobs[_class,name]]"}
+	if _, err := j.Requester.GetJSON("/", &root, query); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+
+	projects := []*devops.DevOpsProject{}
+	for _, folder := range root.Jobs {
+		if folder.Class != FolderClass {
+			continue
+		}
+		project := &devops.DevOpsProject{
+			Name:        folder.Name,
+			DisplayName: folder.DisplayName,
+			Description: folder.Description,
+		}
+		for _, job := range folder.Jobs {
+			if isPipelineJobClass(job.Class) {
+				project.PipelineCount++
+			}
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
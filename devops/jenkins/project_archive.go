@@ -0,0 +1,411 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/emicklei/go-restful"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/opswave/go-jenkins/devops"
+)
+
+// the layout of a project archive
+const (
+	projectArchiveManifest     = "manifest.json"
+	projectArchiveFolderConfig = "config.xml"
+	projectArchiveSecrets      = "secrets.enc"
+	projectArchivePipelineDir  = "pipelines/"
+	projectArchiveBuildDir     = "builds/"
+)
+
+// folderCredentialsPropertyTag holds the credentials of a folder, the secrets in it are encrypted by the Jenkins instance
+const folderCredentialsPropertyTag = "com.cloudbees.hudson.plugins.folder.properties.FolderCredentialsProvider_-FolderCredentialsProperty"
+
+type projectArchiveFile struct {
+	name string
+	data []byte
+}
+
+// ExportProject writes the folder config, the pipeline configs and the credential metadata of a project into a
+// tar.gz archive, the secrets are written only if they are encrypted by the key in options
+func (j *Jenkins) ExportProject(projectId string, writer io.Writer, options *devops.ProjectExportOptions) (*devops.ProjectArchiveManifest, error) {
+	if options == nil {
+		options = &devops.ProjectExportOptions{}
+	}
+	manifest := &devops.ProjectArchiveManifest{
+		Version:       devops.ProjectArchiveVersion,
+		ProjectId:     projectId,
+		IncludeBuilds: options.IncludeBuilds,
+	}
+
+	project, err := j.GetJob(projectId)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	folderConfig, err := project.GetConfig()
+	if err != nil {
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	if folderConfig, err = removeFolderCredentials(folderConfig); err != nil {
+		return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	files := []projectArchiveFile{{name: projectArchiveFolderConfig, data: []byte(folderConfig)}}
+
+	folder, err := j.GetFolder(projectId)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	for _, item := range folder.Raw.Jobs {
+		if !isPipelineJobClass(item.Class) {
+			continue
+		}
+		job, err := j.GetJob(item.Name, projectId)
+		if err != nil {
+			return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+		config, err := job.GetConfig()
+		if err != nil {
+			return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+		manifest.Pipelines = append(manifest.Pipelines, devops.ProjectArchivePipeline{Name: item.Name, Class: item.Class})
+		files = append(files, projectArchiveFile{name: projectArchivePipelineDir + item.Name + ".xml", data: []byte(config)})
+
+		if options.IncludeBuilds {
+			builds, err := job.GetAllBuildStatus()
+			if err != nil {
+				return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+			}
+			data, err := json.Marshal(builds)
+			if err != nil {
+				return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+			}
+			files = append(files, projectArchiveFile{name: projectArchiveBuildDir + item.Name + ".json", data: data})
+		}
+	}
+
+	domains, err := j.ListCredentialDomainsInProject(projectId)
+	if err != nil {
+		return nil, err
+	}
+	for _, domain := range domains {
+		if domain.Name != GlobalCredentialDomain {
+			manifest.Domains = append(manifest.Domains, domain)
+		}
+	}
+	credentials, err := j.ListCredentialsInProject(projectId)
+	if err != nil {
+		return nil, err
+	}
+	for _, credential := range credentials {
+		metadata := *credential
+		metadata.Fingerprint = nil
+		manifest.Credentials = append(manifest.Credentials, &metadata)
+	}
+
+	if len(options.EncryptionKey) > 0 && len(options.Secrets) > 0 {
+		data, err := json.Marshal(exportedSecrets(options.Secrets))
+		if err != nil {
+			return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+		}
+		if data, err = encryptArchiveData(options.EncryptionKey, data); err != nil {
+			return nil, restful.NewError(http.StatusBadRequest, err.Error())
+		}
+		files = append(files, projectArchiveFile{name: projectArchiveSecrets, data: data})
+		manifest.EncryptedSecrets = true
+	}
+
+	// the manifest comes first, so that the archive can be recognized without reading it all
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	files = append([]projectArchiveFile{{name: projectArchiveManifest, data: data}}, files...)
+	if err = writeProjectArchive(writer, files); err != nil {
+		return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	return manifest, nil
+}
+
+// ImportProject creates a project from an archive written by ExportProject, the references to the project ID of the
+// archive in the configs are replaced by projectId. The credentials are restored only if the key in options
+// decrypts their secrets
+func (j *Jenkins) ImportProject(projectId string, reader io.Reader, options *devops.ProjectImportOptions) (*devops.ProjectImportResult, error) {
+	if options == nil {
+		options = &devops.ProjectImportOptions{}
+	}
+	files, err := readProjectArchive(reader)
+	if err != nil {
+		return nil, restful.NewError(http.StatusBadRequest, err.Error())
+	}
+	manifest := &devops.ProjectArchiveManifest{}
+	if data, ok := files[projectArchiveManifest]; !ok {
+		return nil, restful.NewError(http.StatusBadRequest, "the manifest is missing in the archive")
+	} else if err = json.Unmarshal(data, manifest); err != nil {
+		return nil, restful.NewError(http.StatusBadRequest, err.Error())
+	}
+	if manifest.Version != devops.ProjectArchiveVersion {
+		return nil, restful.NewError(http.StatusBadRequest, fmt.Sprintf("unsupported archive version '%s'", manifest.Version))
+	}
+
+	// the secrets are decrypted before anything is created, a wrong key must not leave a partial project
+	secrets := map[string]*v1.Secret{}
+	if manifest.EncryptedSecrets && len(options.EncryptionKey) > 0 {
+		data, err := decryptArchiveData(options.EncryptionKey, files[projectArchiveSecrets])
+		if err != nil {
+			return nil, restful.NewError(http.StatusBadRequest, fmt.Sprintf("cannot decrypt the secrets: %v", err))
+		}
+		var exported []*v1.Secret
+		if err = json.Unmarshal(data, &exported); err != nil {
+			return nil, restful.NewError(http.StatusBadRequest, err.Error())
+		}
+		for _, secret := range exported {
+			secret.Namespace = projectId
+			secrets[secret.Name] = secret
+		}
+	}
+
+	folderConfig, err := remapProjectId(string(files[projectArchiveFolderConfig]), manifest.ProjectId, projectId)
+	if err != nil {
+		return nil, restful.NewError(http.StatusBadRequest, err.Error())
+	}
+	if _, err = j.CreateJobInFolder(folderConfig, projectId); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+
+	// the project is removed if anything in it cannot be created, so the import could be retried with the same ID
+	result, err := j.importProjectItems(projectId, manifest, files, secrets)
+	if err != nil {
+		if deleteErr := j.DeleteDevOpsProject(projectId); deleteErr != nil {
+			klog.Errorf("failed to remove the partially imported project %s: %+v", projectId, deleteErr)
+			return nil, restful.NewError(devops.GetDevOpsStatusCode(err),
+				fmt.Sprintf("%v, and the partially imported project %s cannot be removed: %v", err, projectId, deleteErr))
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// importProjectItems creates the credential domains, credentials and pipelines of an archive in the project
+func (j *Jenkins) importProjectItems(projectId string, manifest *devops.ProjectArchiveManifest, files map[string][]byte,
+	secrets map[string]*v1.Secret) (*devops.ProjectImportResult, error) {
+	result := &devops.ProjectImportResult{ProjectId: projectId}
+	for _, domain := range manifest.Domains {
+		if _, err := j.CreateCredentialDomainInProject(projectId, domain); err != nil {
+			return nil, err
+		}
+	}
+	for _, credential := range manifest.Credentials {
+		secret, ok := secrets[credential.Id]
+		if !ok {
+			result.SkippedCredentials = append(result.SkippedCredentials, credential.Id)
+			continue
+		}
+		if _, err := j.CreateCredentialInProject(projectId, secret); err != nil {
+			return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+		result.Credentials = append(result.Credentials, credential.Id)
+	}
+
+	for _, pipeline := range manifest.Pipelines {
+		data, ok := files[projectArchivePipelineDir+pipeline.Name+".xml"]
+		if !ok {
+			return nil, restful.NewError(http.StatusBadRequest, fmt.Sprintf("the config of pipeline %s is missing in the archive", pipeline.Name))
+		}
+		config, err := remapProjectId(string(data), manifest.ProjectId, projectId)
+		if err != nil {
+			return nil, restful.NewError(http.StatusBadRequest, err.Error())
+		}
+		if _, err = j.CreateJobInFolder(config, pipeline.Name, projectId); err != nil {
+			klog.Errorf("%+v", err)
+			return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+		result.Pipelines = append(result.Pipelines, pipeline.Name)
+	}
+	return result, nil
+}
+
+// exportedSecrets keeps the fields of the secrets which are needed to create the credentials
+func exportedSecrets(secrets []*v1.Secret) []*v1.Secret {
+	exported := make([]*v1.Secret, 0, len(secrets))
+	for _, secret := range secrets {
+		exported = append(exported, &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        secret.Name,
+				Labels:      secret.Labels,
+				Annotations: secret.Annotations,
+			},
+			Type: secret.Type,
+			Data: secret.Data,
+		})
+	}
+	return exported
+}
+
+// removeFolderCredentials removes the credentials from the config of a folder, they cannot be decrypted by
+// another Jenkins instance
+func removeFolderCredentials(config string) (string, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(replaceXmlVersion(config, "1.1", "1.0")); err != nil {
+		return "", err
+	}
+	if properties := doc.Root().SelectElement(PropertiesTag); properties != nil {
+		removeChildElement(properties, folderCredentialsPropertyTag)
+	}
+	stringXml, err := doc.WriteToString()
+	if err != nil {
+		return "", err
+	}
+	return replaceXmlVersion(stringXml, "1.0", "1.1"), nil
+}
+
+// projectReferencePaths are the elements whose texts are comma separated job names
+var projectReferencePaths = []string{
+	"//upstreamProjects",
+	"//createActionJobsToTrigger",
+	"//deleteActionJobsToTrigger",
+	"//" + RunParameterClass + "/projectName",
+}
+
+// remapProjectId replaces the job paths in the project, such as "project/pipeline", with the ones in the new project.
+// Only the elements referencing jobs are changed, see also projectReferencePaths
+func remapProjectId(config, oldProjectId, newProjectId string) (string, error) {
+	if oldProjectId == newProjectId {
+		return config, nil
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(replaceXmlVersion(config, "1.1", "1.0")); err != nil {
+		return "", err
+	}
+	for _, path := range projectReferencePaths {
+		for _, element := range doc.FindElements(path) {
+			names := strings.Split(element.Text(), ",")
+			changed := false
+			for i, name := range names {
+				trimmed := strings.TrimPrefix(strings.TrimSpace(name), "/")
+				if trimmed == oldProjectId || strings.HasPrefix(trimmed, oldProjectId+"/") {
+					names[i] = strings.Replace(name, oldProjectId, newProjectId, 1)
+					changed = true
+				}
+			}
+			if changed {
+				element.SetText(strings.Join(names, ","))
+			}
+		}
+	}
+	stringXml, err := doc.WriteToString()
+	if err != nil {
+		return "", err
+	}
+	return replaceXmlVersion(stringXml, "1.0", "1.1"), nil
+}
+
+func writeProjectArchive(writer io.Writer, files []projectArchiveFile) error {
+	gzipWriter := gzip.NewWriter(writer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, file := range files {
+		if err := tarWriter.WriteHeader(&tar.Header{
+			Name: file.name,
+			Mode: 0600,
+			Size: int64(len(file.data)),
+		}); err != nil {
+			return err
+		}
+		if _, err := tarWriter.Write(file.data); err != nil {
+			return err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+func readProjectArchive(reader io.Reader) (map[string][]byte, error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+
+	files := map[string][]byte{}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var buffer bytes.Buffer
+		if _, err = io.Copy(&buffer, tarReader); err != nil {
+			return nil, err
+		}
+		files[header.Name] = buffer.Bytes()
+	}
+}
+
+// encryptArchiveData encrypts the data by AES-GCM, the nonce is prepended to the result
+func encryptArchiveData(key, data []byte) ([]byte, error) {
+	gcm, err := newArchiveCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+func decryptArchiveData(key, data []byte) ([]byte, error) {
+	gcm, err := newArchiveCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("the encrypted data is too short")
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
+
+func newArchiveCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+const fakeFolderConfig = `<?xml version='1.1' encoding='UTF-8'?>
+<com.cloudbees.hudson.plugins.folder.Folder plugin="cloudbees-folder">
+  <description>fake project</description>
+  <properties>
+    <com.cloudbees.hudson.plugins.folder.properties.FolderCredentialsProvider_-FolderCredentialsProperty>
+      <domainCredentialsMap class="hudson.util.CopyOnWriteMap$Hash"/>
+    </com.cloudbees.hudson.plugins.folder.properties.FolderCredentialsProvider_-FolderCredentialsProperty>
+  </properties>
+</com.cloudbees.hudson.plugins.folder.Folder>`
+
+func exportFakeProject(t *testing.T, options *devops.ProjectExportOptions) []byte {
+	deployConfig, err := createPipelineConfigXml(&devopsv1alpha3.NoScmPipeline{Jenkinsfile: "build job: 'old-project/build'"})
+	assert.Nil(t, err)
+	deployConfig = strings.Replace(deployConfig, "<disabled>", "<upstreamProjects>old-project/build, other/build</upstreamProjects>\n  <disabled>", 1)
+	domainConfig, err := createCredentialDomainConfigXml(&devops.CredentialDomain{Name: "github", Description: "github.com"})
+	assert.Nil(t, err)
+
+	jenkins, _ := newFakeJenkinsWithResponses(t, http.StatusOK, map[string]string{
+		"/job/old-project/api/json": `{"_class":"com.cloudbees.hudson.plugins.folder.Folder","name":"old-project","jobs":[
+			{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"deploy"},
+			{"_class":"hudson.model.FreeStyleProject","name":"legacy"}]}`,
+		"/job/old-project/config.xml/": fakeFolderConfig,
+		"/job/old-project/job/deploy/api/json": `{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"deploy",
+			"allBuilds":[{"number":2,"building":true},{"number":1,"result":"SUCCESS"}]}`,
+		"/job/old-project/job/deploy/config.xml/":                             deployConfig,
+		"/job/old-project/credentials/store/folder/api/json":                  `{"domains":{"_":{},"github":{}}}`,
+		"/job/old-project/credentials/store/folder/domain/github/config.xml/": domainConfig,
+		"/job/old-project/credentials/store/folder/domain/_/api/json":         `{"credentials":[{"id":"kubeconfig","fingerprint":{"hash":"abc"}},{"id":"docker"}]}`,
+		"/job/old-project/credentials/store/folder/domain/github/api/json":    `{"credentials":[]}`,
+	})
+
+	buffer := &bytes.Buffer{}
+	manifest, err := jenkins.ExportProject("old-project", buffer, options)
+	assert.Nil(t, err)
+	assert.Equal(t, "old-project", manifest.ProjectId)
+	assert.Equal(t, []devops.ProjectArchivePipeline{{Name: "deploy", Class: WorkflowJobClass}}, manifest.Pipelines)
+	assert.Equal(t, []*devops.CredentialDomain{{Name: "github", Description: "github.com"}}, manifest.Domains)
+	if assert.Len(t, manifest.Credentials, 2) {
+		assert.Equal(t, "kubeconfig", manifest.Credentials[0].Id)
+		assert.Nil(t, manifest.Credentials[0].Fingerprint)
+	}
+	return buffer.Bytes()
+}
+
+func TestExportProject(t *testing.T) {
+	secret := newFakeSecret(devopsv1alpha3.SecretTypeKubeConfig, map[string]string{devopsv1alpha3.KubeConfigSecretKey: "fake-kubeconfig"})
+	secret.Name = "kubeconfig"
+	key := []byte("0123456789abcdef")
+	archive := exportFakeProject(t, &devops.ProjectExportOptions{
+		EncryptionKey: key,
+		Secrets:       []*v1.Secret{secret},
+		IncludeBuilds: true,
+	})
+
+	files, err := readProjectArchive(bytes.NewReader(archive))
+	assert.Nil(t, err)
+	assert.NotContains(t, string(files[projectArchiveFolderConfig]), folderCredentialsPropertyTag)
+	assert.Contains(t, string(files[projectArchiveFolderConfig]), "fake project")
+	assert.Contains(t, string(files[projectArchivePipelineDir+"deploy.xml"]), "old-project/build")
+	assert.JSONEq(t, `[{"Number":2,"Building":true,"Result":""},{"Number":1,"Building":false,"Result":"SUCCESS"}]`,
+		string(files[projectArchiveBuildDir+"deploy.json"]))
+	assert.NotContains(t, string(files[projectArchiveSecrets]), "fake-kubeconfig")
+
+	t.Run("import", func(t *testing.T) {
+		jenkins, requests := newFakeJenkins(t, http.StatusOK)
+		result, err := jenkins.ImportProject("new-project", bytes.NewReader(archive), &devops.ProjectImportOptions{EncryptionKey: key})
+		assert.Nil(t, err)
+		assert.Equal(t, &devops.ProjectImportResult{
+			ProjectId:          "new-project",
+			Pipelines:          []string{"deploy"},
+			Credentials:        []string{"kubeconfig"},
+			SkippedCredentials: []string{"docker"},
+		}, result)
+
+		var posts []recordedRequest
+		for _, request := range *requests {
+			if request.method == http.MethodPost {
+				posts = append(posts, request)
+			}
+		}
+		if assert.Len(t, posts, 4) {
+			assert.Equal(t, "/createItem", posts[0].path)
+			assert.Equal(t, "name=new-project", posts[0].query)
+			assert.Contains(t, posts[0].body, "fake project")
+			assert.Equal(t, "/job/new-project/credentials/store/folder/createDomain", posts[1].path)
+			assert.Equal(t, "/job/new-project/credentials/store/folder/domain/_/createCredentials", posts[2].path)
+			assert.Contains(t, posts[2].form, "fake-kubeconfig")
+			assert.Equal(t, "/job/new-project/createItem", posts[3].path)
+			assert.Equal(t, "name=deploy", posts[3].query)
+			assert.Contains(t, posts[3].body, "<upstreamProjects>new-project/build, other/build</upstreamProjects>")
+			assert.Contains(t, posts[3].body, "build job: &apos;old-project/build&apos;")
+		}
+	})
+
+	t.Run("import fails", func(t *testing.T) {
+		var deleted bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case strings.HasPrefix(r.URL.Path, "/crumbIssuer/"):
+				w.WriteHeader(http.StatusNotFound)
+			case r.Method == http.MethodPost && r.URL.Path == "/job/new-project/createItem":
+				w.WriteHeader(http.StatusInternalServerError)
+			case r.Method == http.MethodPost && r.URL.Path == "/job/new-project/doDelete":
+				deleted = true
+			}
+		}))
+		defer server.Close()
+		jenkins := CreateJenkins(nil, server.URL, 0, "admin", "password")
+
+		_, err := jenkins.ImportProject("new-project", bytes.NewReader(archive), &devops.ProjectImportOptions{EncryptionKey: key})
+		assert.NotNil(t, err)
+		assert.True(t, deleted, "the partially imported project should be removed")
+	})
+
+	t.Run("import without key", func(t *testing.T) {
+		jenkins, _ := newFakeJenkins(t, http.StatusOK)
+		result, err := jenkins.ImportProject("new-project", bytes.NewReader(archive), nil)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"kubeconfig", "docker"}, result.SkippedCredentials)
+	})
+
+	t.Run("import with a wrong key", func(t *testing.T) {
+		jenkins, requests := newFakeJenkins(t, http.StatusOK)
+		_, err := jenkins.ImportProject("new-project", bytes.NewReader(archive),
+			&devops.ProjectImportOptions{EncryptionKey: []byte("fedcba9876543210")})
+		if assert.NotNil(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(restful.ServiceError).Code)
+		}
+		assert.Empty(t, *requests)
+	})
+}
+
+func TestExportProject_WithoutKey(t *testing.T) {
+	secret := newFakeSecret(devopsv1alpha3.SecretTypeKubeConfig, map[string]string{devopsv1alpha3.KubeConfigSecretKey: "fake-kubeconfig"})
+	archive := exportFakeProject(t, &devops.ProjectExportOptions{Secrets: []*v1.Secret{secret}})
+
+	files, err := readProjectArchive(bytes.NewReader(archive))
+	assert.Nil(t, err)
+	assert.NotContains(t, files, projectArchiveSecrets)
+	assert.NotContains(t, files, projectArchiveBuildDir+"deploy.json")
+}
+
+func TestImportProject_InvalidArchive(t *testing.T) {
+	jenkins, requests := newFakeJenkins(t, http.StatusOK)
+	_, err := jenkins.ImportProject("new-project", strings.NewReader("not an archive"), nil)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, http.StatusBadRequest, err.(restful.ServiceError).Code)
+	}
+
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, writeProjectArchive(buffer, []projectArchiveFile{{name: projectArchiveManifest, data: []byte(`{"version":"v0"}`)}}))
+	_, err = jenkins.ImportProject("new-project", buffer, nil)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unsupported archive version")
+	}
+	assert.Empty(t, *requests)
+}
+
+func Test_remapProjectId(t *testing.T) {
+	config := `<?xml version='1.1' encoding='UTF-8'?>
+<flow-definition plugin="workflow-job">
+  <description>ci/feature is built by ci</description>
+  <properties>
+    <hudson.model.ParametersDefinitionProperty>
+      <parameterDefinitions>
+        <hudson.model.StringParameterDefinition>
+          <name>PROJECT</name>
+          <defaultValue>ci</defaultValue>
+        </hudson.model.StringParameterDefinition>
+        <hudson.model.RunParameterDefinition>
+          <name>RUN</name>
+          <projectName>ci/build</projectName>
+        </hudson.model.RunParameterDefinition>
+      </parameterDefinitions>
+    </hudson.model.ParametersDefinitionProperty>
+    <org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty>
+      <triggers>
+        <jenkins.triggers.ReverseBuildTrigger>
+          <upstreamProjects>ci/build, /ci/test,other/build</upstreamProjects>
+        </jenkins.triggers.ReverseBuildTrigger>
+      </triggers>
+    </org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty>
+  </properties>
+  <definition class="org.jenkinsci.plugins.workflow.cps.CpsScmFlowDefinition" plugin="workflow-cps">
+    <scm class="hudson.plugins.git.GitSCM" plugin="git">
+      <branches>
+        <hudson.plugins.git.BranchSpec>
+          <name>ci/feature</name>
+        </hudson.plugins.git.BranchSpec>
+      </branches>
+    </scm>
+    <scriptPath>ci/Jenkinsfile</scriptPath>
+  </definition>
+</flow-definition>`
+
+	remapped, err := remapProjectId(config, "ci", "ci-copy")
+	assert.Nil(t, err)
+	assert.Contains(t, remapped, "<upstreamProjects>ci-copy/build, /ci-copy/test,other/build</upstreamProjects>")
+	assert.Contains(t, remapped, "<projectName>ci-copy/build</projectName>")
+	// the texts which are not job references are kept
+	assert.Contains(t, remapped, "<description>ci/feature is built by ci</description>")
+	assert.Contains(t, remapped, "<defaultValue>ci</defaultValue>")
+	assert.Contains(t, remapped, "<name>ci/feature</name>")
+	assert.Contains(t, remapped, "<scriptPath>ci/Jenkinsfile</scriptPath>")
+
+	trigger, err := createMultiBranchPipelineConfigXml("ci", &devopsv1alpha3.MultiBranchPipeline{
+		SourceType: devopsv1alpha3.SourceTypeGit,
+		GitSource:  &devopsv1alpha3.GitSource{Url: "https://github.com/kubesphere/ci.git"},
+		MultiBranchJobTrigger: &devopsv1alpha3.MultiBranchJobTrigger{
+			CreateActionJobsToTrigger: "ci/deploy",
+			DeleteActionJobsToTrigger: "cleanup",
+		},
+	})
+	assert.Nil(t, err)
+	remapped, err = remapProjectId(trigger, "ci", "ci-copy")
+	assert.Nil(t, err)
+	pipeline, err := parseMultiBranchPipelineConfigXml(remapped)
+	assert.Nil(t, err)
+	assert.Equal(t, "ci-copy/deploy", pipeline.MultiBranchJobTrigger.CreateActionJobsToTrigger)
+	assert.Equal(t, "cleanup", pipeline.MultiBranchJobTrigger.DeleteActionJobsToTrigger)
+	assert.Equal(t, "https://github.com/kubesphere/ci.git", pipeline.GitSource.Url)
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/opswave/go-jenkins/devops"
+)
+
+// the fields of the build queue and the executors requested by the deletion check
+const (
+	queueItemsTree = "items[id,why,task[name,url]]"
+	executorsTree  = "computer[executors[currentExecutable[number,url]],oneOffExecutors[currentExecutable[number,url]]]"
+)
+
+type queueResponse struct {
+	Items []struct {
+		Id   int64  `json:"id"`
+		Why  string `json:"why"`
+		Task struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"task"`
+	} `json:"items"`
+}
+
+type executorResponse struct {
+	CurrentExecutable *struct {
+		Number int    `json:"number"`
+		URL    string `json:"url"`
+	} `json:"currentExecutable"`
+}
+
+type computersResponse struct {
+	Computer []struct {
+		Executors       []executorResponse `json:"executors"`
+		OneOffExecutors []executorResponse `json:"oneOffExecutors"`
+	} `json:"computer"`
+}
+
+// CheckDevOpsProjectDeletion finds the running builds and the queued items of a project by the URLs of the jobs,
+// the builds of the pipelines run on the one-off executors while their steps run on the executors of the agents
+func (j *Jenkins) CheckDevOpsProjectDeletion(projectId string) (*devops.ProjectDeletionReport, error) {
+	if _, err := j.GetJob(projectId); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	report := &devops.ProjectDeletionReport{ProjectId: projectId}
+
+	computers := &computersResponse{}
+	if _, err := j.Requester.GetJSON("/computer", computers, map[string]string{"tree": executorsTree}); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	found := map[string]bool{}
+	for _, computer := range computers.Computer {
+		for _, executor := range append(computer.OneOffExecutors, computer.Executors...) {
+			build := executor.CurrentExecutable
+			if build == nil {
+				continue
+			}
+			names, ok := projectItemOfURL(build.URL, projectId)
+			if !ok {
+				continue
+			}
+			pipeline := strings.Join(names, "/")
+			if key := fmt.Sprintf("%s#%d", pipeline, build.Number); !found[key] {
+				report.RunningBuilds = append(report.RunningBuilds, &devops.ProjectRunningBuild{
+					Pipeline: pipeline,
+					Number:   build.Number,
+					URL:      build.URL,
+				})
+				found[key] = true
+			}
+		}
+	}
+
+	queue := &queueResponse{}
+	if _, err := j.Requester.GetJSON("/queue", queue, map[string]string{"tree": queueItemsTree}); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	for _, item := range queue.Items {
+		if names, ok := projectItemOfURL(item.Task.URL, projectId); ok {
+			report.QueuedItems = append(report.QueuedItems, &devops.ProjectQueuedItem{
+				Id:       item.Id,
+				Pipeline: strings.Join(names, "/"),
+				Why:      item.Why,
+			})
+		}
+	}
+
+	credentials, err := j.ListCredentialsInProject(projectId)
+	if err != nil {
+		return nil, err
+	}
+	for _, credential := range credentials {
+		report.Credentials = append(report.Credentials, credential.Id)
+	}
+	return report, nil
+}
+
+// DeleteDevOpsProjectWithOptions deletes a project after it is checked and archived, the report of the check is
+// returned even if the project is not deleted
+func (j *Jenkins) DeleteDevOpsProjectWithOptions(projectId string, options *devops.ProjectDeleteOptions) (*devops.ProjectDeletionReport, error) {
+	if options == nil {
+		options = &devops.ProjectDeleteOptions{}
+	}
+	mode := options.Mode
+	if mode == "" {
+		mode = devops.ProjectDeleteModeRefuse
+	}
+
+	report := &devops.ProjectDeletionReport{ProjectId: projectId}
+	switch mode {
+	case devops.ProjectDeleteModeRefuse, devops.ProjectDeleteModeAbort:
+		var err error
+		if report, err = j.CheckDevOpsProjectDeletion(projectId); err != nil {
+			return nil, err
+		}
+	case devops.ProjectDeleteModeForce:
+	default:
+		return nil, restful.NewError(http.StatusBadRequest, fmt.Sprintf("unsupported delete mode '%s'", mode))
+	}
+	if mode == devops.ProjectDeleteModeRefuse && report.Blocked() {
+		err := fmt.Errorf("project %s has %d running builds and %d queued items",
+			projectId, len(report.RunningBuilds), len(report.QueuedItems))
+		return report, restful.NewError(http.StatusConflict, err.Error())
+	}
+
+	if options.Archive != nil {
+		if _, err := j.ExportProject(projectId, options.Archive, options.ArchiveOptions); err != nil {
+			klog.Errorf("cannot archive project %s before deleting it: %v", projectId, err)
+			return report, err
+		}
+	}
+
+	if mode == devops.ProjectDeleteModeAbort {
+		// the queued items are cancelled first, so that they cannot start after the builds are aborted
+		for _, item := range report.QueuedItems {
+			query := map[string]string{"id": strconv.FormatInt(item.Id, 10)}
+			if _, err := j.Requester.Post("/queue/cancelItem", nil, nil, query); err != nil {
+				klog.Errorf("%+v", err)
+				return report, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+			}
+		}
+		for _, build := range report.RunningBuilds {
+			path := projectJobPath(projectId, build.Pipeline) + "/" + strconv.Itoa(build.Number) + "/stop"
+			if _, err := j.Requester.Post(path, nil, nil, nil); err != nil {
+				klog.Errorf("%+v", err)
+				return report, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+			}
+		}
+	}
+
+	if err := j.DeleteDevOpsProject(projectId); err != nil {
+		return report, err
+	}
+	report.Deleted = true
+	return report, nil
+}
+
+// projectItemOfURL returns the names of the job in a project from the URL of a job or a build, such as
+// [pipeline, branch], the rest of the URL after the job is dropped, such as the number of a build. The tasks of
+// the pipeline steps waiting for agents in the queue have the URLs of their builds
+func projectItemOfURL(rawURL, projectId string) ([]string, bool) {
+	itemURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, false
+	}
+	index := strings.Index(itemURL.Path, "/job/")
+	if index < 0 {
+		return nil, false
+	}
+	names := strings.Split(strings.Trim(itemURL.Path[index+len("/job/"):], "/"), "/job/")
+	if len(names) < 2 || names[0] != projectId {
+		return nil, false
+	}
+	names[len(names)-1] = strings.Split(names[len(names)-1], "/")[0]
+	return names[1:], true
+}
+
+// projectJobPath returns the path of a job in a project, the names of the job are separated by slashes
+func projectJobPath(projectId, names string) string {
+	path := "/job/" + url.PathEscape(projectId)
+	for _, name := range strings.Split(names, "/") {
+		path += "/job/" + url.PathEscape(name)
+	}
+	return path
+}
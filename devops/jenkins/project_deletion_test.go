@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opswave/go-jenkins/devops"
+)
+
+func newFakeDeletionJenkins(t *testing.T) (*Jenkins, *[]recordedRequest) {
+	responses := newFakeProjectResponses(t)
+	folderConfig, err := createFolderConfigXml(&devops.ProjectFolderConfig{Description: "for test"})
+	assert.Nil(t, err)
+	responses["/job/fake-project/config.xml/"] = folderConfig
+	responses["/computer/api/json"] = `{"computer":[
+		{"executors":[{"currentExecutable":null}],"oneOffExecutors":[
+			{"currentExecutable":{"number":3,"url":"http://jenkins/job/fake-project/job/deploy/3/"}},
+			{"currentExecutable":{"number":5,"url":"http://jenkins/jenkins/job/fake-project/job/build/job/master/5/"}},
+			{"currentExecutable":{"number":1,"url":"http://jenkins/job/other-project/job/deploy/1/"}}]},
+		{"executors":[{"currentExecutable":{"number":3,"url":"http://jenkins/job/fake-project/job/deploy/3/"}}]}]}`
+	responses["/queue/api/json"] = `{"items":[
+		{"id":7,"why":"Waiting for next available executor","task":{"name":"deploy","url":"http://jenkins/job/fake-project/job/deploy/"}},
+		{"id":8,"why":"Waiting for next available executor on linux","task":{"name":"part of build","url":"http://jenkins/job/fake-project/job/build/job/master/5/"}},
+		{"id":9,"task":{"name":"deploy","url":"http://jenkins/job/other-project/job/deploy/"}}]}`
+	return newFakeJenkinsWithResponses(t, http.StatusOK, responses)
+}
+
+func newFakeDeletionReport() *devops.ProjectDeletionReport {
+	return &devops.ProjectDeletionReport{
+		ProjectId: "fake-project",
+		RunningBuilds: []*devops.ProjectRunningBuild{
+			{Pipeline: "deploy", Number: 3, URL: "http://jenkins/job/fake-project/job/deploy/3/"},
+			{Pipeline: "build/master", Number: 5, URL: "http://jenkins/jenkins/job/fake-project/job/build/job/master/5/"},
+		},
+		QueuedItems: []*devops.ProjectQueuedItem{
+			{Id: 7, Pipeline: "deploy", Why: "Waiting for next available executor"},
+			{Id: 8, Pipeline: "build/master", Why: "Waiting for next available executor on linux"},
+		},
+		Credentials: []string{"kubeconfig", "docker", "unused"},
+	}
+}
+
+func postedRequests(requests *[]recordedRequest) []string {
+	var posts []string
+	for _, request := range *requests {
+		if request.method == http.MethodPost {
+			posts = append(posts, request.path+"?"+request.query)
+		}
+	}
+	return posts
+}
+
+type failedWriter struct{}
+
+func (failedWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestCheckDevOpsProjectDeletion(t *testing.T) {
+	jenkins, _ := newFakeDeletionJenkins(t)
+	report, err := jenkins.CheckDevOpsProjectDeletion("fake-project")
+	assert.Nil(t, err)
+	assert.Equal(t, newFakeDeletionReport(), report)
+	assert.True(t, report.Blocked())
+
+	jenkins, _ = newFakeJenkinsWithResponses(t, http.StatusNotFound, nil)
+	_, err = jenkins.CheckDevOpsProjectDeletion("fake-project")
+	assert.Equal(t, http.StatusNotFound, err.(restful.ServiceError).Code)
+}
+
+func TestDeleteDevOpsProjectWithOptions(t *testing.T) {
+	t.Run("refuse by default", func(t *testing.T) {
+		jenkins, requests := newFakeDeletionJenkins(t)
+		report, err := jenkins.DeleteDevOpsProjectWithOptions("fake-project", nil)
+		assert.Equal(t, http.StatusConflict, err.(restful.ServiceError).Code)
+		assert.Equal(t, newFakeDeletionReport(), report)
+		assert.Empty(t, postedRequests(requests))
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		jenkins, requests := newFakeDeletionJenkins(t)
+		report, err := jenkins.DeleteDevOpsProjectWithOptions("fake-project", &devops.ProjectDeleteOptions{Mode: devops.ProjectDeleteModeAbort})
+		assert.Nil(t, err)
+		assert.True(t, report.Deleted)
+		assert.Equal(t, []string{
+			"/queue/cancelItem?id=7",
+			"/queue/cancelItem?id=8",
+			"/job/fake-project/job/deploy/3/stop?",
+			"/job/fake-project/job/build/job/master/5/stop?",
+			"/job/fake-project/doDelete?",
+		}, postedRequests(requests))
+	})
+
+	t.Run("force", func(t *testing.T) {
+		jenkins, requests := newFakeDeletionJenkins(t)
+		report, err := jenkins.DeleteDevOpsProjectWithOptions("fake-project", &devops.ProjectDeleteOptions{Mode: devops.ProjectDeleteModeForce})
+		assert.Nil(t, err)
+		assert.Equal(t, &devops.ProjectDeletionReport{ProjectId: "fake-project", Deleted: true}, report)
+		assert.Equal(t, []string{"/job/fake-project/doDelete?"}, postedRequests(requests))
+	})
+
+	t.Run("archive before deleting", func(t *testing.T) {
+		jenkins, requests := newFakeDeletionJenkins(t)
+		archive := &bytes.Buffer{}
+		report, err := jenkins.DeleteDevOpsProjectWithOptions("fake-project", &devops.ProjectDeleteOptions{
+			Mode:    devops.ProjectDeleteModeForce,
+			Archive: archive,
+		})
+		assert.Nil(t, err)
+		assert.True(t, report.Deleted)
+		files, err := readProjectArchive(archive)
+		assert.Nil(t, err)
+		assert.Contains(t, files, projectArchiveManifest)
+		assert.Equal(t, []string{"/job/fake-project/doDelete?"}, postedRequests(requests))
+
+		jenkins, requests = newFakeDeletionJenkins(t)
+		report, err = jenkins.DeleteDevOpsProjectWithOptions("fake-project", &devops.ProjectDeleteOptions{
+			Mode:    devops.ProjectDeleteModeAbort,
+			Archive: failedWriter{},
+		})
+		assert.NotNil(t, err)
+		assert.False(t, report.Deleted)
+		assert.Empty(t, postedRequests(requests))
+	})
+
+	t.Run("unsupported mode", func(t *testing.T) {
+		jenkins, requests := newFakeDeletionJenkins(t)
+		_, err := jenkins.DeleteDevOpsProjectWithOptions("fake-project", &devops.ProjectDeleteOptions{Mode: "unknown"})
+		assert.Equal(t, http.StatusBadRequest, err.(restful.ServiceError).Code)
+		assert.Empty(t, postedRequests(requests))
+	})
+}
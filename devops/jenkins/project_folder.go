@@ -0,0 +1,336 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/beevik/etree"
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/opswave/go-jenkins/devops"
+)
+
+// the properties of a folder which are configured by devops.ProjectFolderConfig
+const (
+	DisplayNameTag                = "displayName"
+	HealthMetricsTag              = "healthMetrics"
+	FolderLibrariesTag            = "org.jenkinsci.plugins.workflow.libs.FolderLibraries"
+	LibraryConfigurationTag       = "org.jenkinsci.plugins.workflow.libs.LibraryConfiguration"
+	FolderEnvPropertiesTag        = "com.mig82.folders.properties.FolderProperties"
+	FolderEnvStringTag            = "com.mig82.folders.properties.StringProperty"
+	FolderDockerConfigTag         = "org.jenkinsci.plugins.pipeline.modeldefinition.config.FolderConfig"
+	FolderAllowedLabelsTag        = "jenkins.plugins.folderlabels.AllowedLabelsFolderProperty"
+	WorstChildHealthMetricClass   = "com.cloudbees.hudson.plugins.folder.health.WorstChildHealthMetric"
+	AverageChildHealthMetricClass = "com.cloudbees.hudson.plugins.folder.health.AverageChildHealthMetric"
+)
+
+var folderHealthMetricClasses = map[string]string{
+	devops.WorstChildHealthMetric:   WorstChildHealthMetricClass,
+	devops.AverageChildHealthMetric: AverageChildHealthMetricClass,
+}
+
+// CreateDevOpsProjectWithConfig creates the folder of a project from its config.xml, so it is configured at once
+func (j *Jenkins) CreateDevOpsProjectWithConfig(projectId string, config *devops.ProjectFolderConfig) (string, error) {
+	if config == nil {
+		return j.CreateDevOpsProject(projectId)
+	}
+	folderConfig, err := createFolderConfigXml(config)
+	if err != nil {
+		return "", restful.NewError(http.StatusBadRequest, err.Error())
+	}
+	if _, err = j.CreateJobInFolder(folderConfig, projectId); err != nil {
+		klog.Errorf("%+v", err)
+		return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return projectId, nil
+}
+
+func (j *Jenkins) GetDevOpsProjectConfig(projectId string) (*devops.ProjectFolderConfig, error) {
+	job, err := j.GetJob(projectId)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	config, err := job.GetConfig()
+	if err != nil {
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	folderConfig, err := parseFolderConfigXml(config)
+	if err != nil {
+		return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	return folderConfig, nil
+}
+
+func (j *Jenkins) UpdateDevOpsProjectConfig(projectId string, config *devops.ProjectFolderConfig) (*devops.ProjectFolderConfig, error) {
+	if config == nil {
+		return nil, restful.NewError(http.StatusBadRequest, "the folder config is required")
+	}
+	job, err := j.GetJob(projectId)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	currentConfig, err := job.GetConfig()
+	if err != nil {
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	updatedConfig, err := updateFolderConfigXml(currentConfig, config)
+	if err != nil {
+		return nil, restful.NewError(http.StatusBadRequest, err.Error())
+	}
+	if err = job.UpdateConfig(updatedConfig); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	folderConfig, err := parseFolderConfigXml(updatedConfig)
+	if err != nil {
+		return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	return folderConfig, nil
+}
+
+func createFolderConfigXml(config *devops.ProjectFolderConfig) (string, error) {
+	xmlString := `<?xml version='1.0' encoding='UTF-8'?>
+<com.cloudbees.hudson.plugins.folder.Folder plugin="cloudbees-folder">
+  <actions/>
+  <description></description>
+  <properties/>
+  <healthMetrics/>
+</com.cloudbees.hudson.plugins.folder.Folder>
+`
+	return updateFolderConfigXml(xmlString, config)
+}
+
+// updateFolderConfigXml updates the folder in place, the properties and the health metrics which are not
+// configured by devops.ProjectFolderConfig are kept
+func updateFolderConfigXml(config string, folderConfig *devops.ProjectFolderConfig) (string, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(replaceXmlVersion(config, "1.1", "1.0")); err != nil {
+		return "", err
+	}
+	folder := doc.Root()
+	if folder == nil {
+		return "", fmt.Errorf("can not find folder definition")
+	}
+
+	description := addOrUpdateElement(folder, "description", StringNull)
+	description.SetText(folderConfig.Description)
+	if folderConfig.DisplayName != "" {
+		displayName := folder.SelectElement(DisplayNameTag)
+		if displayName == nil {
+			displayName = etree.NewElement(DisplayNameTag)
+			folder.InsertChildAt(description.Index()+1, displayName)
+		}
+		displayName.SetText(folderConfig.DisplayName)
+	} else {
+		removeChildElement(folder, DisplayNameTag)
+	}
+
+	properties := addOrUpdateElement(folder, PropertiesTag, StringNull)
+	if err := replaceFolderPropertiesInEtree(properties, folderConfig); err != nil {
+		return "", err
+	}
+	if err := replaceHealthMetricsInEtree(addOrUpdateElement(folder, HealthMetricsTag, StringNull), folderConfig.HealthMetrics); err != nil {
+		return "", err
+	}
+
+	doc.Indent(2)
+	stringXml, err := doc.WriteToString()
+	if err != nil {
+		return "", err
+	}
+	return replaceXmlVersion(stringXml, "1.0", "1.1"), nil
+}
+
+func replaceFolderPropertiesInEtree(properties *etree.Element, config *devops.ProjectFolderConfig) error {
+	if len(config.Environment) > 0 {
+		property := addOrUpdateJobProperty(properties, FolderEnvPropertiesTag, "folder-properties")
+		removeChildElement(property, PropertiesTag)
+		envProperties := property.CreateElement(PropertiesTag)
+		for _, env := range config.Environment {
+			if env.Key == "" {
+				return fmt.Errorf("the key of the environment variable is required")
+			}
+			envProperty := envProperties.CreateElement(FolderEnvStringTag)
+			envProperty.CreateElement("key").SetText(env.Key)
+			envProperty.CreateElement("value").SetText(env.Value)
+		}
+	} else {
+		removeChildElement(properties, FolderEnvPropertiesTag)
+	}
+
+	if len(config.Libraries) > 0 {
+		property := addOrUpdateJobProperty(properties, FolderLibrariesTag, "workflow-cps-global-lib")
+		if err := replaceLibrariesInEtree(addOrUpdateElement(property, "libraries", StringNull), config.Libraries); err != nil {
+			return err
+		}
+	} else {
+		removeChildElement(properties, FolderLibrariesTag)
+	}
+
+	if config.DockerLabel != "" || config.DockerRegistry != nil {
+		property := addOrUpdateJobProperty(properties, FolderDockerConfigTag, "pipeline-model-definition")
+		setChildElementText(property, "dockerLabel", config.DockerLabel)
+		removeChildElement(property, "registry")
+		if config.DockerRegistry != nil {
+			registry := property.CreateElement("registry")
+			registry.CreateAttr(PluginKey, "docker-commons")
+			if config.DockerRegistry.URL != "" {
+				registry.CreateElement("url").SetText(config.DockerRegistry.URL)
+			}
+			if config.DockerRegistry.CredentialId != "" {
+				registry.CreateElement("credentialsId").SetText(config.DockerRegistry.CredentialId)
+			}
+		}
+	} else {
+		removeChildElement(properties, FolderDockerConfigTag)
+	}
+
+	if len(config.AllowedLabels) > 0 {
+		property := addOrUpdateJobProperty(properties, FolderAllowedLabelsTag, "folder-labels")
+		removeChildElement(property, "labels")
+		labels := property.CreateElement("labels")
+		for _, label := range config.AllowedLabels {
+			labels.CreateElement("string").SetText(label)
+		}
+	} else {
+		removeChildElement(properties, FolderAllowedLabelsTag)
+	}
+	return nil
+}
+
+// replaceLibrariesInEtree updates the libraries with the same names in place, so their retrievers are kept if
+// they are not given, the new libraries require the retrievers
+func replaceLibrariesInEtree(libraries *etree.Element, configs []*devops.PipelineLibrary) error {
+	existing := map[string]*etree.Element{}
+	for _, library := range libraries.SelectElements(LibraryConfigurationTag) {
+		existing[getElementTextValueOrEmpty(library, "name")] = library
+		libraries.RemoveChild(library)
+	}
+	for _, config := range configs {
+		if config.Name == "" {
+			return fmt.Errorf("the name of the library is required")
+		}
+		library, ok := existing[config.Name]
+		if ok {
+			delete(existing, config.Name)
+			libraries.AddChild(library)
+		} else {
+			if config.Retriever == nil {
+				return fmt.Errorf("the retriever of the library %s is required", config.Name)
+			}
+			library = libraries.CreateElement(LibraryConfigurationTag)
+			library.CreateElement("name").SetText(config.Name)
+		}
+		if err := updateLibraryInEtree(library, config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceHealthMetricsInEtree replaces the known health metrics, the ones from other plugins are kept
+func replaceHealthMetricsInEtree(healthMetrics *etree.Element, metrics []*devops.FolderHealthMetric) error {
+	for _, class := range folderHealthMetricClasses {
+		for _, metric := range healthMetrics.SelectElements(class) {
+			healthMetrics.RemoveChild(metric)
+		}
+	}
+	for _, metric := range metrics {
+		class, ok := folderHealthMetricClasses[metric.Type]
+		if !ok {
+			return fmt.Errorf("unsupported health metric type '%s'", metric.Type)
+		}
+		metricEle := healthMetrics.CreateElement(class)
+		if metric.Type == devops.WorstChildHealthMetric {
+			metricEle.CreateElement("nonRecursive").SetText(fmt.Sprint(metric.NonRecursive))
+		}
+	}
+	return nil
+}
+
+func parseFolderConfigXml(config string) (*devops.ProjectFolderConfig, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(replaceXmlVersion(config, "1.1", "1.0")); err != nil {
+		return nil, err
+	}
+	folder := doc.Root()
+	if folder == nil {
+		return nil, fmt.Errorf("can not find folder definition")
+	}
+	folderConfig := &devops.ProjectFolderConfig{
+		DisplayName: getElementTextValueOrEmpty(folder, DisplayNameTag),
+		Description: getElementTextValueOrEmpty(folder, "description"),
+	}
+
+	if properties := folder.SelectElement(PropertiesTag); properties != nil {
+		if property := properties.SelectElement(FolderEnvPropertiesTag); property != nil {
+			if envProperties := property.SelectElement(PropertiesTag); envProperties != nil {
+				for _, env := range envProperties.SelectElements(FolderEnvStringTag) {
+					folderConfig.Environment = append(folderConfig.Environment, &devops.FolderProperty{
+						Key:   getElementTextValueOrEmpty(env, "key"),
+						Value: getElementTextValueOrEmpty(env, "value"),
+					})
+				}
+			}
+		}
+
+		if property := properties.SelectElement(FolderLibrariesTag); property != nil {
+			if libraries := property.SelectElement("libraries"); libraries != nil {
+				for _, library := range libraries.SelectElements(LibraryConfigurationTag) {
+					folderConfig.Libraries = append(folderConfig.Libraries, getLibraryFromEtree(library))
+				}
+			}
+		}
+
+		if property := properties.SelectElement(FolderDockerConfigTag); property != nil {
+			folderConfig.DockerLabel = getElementTextValueOrEmpty(property, "dockerLabel")
+			if registry := property.SelectElement("registry"); registry != nil {
+				folderConfig.DockerRegistry = &devops.DockerRegistry{
+					URL:          getElementTextValueOrEmpty(registry, "url"),
+					CredentialId: getElementTextValueOrEmpty(registry, "credentialsId"),
+				}
+			}
+		}
+
+		if property := properties.SelectElement(FolderAllowedLabelsTag); property != nil {
+			if labels := property.SelectElement("labels"); labels != nil {
+				for _, label := range labels.SelectElements("string") {
+					folderConfig.AllowedLabels = append(folderConfig.AllowedLabels, label.Text())
+				}
+			}
+		}
+	}
+
+	if healthMetrics := folder.SelectElement(HealthMetricsTag); healthMetrics != nil {
+		for _, metric := range healthMetrics.ChildElements() {
+			for metricType, class := range folderHealthMetricClasses {
+				if metric.Tag == class {
+					folderConfig.HealthMetrics = append(folderConfig.HealthMetrics, &devops.FolderHealthMetric{
+						Type:         metricType,
+						NonRecursive: getElementTextValueOrEmpty(metric, "nonRecursive") == "true",
+					})
+				}
+			}
+		}
+	}
+	return folderConfig, nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+func newFakeFolderConfig() *devops.ProjectFolderConfig {
+	return &devops.ProjectFolderConfig{
+		DisplayName: "Fake Project",
+		Description: "for test",
+		Environment: []*devops.FolderProperty{{Key: "REGISTRY", Value: "docker.io"}, {Key: "EMPTY", Value: ""}},
+		Libraries: []*devops.PipelineLibrary{{
+			Name:                 "shared",
+			DefaultVersion:       "main",
+			Implicit:             true,
+			AllowVersionOverride: true,
+			Retriever: &devops.LibraryRetriever{
+				SourceType: devopsv1alpha3.SourceTypeGit,
+				GitSource:  &devopsv1alpha3.GitSource{Url: "https://github.com/kubesphere/pipeline-library"},
+			},
+		}},
+		DockerLabel:    "docker",
+		DockerRegistry: &devops.DockerRegistry{URL: "https://docker.io", CredentialId: "dockerhub"},
+		HealthMetrics: []*devops.FolderHealthMetric{
+			{Type: devops.WorstChildHealthMetric, NonRecursive: true},
+			{Type: devops.AverageChildHealthMetric},
+		},
+		AllowedLabels: []string{"linux", "docker"},
+	}
+}
+
+func TestFolderConfigXml(t *testing.T) {
+	inputs := []*devops.ProjectFolderConfig{{}, {Description: "for test"}, newFakeFolderConfig()}
+	for index, input := range inputs {
+		config, err := createFolderConfigXml(input)
+		assert.Nil(t, err)
+		output, err := parseFolderConfigXml(config)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output, "index: %d", index)
+	}
+
+	_, err := createFolderConfigXml(&devops.ProjectFolderConfig{HealthMetrics: []*devops.FolderHealthMetric{{Type: "unknown"}}})
+	assert.NotNil(t, err)
+	_, err = createFolderConfigXml(&devops.ProjectFolderConfig{Libraries: []*devops.PipelineLibrary{{}}})
+	assert.NotNil(t, err)
+	// a new library requires the retriever
+	_, err = createFolderConfigXml(&devops.ProjectFolderConfig{Libraries: []*devops.PipelineLibrary{{Name: "shared"}}})
+	assert.NotNil(t, err)
+}
+
+func TestUpdateFolderConfigXml(t *testing.T) {
+	config := `<?xml version='1.1' encoding='UTF-8'?>
+<com.cloudbees.hudson.plugins.folder.Folder plugin="cloudbees-folder@6.15">
+  <actions/>
+  <description>old</description>
+  <properties>
+    <com.cloudbees.hudson.plugins.folder.properties.FolderCredentialsProvider_-FolderCredentialsProperty/>
+    <org.jenkinsci.plugins.workflow.libs.FolderLibraries plugin="workflow-cps-global-lib@2.21">
+      <libraries>
+        <org.jenkinsci.plugins.workflow.libs.LibraryConfiguration>
+          <name>shared</name>
+          <retriever class="org.jenkinsci.plugins.workflow.libs.SCMSourceRetriever">
+            <scm class="jenkins.plugins.git.GitSCMSource"/>
+          </retriever>
+          <defaultVersion>master</defaultVersion>
+          <implicit>false</implicit>
+        </org.jenkinsci.plugins.workflow.libs.LibraryConfiguration>
+        <org.jenkinsci.plugins.workflow.libs.LibraryConfiguration>
+          <name>removed</name>
+        </org.jenkinsci.plugins.workflow.libs.LibraryConfiguration>
+      </libraries>
+    </org.jenkinsci.plugins.workflow.libs.FolderLibraries>
+  </properties>
+  <folderViews class="com.cloudbees.hudson.plugins.folder.views.DefaultFolderViewHolder"/>
+  <healthMetrics>
+    <com.cloudbees.hudson.plugins.folder.health.WorstChildHealthMetric>
+      <nonRecursive>false</nonRecursive>
+    </com.cloudbees.hudson.plugins.folder.health.WorstChildHealthMetric>
+    <com.cloudbees.hudson.plugins.folder.health.ProjectEnabledHealthMetric/>
+  </healthMetrics>
+</com.cloudbees.hudson.plugins.folder.Folder>
+`
+	folderConfig := newFakeFolderConfig()
+	folderConfig.Libraries[0].Retriever = nil
+	updated, err := updateFolderConfigXml(config, folderConfig)
+	assert.Nil(t, err)
+	output, err := parseFolderConfigXml(updated)
+	assert.Nil(t, err)
+	assert.Equal(t, folderConfig, output)
+
+	// the properties and health metrics which are not configured are kept, so are the retrievers of the libraries
+	assert.Contains(t, updated, folderCredentialsPropertyTag)
+	assert.Contains(t, updated, "com.cloudbees.hudson.plugins.folder.health.ProjectEnabledHealthMetric")
+	assert.Contains(t, updated, "folderViews")
+	assert.Contains(t, updated, "jenkins.plugins.git.GitSCMSource")
+	assert.NotContains(t, updated, "removed")
+	assert.Contains(t, updated, "<?xml version='1.1' encoding='UTF-8'?>")
+
+	updated, err = updateFolderConfigXml(updated, &devops.ProjectFolderConfig{})
+	assert.Nil(t, err)
+	output, err = parseFolderConfigXml(updated)
+	assert.Nil(t, err)
+	assert.Equal(t, &devops.ProjectFolderConfig{}, output)
+	assert.Contains(t, updated, folderCredentialsPropertyTag)
+}
+
+func TestDevOpsProjectConfig(t *testing.T) {
+	folderConfig, err := createFolderConfigXml(&devops.ProjectFolderConfig{Description: "old"})
+	assert.Nil(t, err)
+	jenkins, requests := newFakeJenkinsWithResponses(t, http.StatusOK, map[string]string{
+		"/job/fake-project/api/json":    `{"_class":"com.cloudbees.hudson.plugins.folder.Folder","name":"fake-project"}`,
+		"/job/fake-project/config.xml/": folderConfig,
+	})
+
+	config, err := jenkins.GetDevOpsProjectConfig("fake-project")
+	assert.Nil(t, err)
+	assert.Equal(t, &devops.ProjectFolderConfig{Description: "old"}, config)
+
+	config, err = jenkins.UpdateDevOpsProjectConfig("fake-project", newFakeFolderConfig())
+	assert.Nil(t, err)
+	assert.Equal(t, newFakeFolderConfig(), config)
+	_, err = jenkins.UpdateDevOpsProjectConfig("fake-project", nil)
+	assert.NotNil(t, err)
+
+	projectId, err := jenkins.CreateDevOpsProjectWithConfig("new-project", newFakeFolderConfig())
+	assert.Nil(t, err)
+	assert.Equal(t, "new-project", projectId)
+
+	var posts []recordedRequest
+	for _, request := range *requests {
+		if request.method == http.MethodPost {
+			posts = append(posts, request)
+		}
+	}
+	if assert.Equal(t, 2, len(posts)) {
+		assert.Equal(t, "/job/fake-project/config.xml", posts[0].path)
+		updated, err := parseFolderConfigXml(posts[0].body)
+		assert.Nil(t, err)
+		assert.Equal(t, newFakeFolderConfig(), updated)
+
+		assert.Equal(t, "/createItem", posts[1].path)
+		assert.Equal(t, "name=new-project", posts[1].query)
+		created, err := parseFolderConfigXml(posts[1].body)
+		assert.Nil(t, err)
+		assert.Equal(t, newFakeFolderConfig(), created)
+	}
+}
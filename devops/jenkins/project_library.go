@@ -0,0 +1,248 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/beevik/etree"
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/opswave/go-jenkins/devops"
+	"github.com/opswave/go-jenkins/devops/jenkins/internal"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+const (
+	RetrieverTag            = "retriever"
+	SCMSourceRetrieverClass = "org.jenkinsci.plugins.workflow.libs.SCMSourceRetriever"
+)
+
+func (j *Jenkins) ListProjectLibraries(projectId string) ([]*devops.PipelineLibrary, error) {
+	folderConfig, err := j.GetDevOpsProjectConfig(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return folderConfig.Libraries, nil
+}
+
+func (j *Jenkins) CreateProjectLibrary(projectId string, library *devops.PipelineLibrary) (*devops.PipelineLibrary, error) {
+	if err := validateLibrary(library); err != nil {
+		return nil, err
+	}
+	if library.Retriever == nil {
+		return nil, restful.NewError(http.StatusBadRequest, "the retriever of the library is required")
+	}
+	var created *devops.PipelineLibrary
+	err := j.modifyProjectLibraries(projectId, func(libraries *etree.Element) error {
+		if findLibraryElement(libraries, library.Name) != nil {
+			return restful.NewError(http.StatusConflict, fmt.Sprintf("library %s already exists", library.Name))
+		}
+		libraryEle := libraries.CreateElement(LibraryConfigurationTag)
+		libraryEle.CreateElement("name").SetText(library.Name)
+		if err := updateLibraryInEtree(libraryEle, library); err != nil {
+			return restful.NewError(http.StatusBadRequest, err.Error())
+		}
+		created = getLibraryFromEtree(libraryEle)
+		return nil
+	})
+	return created, err
+}
+
+func (j *Jenkins) UpdateProjectLibrary(projectId string, library *devops.PipelineLibrary) (*devops.PipelineLibrary, error) {
+	if err := validateLibrary(library); err != nil {
+		return nil, err
+	}
+	var updated *devops.PipelineLibrary
+	err := j.modifyProjectLibraries(projectId, func(libraries *etree.Element) error {
+		libraryEle := findLibraryElement(libraries, library.Name)
+		if libraryEle == nil {
+			return restful.NewError(http.StatusNotFound, fmt.Sprintf("library %s not found", library.Name))
+		}
+		if err := updateLibraryInEtree(libraryEle, library); err != nil {
+			return restful.NewError(http.StatusBadRequest, err.Error())
+		}
+		updated = getLibraryFromEtree(libraryEle)
+		return nil
+	})
+	return updated, err
+}
+
+func (j *Jenkins) DeleteProjectLibrary(projectId, name string) error {
+	return j.modifyProjectLibraries(projectId, func(libraries *etree.Element) error {
+		libraryEle := findLibraryElement(libraries, name)
+		if libraryEle == nil {
+			return restful.NewError(http.StatusNotFound, fmt.Sprintf("library %s not found", name))
+		}
+		libraries.RemoveChild(libraryEle)
+		return nil
+	})
+}
+
+func validateLibrary(library *devops.PipelineLibrary) error {
+	if library == nil || library.Name == "" {
+		return restful.NewError(http.StatusBadRequest, "the name of the library is required")
+	}
+	return nil
+}
+
+// modifyProjectLibraries modifies the libraries in the config of the project folder, the property of the libraries
+// is removed if there is no library left
+func (j *Jenkins) modifyProjectLibraries(projectId string, modify func(libraries *etree.Element) error) error {
+	job, err := j.GetJob(projectId)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	config, err := job.GetConfig()
+	if err != nil {
+		return restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	doc := etree.NewDocument()
+	if err = doc.ReadFromString(replaceXmlVersion(config, "1.1", "1.0")); err != nil {
+		return restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	folder := doc.Root()
+	if folder == nil {
+		return restful.NewError(http.StatusInternalServerError, "can not find folder definition")
+	}
+
+	properties := addOrUpdateElement(folder, PropertiesTag, StringNull)
+	property := addOrUpdateJobProperty(properties, FolderLibrariesTag, "workflow-cps-global-lib")
+	libraries := addOrUpdateElement(property, "libraries", StringNull)
+	if err = modify(libraries); err != nil {
+		return err
+	}
+	if len(libraries.SelectElements(LibraryConfigurationTag)) == 0 {
+		removeChildElement(properties, FolderLibrariesTag)
+	}
+
+	doc.Indent(2)
+	stringXml, err := doc.WriteToString()
+	if err != nil {
+		return restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	if err = job.UpdateConfig(replaceXmlVersion(stringXml, "1.0", "1.1")); err != nil {
+		klog.Errorf("%+v", err)
+		return restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return nil
+}
+
+func findLibraryElement(libraries *etree.Element, name string) *etree.Element {
+	for _, library := range libraries.SelectElements(LibraryConfigurationTag) {
+		if getElementTextValueOrEmpty(library, "name") == name {
+			return library
+		}
+	}
+	return nil
+}
+
+// updateLibraryInEtree updates a LibraryConfiguration in place, the retriever is kept if the library has none
+func updateLibraryInEtree(library *etree.Element, config *devops.PipelineLibrary) error {
+	if config.Retriever != nil {
+		retriever := etree.NewElement(RetrieverTag)
+		if err := appendLibraryRetrieverToEtree(retriever, config.Retriever); err != nil {
+			return err
+		}
+		if existing := library.SelectElement(RetrieverTag); existing != nil {
+			library.InsertChildAt(existing.Index(), retriever)
+			library.RemoveChild(existing)
+		} else {
+			// the retriever follows the name as Jenkins writes it
+			library.InsertChildAt(addOrUpdateElement(library, "name", StringNull).Index()+1, retriever)
+		}
+	}
+	setChildElementText(library, "defaultVersion", config.DefaultVersion)
+	setChildElementText(library, "implicit", fmt.Sprint(config.Implicit))
+	setChildElementText(library, "allowVersionOverride", fmt.Sprint(config.AllowVersionOverride))
+	setChildElementText(library, "includeInChangesets", fmt.Sprint(config.IncludeInChangesets))
+	return nil
+}
+
+func getLibraryFromEtree(library *etree.Element) *devops.PipelineLibrary {
+	config := &devops.PipelineLibrary{
+		Name:                 getElementTextValueOrEmpty(library, "name"),
+		DefaultVersion:       getElementTextValueOrEmpty(library, "defaultVersion"),
+		Implicit:             getElementTextValueOrEmpty(library, "implicit") == "true",
+		AllowVersionOverride: getElementTextValueOrEmpty(library, "allowVersionOverride") == "true",
+		IncludeInChangesets:  getElementTextValueOrEmpty(library, "includeInChangesets") == "true",
+	}
+	if retriever := library.SelectElement(RetrieverTag); retriever != nil {
+		config.Retriever = getLibraryRetrieverFromEtree(retriever)
+	}
+	return config
+}
+
+// appendLibraryRetrieverToEtree writes a SCMSourceRetriever, its SCM source is the same as the branch source of
+// a multi-branch pipeline
+func appendLibraryRetrieverToEtree(retriever *etree.Element, config *devops.LibraryRetriever) error {
+	retriever.CreateAttr("class", SCMSourceRetrieverClass)
+	retriever.CreateElement("clone").SetText(fmt.Sprint(config.Clone))
+	scm := retriever.CreateElement("scm")
+	switch config.SourceType {
+	case devopsv1alpha3.SourceTypeGit:
+		if config.GitSource == nil {
+			return fmt.Errorf("the git source of the library is required")
+		}
+		internal.AppendGitSourceToEtree(scm, config.GitSource)
+	case devopsv1alpha3.SourceTypeGithub:
+		if config.GithubSource == nil {
+			return fmt.Errorf("the GitHub source of the library is required")
+		}
+		internal.AppendGithubSourceToEtree(scm, config.GithubSource)
+	case devopsv1alpha3.SourceTypeGitlab:
+		if config.GitlabSource == nil {
+			return fmt.Errorf("the GitLab source of the library is required")
+		}
+		internal.AppendGitlabSourceToEtree(scm, config.GitlabSource)
+	default:
+		return fmt.Errorf("unsupported source type '%s' of the library", config.SourceType)
+	}
+	if config.LibraryPath != "" {
+		retriever.CreateElement("libraryPath").SetText(config.LibraryPath)
+	}
+	return nil
+}
+
+// getLibraryRetrieverFromEtree returns nil if the retriever is not supported, such as the legacy SCM retriever
+func getLibraryRetrieverFromEtree(retriever *etree.Element) *devops.LibraryRetriever {
+	scm := retriever.SelectElement("scm")
+	if retriever.SelectAttrValue("class", "") != SCMSourceRetrieverClass || scm == nil || scm.SelectElement("traits") == nil {
+		return nil
+	}
+	config := &devops.LibraryRetriever{
+		LibraryPath: getElementTextValueOrEmpty(retriever, "libraryPath"),
+		Clone:       getElementTextValueOrEmpty(retriever, "clone") == "true",
+	}
+	switch scm.SelectAttrValue("class", "") {
+	case "jenkins.plugins.git.GitSCMSource":
+		config.SourceType = devopsv1alpha3.SourceTypeGit
+		config.GitSource = internal.GetGitSourcefromEtree(scm)
+	case "org.jenkinsci.plugins.github_branch_source.GitHubSCMSource":
+		config.SourceType = devopsv1alpha3.SourceTypeGithub
+		config.GithubSource = internal.GetGithubSourcefromEtree(scm)
+	case "io.jenkins.plugins.gitlabbranchsource.GitLabSCMSource":
+		config.SourceType = devopsv1alpha3.SourceTypeGitlab
+		config.GitlabSource = internal.GetGitlabSourceFromEtree(scm)
+	default:
+		return nil
+	}
+	return config
+}
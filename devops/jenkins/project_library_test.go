@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+func newFakeLibrary() *devops.PipelineLibrary {
+	return &devops.PipelineLibrary{
+		Name:           "shared",
+		DefaultVersion: "main",
+		Implicit:       true,
+		Retriever: &devops.LibraryRetriever{
+			SourceType: devopsv1alpha3.SourceTypeGit,
+			GitSource: &devopsv1alpha3.GitSource{
+				Url:              "https://github.com/kubesphere/pipeline-library",
+				CredentialId:     "github",
+				DiscoverBranches: true,
+				DiscoverTags:     true,
+			},
+		},
+	}
+}
+
+func newFakeLibraryJenkins(t *testing.T) (*Jenkins, *[]recordedRequest) {
+	folderConfig, err := createFolderConfigXml(&devops.ProjectFolderConfig{
+		Description: "for test",
+		Libraries:   []*devops.PipelineLibrary{newFakeLibrary()},
+	})
+	assert.Nil(t, err)
+	return newFakeJenkinsWithResponses(t, http.StatusOK, map[string]string{
+		"/job/fake-project/api/json":    `{"_class":"com.cloudbees.hudson.plugins.folder.Folder","name":"fake-project"}`,
+		"/job/fake-project/config.xml/": folderConfig,
+	})
+}
+
+// postedFolderConfig returns the folder config posted to Jenkins
+func postedFolderConfig(t *testing.T, requests *[]recordedRequest) (string, *devops.ProjectFolderConfig) {
+	for _, request := range *requests {
+		if request.method == http.MethodPost && request.path == "/job/fake-project/config.xml" {
+			folderConfig, err := parseFolderConfigXml(request.body)
+			assert.Nil(t, err)
+			return request.body, folderConfig
+		}
+	}
+	t.Fatal("the folder config is not updated")
+	return "", nil
+}
+
+func TestLibraryRetriever(t *testing.T) {
+	retrievers := []*devops.LibraryRetriever{
+		newFakeLibrary().Retriever,
+		{
+			SourceType:  devopsv1alpha3.SourceTypeGithub,
+			LibraryPath: "libs/",
+			Clone:       true,
+			GithubSource: &devopsv1alpha3.GithubSource{
+				Owner:                     "kubesphere",
+				Repo:                      "pipeline-library",
+				CredentialId:              "github",
+				DiscoverBranches:          1,
+				AcceptJenkinsNotification: true,
+			},
+		},
+		{
+			SourceType: devopsv1alpha3.SourceTypeGitlab,
+			GitlabSource: &devopsv1alpha3.GitlabSource{
+				ServerName:       "default",
+				Owner:            "kubesphere",
+				Repo:             "kubesphere/pipeline-library",
+				CredentialId:     "gitlab",
+				DiscoverBranches: 1,
+			},
+		},
+	}
+	for index, input := range retrievers {
+		retriever := etree.NewElement(RetrieverTag)
+		assert.Nil(t, appendLibraryRetrieverToEtree(retriever, input))
+		assert.Equal(t, input, getLibraryRetrieverFromEtree(retriever), "index: %d", index)
+	}
+
+	for _, input := range []*devops.LibraryRetriever{
+		{SourceType: devopsv1alpha3.SourceTypeGit},
+		{SourceType: devopsv1alpha3.SourceTypeSVN},
+	} {
+		assert.NotNil(t, appendLibraryRetrieverToEtree(etree.NewElement(RetrieverTag), input))
+	}
+
+	legacy := etree.NewElement(RetrieverTag)
+	legacy.CreateAttr("class", "org.jenkinsci.plugins.workflow.libs.SCMRetriever")
+	assert.Nil(t, getLibraryRetrieverFromEtree(legacy))
+}
+
+func TestListProjectLibraries(t *testing.T) {
+	jenkins, _ := newFakeLibraryJenkins(t)
+	libraries, err := jenkins.ListProjectLibraries("fake-project")
+	assert.Nil(t, err)
+	assert.Equal(t, []*devops.PipelineLibrary{newFakeLibrary()}, libraries)
+}
+
+func TestCreateProjectLibrary(t *testing.T) {
+	jenkins, requests := newFakeLibraryJenkins(t)
+	library := &devops.PipelineLibrary{
+		Name:                 "another",
+		AllowVersionOverride: true,
+		Retriever: &devops.LibraryRetriever{
+			SourceType: devopsv1alpha3.SourceTypeGit,
+			GitSource:  &devopsv1alpha3.GitSource{Url: "https://github.com/kubesphere/another-library"},
+		},
+	}
+	created, err := jenkins.CreateProjectLibrary("fake-project", library)
+	assert.Nil(t, err)
+	assert.Equal(t, library, created)
+	_, folderConfig := postedFolderConfig(t, requests)
+	assert.Equal(t, []*devops.PipelineLibrary{newFakeLibrary(), library}, folderConfig.Libraries)
+	assert.Equal(t, "for test", folderConfig.Description)
+
+	_, err = jenkins.CreateProjectLibrary("fake-project", newFakeLibrary())
+	assert.Equal(t, http.StatusConflict, err.(restful.ServiceError).Code)
+	_, err = jenkins.CreateProjectLibrary("fake-project", &devops.PipelineLibrary{})
+	assert.Equal(t, http.StatusBadRequest, err.(restful.ServiceError).Code)
+	_, err = jenkins.CreateProjectLibrary("fake-project", &devops.PipelineLibrary{Name: "without-retriever"})
+	assert.Equal(t, http.StatusBadRequest, err.(restful.ServiceError).Code)
+	_, err = jenkins.CreateProjectLibrary("fake-project", &devops.PipelineLibrary{
+		Name:      "invalid",
+		Retriever: &devops.LibraryRetriever{SourceType: devopsv1alpha3.SourceTypeGithub},
+	})
+	assert.Equal(t, http.StatusBadRequest, err.(restful.ServiceError).Code)
+}
+
+func TestUpdateProjectLibrary(t *testing.T) {
+	jenkins, requests := newFakeLibraryJenkins(t)
+	// the retriever is kept if it is not given
+	library := &devops.PipelineLibrary{Name: "shared", DefaultVersion: "v1.0.0"}
+	updated, err := jenkins.UpdateProjectLibrary("fake-project", library)
+	assert.Nil(t, err)
+	expected := newFakeLibrary()
+	expected.DefaultVersion = "v1.0.0"
+	expected.Implicit = false
+	assert.Equal(t, expected, updated)
+	_, folderConfig := postedFolderConfig(t, requests)
+	assert.Equal(t, []*devops.PipelineLibrary{expected}, folderConfig.Libraries)
+
+	jenkins, requests = newFakeLibraryJenkins(t)
+	library.Retriever = &devops.LibraryRetriever{
+		SourceType:   devopsv1alpha3.SourceTypeGithub,
+		GithubSource: &devopsv1alpha3.GithubSource{Owner: "kubesphere", Repo: "pipeline-library"},
+	}
+	updated, err = jenkins.UpdateProjectLibrary("fake-project", library)
+	assert.Nil(t, err)
+	assert.Equal(t, library, updated)
+	config, folderConfig := postedFolderConfig(t, requests)
+	assert.Equal(t, []*devops.PipelineLibrary{library}, folderConfig.Libraries)
+	assert.NotContains(t, config, "jenkins.plugins.git.GitSCMSource")
+
+	_, err = jenkins.UpdateProjectLibrary("fake-project", &devops.PipelineLibrary{Name: "missing"})
+	assert.Equal(t, http.StatusNotFound, err.(restful.ServiceError).Code)
+}
+
+func TestDeleteProjectLibrary(t *testing.T) {
+	jenkins, requests := newFakeLibraryJenkins(t)
+	assert.Nil(t, jenkins.DeleteProjectLibrary("fake-project", "shared"))
+	config, folderConfig := postedFolderConfig(t, requests)
+	assert.Empty(t, folderConfig.Libraries)
+	assert.NotContains(t, config, FolderLibrariesTag)
+
+	err := jenkins.DeleteProjectLibrary("fake-project", "missing")
+	assert.Equal(t, http.StatusNotFound, err.(restful.ServiceError).Code)
+}
@@ -73,6 +73,28 @@ func (j *Jenkins) CreateProjectPipeline(projectId string, pipeline *devopsv1alph
 			return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
 		}
 
+		return pipeline.Name, nil
+	case devopsv1alpha3.OrganizationFolderPipelineType:
+		config, err := createOrganizationFolderConfigXml(pipeline.Spec.OrganizationFolder)
+		if err != nil {
+			return "", restful.NewError(http.StatusBadRequest, err.Error())
+		}
+
+		job, err := j.GetJob(pipeline.Name, projectId)
+		if job != nil {
+			err := fmt.Errorf("job name [%s] has been used", job.GetName())
+			return "", restful.NewError(http.StatusConflict, err.Error())
+		}
+
+		if err != nil && devops.GetDevOpsStatusCode(err) != http.StatusNotFound {
+			return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+
+		_, err = j.CreateJobInFolder(config, pipeline.Name, projectId)
+		if err != nil {
+			return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+
 		return pipeline.Name, nil
 
 	default:
@@ -115,14 +137,38 @@ func (j *Jenkins) UpdateProjectPipeline(projectId string, pipeline *devopsv1alph
 		return pipeline.Name, nil
 
 	case devopsv1alpha3.MultiBranchPipelineType:
-		config, err := createMultiBranchPipelineConfigXml(projectId, pipeline.Spec.MultiBranchPipeline)
+		job, err := j.GetJob(pipeline.Spec.MultiBranchPipeline.Name, projectId)
+
+		if err != nil {
+			return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+		currentConfig, err := job.GetConfig()
+		if err != nil {
+			return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+
+		config, err := updateMultiBranchPipelineConfigXml(currentConfig, projectId, pipeline.Spec.MultiBranchPipeline)
 		if err != nil {
 			klog.Errorf("%+v", err)
 			return "", restful.NewError(http.StatusInternalServerError, err.Error())
 		}
 
-		job, err := j.GetJob(pipeline.Spec.MultiBranchPipeline.Name, projectId)
+		err = job.UpdateConfig(config)
+		if err != nil {
+			klog.Errorf("%+v", err)
+			return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+
+		return pipeline.Name, nil
 
+	case devopsv1alpha3.OrganizationFolderPipelineType:
+		config, err := createOrganizationFolderConfigXml(pipeline.Spec.OrganizationFolder)
+		if err != nil {
+			klog.Errorf("%+v", err)
+			return "", restful.NewError(http.StatusBadRequest, err.Error())
+		}
+
+		job, err := j.GetJob(pipeline.Name, projectId)
 		if err != nil {
 			return "", restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
 		}
@@ -148,17 +194,40 @@ func (j *Jenkins) GetProjectPipelineConfig(projectId, pipelineId string) (*devop
 		klog.Errorf("%+v", err)
 		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
 	}
-	switch job.Raw.Class {
-	case "org.jenkinsci.plugins.workflow.job.WorkflowJob":
-		config, err := job.GetConfig()
-		if err != nil {
-			return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
-		}
+	if !isPipelineJobClass(job.Raw.Class) {
+		err = fmt.Errorf("unsupported job class %s", job.Raw.Class)
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(http.StatusBadRequest, err.Error())
+	}
+	config, err := job.GetConfig()
+	if err != nil {
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	pipeline, err := parseJobConfig(job.Raw.Class, pipelineId, config)
+	if err != nil {
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	return pipeline, nil
+}
+
+// isPipelineJobClass returns true if a job of the class can be converted to a pipeline
+func isPipelineJobClass(class string) bool {
+	switch class {
+	case WorkflowJobClass, WorkflowMultiBranchProjectClass, OrganizationFolderClass:
+		return true
+	}
+	return false
+}
+
+// parseJobConfig converts the config of a job to a pipeline, see also isPipelineJobClass
+func parseJobConfig(class, name, config string) (*devopsv1alpha3.Pipeline, error) {
+	switch class {
+	case WorkflowJobClass:
 		pipeline, err := parsePipelineConfigXml(config)
 		if err != nil {
-			return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+			return nil, err
 		}
-		pipeline.Name = pipelineId
+		pipeline.Name = name
 		return &devopsv1alpha3.Pipeline{
 			Spec: devopsv1alpha3.PipelineSpec{
 				Type:     devopsv1alpha3.NoScmPipelineType,
@@ -166,24 +235,31 @@ func (j *Jenkins) GetProjectPipelineConfig(projectId, pipelineId string) (*devop
 			},
 		}, nil
 
-	case "org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject":
-		config, err := job.GetConfig()
-		if err != nil {
-			return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
-		}
+	case WorkflowMultiBranchProjectClass:
 		pipeline, err := parseMultiBranchPipelineConfigXml(config)
 		if err != nil {
-			return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+			return nil, err
 		}
-		pipeline.Name = pipelineId
+		pipeline.Name = name
 		return &devopsv1alpha3.Pipeline{
 			Spec: devopsv1alpha3.PipelineSpec{
 				Type:                devopsv1alpha3.MultiBranchPipelineType,
 				MultiBranchPipeline: pipeline,
 			},
 		}, nil
-	default:
-		klog.Errorf("%+v", err)
-		return nil, restful.NewError(http.StatusBadRequest, err.Error())
+
+	case OrganizationFolderClass:
+		folder, err := parseOrganizationFolderConfigXml(config)
+		if err != nil {
+			return nil, err
+		}
+		folder.Name = name
+		return &devopsv1alpha3.Pipeline{
+			Spec: devopsv1alpha3.PipelineSpec{
+				Type:               devopsv1alpha3.OrganizationFolderPipelineType,
+				OrganizationFolder: folder,
+			},
+		}, nil
 	}
+	return nil, fmt.Errorf("unsupported job class %s", class)
 }
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+// ConfigFingerprint returns the fingerprint of a config.xml used by ApplyProjectPipeline
+func ConfigFingerprint(config string) string {
+	hash := sha256.Sum256([]byte(config))
+	return hex.EncodeToString(hash[:])
+}
+
+func (j *Jenkins) ApplyProjectPipeline(projectId string, pipeline *devopsv1alpha3.Pipeline, fingerprint string) (*devops.PipelineApplyResult, error) {
+	class, err := pipelineJobClass(pipeline)
+	if err != nil {
+		return nil, restful.NewError(http.StatusBadRequest, err.Error())
+	}
+	result := &devops.PipelineApplyResult{Pipeline: pipeline.Name}
+
+	job, err := j.GetJob(pipeline.Name, projectId)
+	if err != nil && devops.GetDevOpsStatusCode(err) != http.StatusNotFound {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	if job == nil {
+		if fingerprint != "" {
+			return nil, restful.NewError(http.StatusConflict, fmt.Sprintf("pipeline %s was deleted", pipeline.Name))
+		}
+		if _, err = j.CreateProjectPipeline(projectId, pipeline); err != nil {
+			return nil, err
+		}
+		result.Operation = devops.PipelineApplyCreated
+		return j.fillApplyFingerprint(projectId, result)
+	}
+
+	currentConfig, err := job.GetConfig()
+	if err != nil {
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	if fingerprint != "" && fingerprint != ConfigFingerprint(currentConfig) {
+		return nil, restful.NewError(http.StatusConflict,
+			fmt.Sprintf("pipeline %s was changed by others, please get the latest one and try again", pipeline.Name))
+	}
+
+	if job.Raw.Class != class {
+		// only the pipelines are recreated, the other jobs are never deleted
+		if !isPipelineJobClass(job.Raw.Class) {
+			return nil, restful.NewError(http.StatusConflict,
+				fmt.Sprintf("job name [%s] has been used by a job of class %s", pipeline.Name, job.Raw.Class))
+		}
+		// recreating drops the builds of the job, so it must be based on the config the caller has seen
+		if fingerprint == "" {
+			return nil, restful.NewError(http.StatusPreconditionRequired,
+				fmt.Sprintf("the fingerprint is required to recreate pipeline %s of a different type", pipeline.Name))
+		}
+		if _, err = j.DeleteJob(pipeline.Name, projectId); err != nil {
+			klog.Errorf("%+v", err)
+			return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+		}
+		if _, err = j.CreateProjectPipeline(projectId, pipeline); err != nil {
+			if _, restoreErr := j.CreateJobInFolder(currentConfig, pipeline.Name, projectId); restoreErr != nil {
+				klog.Errorf("failed to restore pipeline %s/%s: %+v", projectId, pipeline.Name, restoreErr)
+				return nil, restful.NewError(http.StatusInternalServerError,
+					fmt.Sprintf("failed to recreate pipeline %s: %v, and failed to restore it: %v", pipeline.Name, err, restoreErr))
+			}
+			return nil, err
+		}
+		result.Operation = devops.PipelineApplyRecreated
+		return j.fillApplyFingerprint(projectId, result)
+	}
+
+	current, err := parseJobConfig(class, pipeline.Name, currentConfig)
+	if err != nil {
+		return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	drift, err := diffPipeline(pipeline, current)
+	if err != nil {
+		return nil, restful.NewError(http.StatusInternalServerError, err.Error())
+	}
+	if len(drift.Diffs) == 0 {
+		result.Operation = devops.PipelineApplyUnchanged
+		result.Fingerprint = ConfigFingerprint(currentConfig)
+		return result, nil
+	}
+
+	config, err := updatedProjectPipelineConfig(projectId, currentConfig, pipeline)
+	if err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(http.StatusBadRequest, err.Error())
+	}
+	if err = job.UpdateConfig(config); err != nil {
+		klog.Errorf("%+v", err)
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	result.Operation = devops.PipelineApplyUpdated
+	return j.fillApplyFingerprint(projectId, result)
+}
+
+// fillApplyFingerprint sets the fingerprint of the config saved by Jenkins, which might be formatted by Jenkins
+func (j *Jenkins) fillApplyFingerprint(projectId string, result *devops.PipelineApplyResult) (*devops.PipelineApplyResult, error) {
+	job, err := j.GetJob(result.Pipeline, projectId)
+	if err != nil {
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	config, err := job.GetConfig()
+	if err != nil {
+		return nil, restful.NewError(devops.GetDevOpsStatusCode(err), err.Error())
+	}
+	result.Fingerprint = ConfigFingerprint(config)
+	return result, nil
+}
+
+// pipelineJobClass returns the class of the job for a pipeline
+func pipelineJobClass(pipeline *devopsv1alpha3.Pipeline) (string, error) {
+	switch pipeline.Spec.Type {
+	case devopsv1alpha3.NoScmPipelineType:
+		if pipeline.Spec.Pipeline != nil {
+			return WorkflowJobClass, nil
+		}
+	case devopsv1alpha3.MultiBranchPipelineType:
+		if pipeline.Spec.MultiBranchPipeline != nil {
+			return WorkflowMultiBranchProjectClass, nil
+		}
+	case devopsv1alpha3.OrganizationFolderPipelineType:
+		if pipeline.Spec.OrganizationFolder != nil {
+			return OrganizationFolderClass, nil
+		}
+	default:
+		return "", fmt.Errorf("error unsupport job type")
+	}
+	return "", fmt.Errorf("the spec of %s pipeline is missing", pipeline.Spec.Type)
+}
+
+// updatedProjectPipelineConfig returns the config of a job updated from the pipeline, the type of them must be the same
+func updatedProjectPipelineConfig(projectId, currentConfig string, pipeline *devopsv1alpha3.Pipeline) (string, error) {
+	switch pipeline.Spec.Type {
+	case devopsv1alpha3.NoScmPipelineType:
+		return updatePipelineConfigXml(currentConfig, pipeline.Spec.Pipeline)
+	case devopsv1alpha3.MultiBranchPipelineType:
+		return updateMultiBranchPipelineConfigXml(currentConfig, projectId, pipeline.Spec.MultiBranchPipeline)
+	default:
+		return createOrganizationFolderConfigXml(pipeline.Spec.OrganizationFolder)
+	}
+}
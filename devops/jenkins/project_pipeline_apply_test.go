@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+// newStatefulFakeJenkins starts a fake Jenkins which keeps the configs of the jobs in a project,
+// the jobs can be created, updated and deleted
+func newStatefulFakeJenkins(t *testing.T, projectId string, configs map[string]string) (*Jenkins, *[]string) {
+	var mutex sync.Mutex
+	operations := &[]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		prefix := "/job/" + projectId + "/"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		path := strings.TrimPrefix(r.URL.Path, prefix)
+		if path == "api/json" {
+			// the folder of the project lists all the jobs
+			var jobs []string
+			for name, config := range configs {
+				jobs = append(jobs, `{"_class":"`+configClass(config)+`","name":"`+name+`"}`)
+			}
+			_, _ = w.Write([]byte(`{"_class":"` + FolderClass + `","name":"` + projectId + `","jobs":[` + strings.Join(jobs, ",") + `]}`))
+			return
+		}
+		if r.Method == http.MethodPost && path == "createItem" {
+			body, _ := io.ReadAll(r.Body)
+			configs[r.URL.Query().Get("name")] = string(body)
+			*operations = append(*operations, "create "+r.URL.Query().Get("name"))
+			return
+		}
+
+		name := strings.Split(strings.TrimPrefix(path, "job/"), "/")[0]
+		config, ok := configs[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/doDelete"):
+			delete(configs, name)
+			*operations = append(*operations, "delete "+name)
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/config.xml"):
+			body, _ := io.ReadAll(r.Body)
+			configs[name] = string(body)
+			*operations = append(*operations, "update "+name)
+		case strings.HasSuffix(path, "/config.xml/"):
+			_, _ = w.Write([]byte(config))
+		case strings.HasSuffix(path, "/api/json"):
+			_, _ = w.Write([]byte(`{"_class":"` + configClass(config) + `","name":"` + name + `"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return CreateJenkins(nil, server.URL, 0, "admin", "password"), operations
+}
+
+// configClass returns the class of the job, the root tag is the class except the one of a pipeline.
+// The job with a malformed config is taken as a pipeline
+func configClass(config string) string {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(replaceXmlVersion(config, "1.1", "1.0")); err != nil || doc.Root() == nil {
+		return WorkflowJobClass
+	}
+	class := doc.Root().Tag
+	if class == FlowTag {
+		class = WorkflowJobClass
+	}
+	return class
+}
+
+func newFakeApplyPipeline(jenkinsfile string) *devopsv1alpha3.Pipeline {
+	return &devopsv1alpha3.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "deploy"},
+		Spec: devopsv1alpha3.PipelineSpec{
+			Type:     devopsv1alpha3.NoScmPipelineType,
+			Pipeline: &devopsv1alpha3.NoScmPipeline{Name: "deploy", Jenkinsfile: jenkinsfile},
+		},
+	}
+}
+
+func TestApplyProjectPipeline(t *testing.T) {
+	configs := map[string]string{}
+	jenkins, operations := newStatefulFakeJenkins(t, "fake-project", configs)
+
+	created, err := jenkins.ApplyProjectPipeline("fake-project", newFakeApplyPipeline("node{echo 'hello'}"), "")
+	assert.Nil(t, err)
+	assert.Equal(t, devops.PipelineApplyCreated, created.Operation)
+	assert.Equal(t, ConfigFingerprint(configs["deploy"]), created.Fingerprint)
+
+	unchanged, err := jenkins.ApplyProjectPipeline("fake-project", newFakeApplyPipeline("node{echo 'hello'}"), created.Fingerprint)
+	assert.Nil(t, err)
+	assert.False(t, unchanged.Changed())
+	assert.Equal(t, created.Fingerprint, unchanged.Fingerprint)
+
+	updated, err := jenkins.ApplyProjectPipeline("fake-project", newFakeApplyPipeline("node{echo 'world'}"), created.Fingerprint)
+	assert.Nil(t, err)
+	assert.Equal(t, devops.PipelineApplyUpdated, updated.Operation)
+	assert.NotEqual(t, created.Fingerprint, updated.Fingerprint)
+	assert.Contains(t, configs["deploy"], "echo &apos;world&apos;")
+
+	t.Run("conflict", func(t *testing.T) {
+		_, err := jenkins.ApplyProjectPipeline("fake-project", newFakeApplyPipeline("node{echo 'again'}"), created.Fingerprint)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, http.StatusConflict, err.(restful.ServiceError).Code)
+		}
+		assert.Contains(t, configs["deploy"], "echo &apos;world&apos;")
+	})
+
+	t.Run("type changed", func(t *testing.T) {
+		pipeline := &devopsv1alpha3.Pipeline{
+			ObjectMeta: metav1.ObjectMeta{Name: "deploy"},
+			Spec: devopsv1alpha3.PipelineSpec{
+				Type: devopsv1alpha3.MultiBranchPipelineType,
+				MultiBranchPipeline: &devopsv1alpha3.MultiBranchPipeline{
+					Name:       "deploy",
+					SourceType: devopsv1alpha3.SourceTypeGit,
+					GitSource:  &devopsv1alpha3.GitSource{Url: "https://github.com/kubesphere/ks-devops"},
+				},
+			},
+		}
+		// the fingerprint is required to recreate the pipeline
+		_, err := jenkins.ApplyProjectPipeline("fake-project", pipeline, "")
+		if assert.NotNil(t, err) {
+			assert.Equal(t, http.StatusPreconditionRequired, err.(restful.ServiceError).Code)
+		}
+
+		recreated, err := jenkins.ApplyProjectPipeline("fake-project", pipeline, updated.Fingerprint)
+		assert.Nil(t, err)
+		assert.Equal(t, devops.PipelineApplyRecreated, recreated.Operation)
+		assert.True(t, strings.Contains(configs["deploy"], WorkflowMultiBranchProjectClass))
+
+		// the pipeline is restored if it can not be recreated
+		current := configs["deploy"]
+		invalid := newFakeApplyPipeline("")
+		invalid.Spec.Pipeline.ScmDefinition = &devopsv1alpha3.ScmFlowDefinition{SourceType: devopsv1alpha3.SourceTypeGit}
+		_, err = jenkins.ApplyProjectPipeline("fake-project", invalid, recreated.Fingerprint)
+		assert.NotNil(t, err)
+		assert.Equal(t, current, configs["deploy"])
+	})
+
+	assert.Equal(t, []string{"create deploy", "update deploy", "delete deploy", "create deploy",
+		"delete deploy", "create deploy"}, *operations)
+
+	t.Run("deleted", func(t *testing.T) {
+		delete(configs, "deploy")
+		_, err := jenkins.ApplyProjectPipeline("fake-project", newFakeApplyPipeline("node{echo 'hello'}"), updated.Fingerprint)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, http.StatusConflict, err.(restful.ServiceError).Code)
+		}
+	})
+
+	t.Run("not a pipeline", func(t *testing.T) {
+		configs["legacy"] = "<project/>"
+		pipeline := newFakeApplyPipeline("node{echo 'hello'}")
+		pipeline.Name = "legacy"
+		_, err := jenkins.ApplyProjectPipeline("fake-project", pipeline, "")
+		if assert.NotNil(t, err) {
+			assert.Equal(t, http.StatusConflict, err.(restful.ServiceError).Code)
+		}
+		assert.Equal(t, "<project/>", configs["legacy"])
+	})
+
+	t.Run("invalid spec", func(t *testing.T) {
+		pipeline := newFakeApplyPipeline("")
+		pipeline.Spec.Pipeline = nil
+		_, err := jenkins.ApplyProjectPipeline("fake-project", pipeline, "")
+		if assert.NotNil(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(restful.ServiceError).Code)
+		}
+	})
+}
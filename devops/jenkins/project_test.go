@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkins
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opswave/go-jenkins/devops"
+)
+
+func TestListDevOpsProjects(t *testing.T) {
+	jenkins, requests := newFakeJenkinsWithResponses(t, http.StatusOK, map[string]string{
+		"/api/json": `{"jobs":[
+			{"_class":"com.cloudbees.hudson.plugins.folder.Folder","name":"fake-project","displayName":"Fake Project","description":"for test","jobs":[
+				{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"deploy"},
+				{"_class":"org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject","name":"build"},
+				{"_class":"hudson.model.FreeStyleProject","name":"legacy"}]},
+			{"_class":"com.cloudbees.hudson.plugins.folder.Folder","name":"empty-project","jobs":[]},
+			{"_class":"hudson.model.FreeStyleProject","name":"legacy"}]}`,
+	})
+	projects, err := jenkins.ListDevOpsProjects()
+	assert.Nil(t, err)
+	assert.Equal(t, []*devops.DevOpsProject{
+		{Name: "fake-project", DisplayName: "Fake Project", Description: "for test", PipelineCount: 2},
+		{Name: "empty-project"},
+	}, projects)
+	if assert.Equal(t, 1, len(*requests)) {
+		assert.Contains(t, (*requests)[0].query, "tree=")
+	}
+}
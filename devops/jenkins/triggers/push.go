@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"strconv"
+
+	"github.com/beevik/etree"
+
+	"github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+const (
+	// GithubPushTriggerTag is the tag of the trigger of the GitHub plugin
+	GithubPushTriggerTag = "com.cloudbees.jenkins.GitHubPushTrigger"
+	// GitlabPushTriggerTag is the tag of the trigger of the GitLab plugin
+	GitlabPushTriggerTag = "com.dabsquared.gitlabjenkins.GitLabPushTrigger"
+)
+
+// CreateGithubPushTriggerXML creates the xml element for GitHubPushTrigger, the existing one is replaced in place
+func CreateGithubPushTriggerXML(parent *etree.Element, trigger *v1alpha3.GithubPushTrigger) (ele *etree.Element) {
+	if trigger == nil || parent == nil {
+		return
+	}
+	ele = replaceOrCreateElement(parent, GithubPushTriggerTag)
+	ele.CreateAttr("plugin", "github")
+	ele.CreateElement("spec")
+	return
+}
+
+// ParseGithubPushTriggerXML parse GitHubPushTrigger xml structure into go struct GithubPushTrigger
+func ParseGithubPushTriggerXML(ele *etree.Element) (trigger *v1alpha3.GithubPushTrigger) {
+	if ele == nil {
+		return
+	}
+	return &v1alpha3.GithubPushTrigger{}
+}
+
+// CreateGitlabPushTriggerXML creates the xml element for GitLabPushTrigger, the existing one is replaced in place
+func CreateGitlabPushTriggerXML(parent *etree.Element, trigger *v1alpha3.GitlabPushTrigger) (ele *etree.Element) {
+	if trigger == nil || parent == nil {
+		return
+	}
+	branchFilterType := "All"
+	if trigger.IncludeBranches != "" || trigger.ExcludeBranches != "" {
+		branchFilterType = "NameBasedFilter"
+	}
+
+	ele = replaceOrCreateElement(parent, GitlabPushTriggerTag)
+	ele.CreateAttr("plugin", "gitlab-plugin")
+	ele.CreateElement("spec")
+	ele.CreateElement("triggerOnPush").SetText(strconv.FormatBool(trigger.TriggerOnPush))
+	ele.CreateElement("triggerOnMergeRequest").SetText(strconv.FormatBool(trigger.TriggerOnMergeRequest))
+	ele.CreateElement("triggerOnAcceptedMergeRequest").SetText("false")
+	ele.CreateElement("triggerOnClosedMergeRequest").SetText("false")
+	ele.CreateElement("triggerOpenMergeRequestOnPush").SetText("never")
+	ele.CreateElement("triggerOnNoteRequest").SetText("false")
+	ele.CreateElement("ciSkip").SetText("true")
+	ele.CreateElement("setBuildDescription").SetText("true")
+	ele.CreateElement("branchFilterType").SetText(branchFilterType)
+	ele.CreateElement("includeBranchesSpec").SetText(trigger.IncludeBranches)
+	ele.CreateElement("excludeBranchesSpec").SetText(trigger.ExcludeBranches)
+	ele.CreateElement("secretToken").SetText(trigger.SecretToken)
+	return
+}
+
+// ParseGitlabPushTriggerXML parse GitLabPushTrigger xml structure into go struct GitlabPushTrigger
+func ParseGitlabPushTriggerXML(ele *etree.Element) (trigger *v1alpha3.GitlabPushTrigger) {
+	if ele == nil {
+		return
+	}
+	return &v1alpha3.GitlabPushTrigger{
+		TriggerOnPush:         getElementTextAsBoolean(ele, "triggerOnPush"),
+		TriggerOnMergeRequest: getElementTextAsBoolean(ele, "triggerOnMergeRequest"),
+		IncludeBranches:       getElementText(ele, "includeBranchesSpec"),
+		ExcludeBranches:       getElementText(ele, "excludeBranchesSpec"),
+		SecretToken:           getElementText(ele, "secretToken"),
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"strconv"
+
+	"github.com/beevik/etree"
+
+	"github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+// SCMTriggerTag is the tag of the trigger which polls the scm
+const SCMTriggerTag = "hudson.triggers.SCMTrigger"
+
+// CreateScmTriggerXML creates the xml element for SCMTrigger, the existing one is replaced in place
+func CreateScmTriggerXML(parent *etree.Element, trigger *v1alpha3.ScmTrigger) (ele *etree.Element) {
+	if trigger == nil || parent == nil {
+		return
+	}
+	ele = replaceOrCreateElement(parent, SCMTriggerTag)
+	ele.CreateElement("spec").SetText(trigger.Schedule)
+	ele.CreateElement("ignorePostCommitHooks").SetText(strconv.FormatBool(trigger.IgnorePostCommitHooks))
+	return
+}
+
+// ParseScmTriggerXML parse SCMTrigger xml structure into go struct ScmTrigger
+func ParseScmTriggerXML(ele *etree.Element) (trigger *v1alpha3.ScmTrigger) {
+	if ele == nil {
+		return
+	}
+	return &v1alpha3.ScmTrigger{
+		Schedule:              getElementText(ele, "spec"),
+		IgnorePostCommitHooks: getElementTextAsBoolean(ele, "ignorePostCommitHooks"),
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+
+	"github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+// ReverseBuildTriggerTag is the tag of the trigger which starts a build when the upstream builds are completed
+const ReverseBuildTriggerTag = "jenkins.triggers.ReverseBuildTrigger"
+
+// upstreamThresholds maps the result threshold to its ordinal and color in Jenkins
+var upstreamThresholds = map[string]struct {
+	ordinal int
+	color   string
+}{
+	v1alpha3.UpstreamThresholdSuccess:  {0, "BLUE"},
+	v1alpha3.UpstreamThresholdUnstable: {1, "YELLOW"},
+	v1alpha3.UpstreamThresholdFailure:  {2, "RED"},
+}
+
+// CreateUpstreamTriggerXML creates the xml element for ReverseBuildTrigger, the existing one is replaced in place
+func CreateUpstreamTriggerXML(parent *etree.Element, trigger *v1alpha3.UpstreamTrigger) (ele *etree.Element) {
+	if trigger == nil || parent == nil {
+		return
+	}
+	threshold := trigger.Threshold
+	if _, ok := upstreamThresholds[threshold]; !ok {
+		threshold = v1alpha3.UpstreamThresholdSuccess
+	}
+
+	ele = replaceOrCreateElement(parent, ReverseBuildTriggerTag)
+	ele.CreateElement("spec")
+	ele.CreateElement("upstreamProjects").SetText(strings.Join(trigger.Projects, ", "))
+	thresholdEle := ele.CreateElement("threshold")
+	thresholdEle.CreateElement("name").SetText(threshold)
+	thresholdEle.CreateElement("ordinal").SetText(strconv.Itoa(upstreamThresholds[threshold].ordinal))
+	thresholdEle.CreateElement("color").SetText(upstreamThresholds[threshold].color)
+	thresholdEle.CreateElement("completeBuild").SetText("true")
+	return
+}
+
+// ParseUpstreamTriggerXML parse ReverseBuildTrigger xml structure into go struct UpstreamTrigger
+func ParseUpstreamTriggerXML(ele *etree.Element) (trigger *v1alpha3.UpstreamTrigger) {
+	if ele == nil {
+		return
+	}
+	trigger = &v1alpha3.UpstreamTrigger{}
+	for _, project := range strings.Split(getElementText(ele, "upstreamProjects"), ",") {
+		if project = strings.TrimSpace(project); project != "" {
+			trigger.Projects = append(trigger.Projects, project)
+		}
+	}
+	if thresholdEle := ele.SelectElement("threshold"); thresholdEle != nil {
+		trigger.Threshold = getElementText(thresholdEle, "name")
+	}
+	return
+}
+
+// replaceOrCreateElement returns an empty element with the tag, it takes the place of the existing one
+// to keep the order of the elements
+func replaceOrCreateElement(parent *etree.Element, tag string) *etree.Element {
+	ele := etree.NewElement(tag)
+	if existing := parent.SelectElement(tag); existing != nil {
+		index := existing.Index()
+		parent.RemoveChild(existing)
+		parent.InsertChildAt(index, ele)
+	} else {
+		parent.AddChild(ele)
+	}
+	return ele
+}
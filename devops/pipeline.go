@@ -721,6 +721,8 @@ type CheckScript struct {
 type CronData struct {
 	PipelineName string `json:"pipelineName,omitempty" description:"Pipeline name, if pipeline haven't created, not required'"`
 	Cron         string `json:"cron" description:"Cron script data."`
+	Count        int    `json:"count,omitempty" description:"The count of next run times to return, default 5, at most 100"`
+	UseJenkins   bool   `json:"useJenkins,omitempty" description:"Check the cron by Jenkins instead of parsing it locally"`
 }
 
 type CheckCronRes struct {
@@ -728,6 +730,8 @@ type CheckCronRes struct {
 	Message  string `json:"message,omitempty" description:"message"`
 	LastTime string `json:"lastTime,omitempty" description:"last run time."`
 	NextTime string `json:"nextTime,omitempty" description:"next run time."`
+	// NextTimes is only given when the cron is parsed locally
+	NextTimes []string `json:"nextTimes,omitempty" description:"next run times."`
 }
 
 // GetPipelineRun
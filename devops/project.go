@@ -16,6 +16,12 @@ limitations under the License.
 
 package devops
 
+import (
+	"io"
+
+	v1 "k8s.io/api/core/v1"
+)
+
 /*
 *
 project operator, providing API for creating/getting/deleting projects
@@ -26,4 +32,131 @@ type ProjectOperator interface {
 	CreateDevOpsProject(projectId string) (string, error)
 	DeleteDevOpsProject(projectId string) error
 	GetDevOpsProject(projectId string) (string, error)
+	// ListDevOpsProjects returns the folders of the projects with the count of their pipelines
+	ListDevOpsProjects() ([]*DevOpsProject, error)
+	// CheckDevOpsProjectDeletion reports what would be lost if a project is deleted
+	CheckDevOpsProjectDeletion(projectId string) (*ProjectDeletionReport, error)
+	// DeleteDevOpsProjectWithOptions deletes a project in the mode of options, DeleteDevOpsProject is the same as
+	// the force mode
+	DeleteDevOpsProjectWithOptions(projectId string, options *ProjectDeleteOptions) (*ProjectDeletionReport, error)
+	// CreateDevOpsProjectWithConfig creates a project whose folder is configured by config
+	CreateDevOpsProjectWithConfig(projectId string, config *ProjectFolderConfig) (string, error)
+	// GetDevOpsProjectConfig returns the configuration of the folder of a project
+	GetDevOpsProjectConfig(projectId string) (*ProjectFolderConfig, error)
+	// UpdateDevOpsProjectConfig replaces the configuration of the folder of a project, the other properties of the
+	// folder are kept
+	UpdateDevOpsProjectConfig(projectId string, config *ProjectFolderConfig) (*ProjectFolderConfig, error)
+	// ListProjectLibraries returns the pipeline shared libraries of a project
+	ListProjectLibraries(projectId string) ([]*PipelineLibrary, error)
+	// CreateProjectLibrary adds a pipeline shared library to a project, the name of the library must be unique
+	CreateProjectLibrary(projectId string, library *PipelineLibrary) (*PipelineLibrary, error)
+	// UpdateProjectLibrary updates the pipeline shared library which has the same name
+	UpdateProjectLibrary(projectId string, library *PipelineLibrary) (*PipelineLibrary, error)
+	// DeleteProjectLibrary removes a pipeline shared library from a project
+	DeleteProjectLibrary(projectId, name string) error
+	// ExportProject writes the folder, pipelines and credential metadata of a project into a tar.gz archive
+	ExportProject(projectId string, writer io.Writer, options *ProjectExportOptions) (*ProjectArchiveManifest, error)
+	// ImportProject restores an archive written by ExportProject as the project, the project ID of the archive is remapped
+	ImportProject(projectId string, reader io.Reader, options *ProjectImportOptions) (*ProjectImportResult, error)
+}
+
+// ProjectArchiveVersion is the version of the archive format written by ExportProject
+const ProjectArchiveVersion = "v1"
+
+// ProjectExportOptions controls what is written into a project archive
+type ProjectExportOptions struct {
+	// EncryptionKey is an AES key of 16, 24 or 32 bytes, the secrets are exported only if it is set
+	EncryptionKey []byte
+	// Secrets are the secrets of the credentials in the project
+	Secrets []*v1.Secret
+	// IncludeBuilds exports the build history metadata of the pipelines
+	IncludeBuilds bool
+}
+
+// ProjectImportOptions controls how a project archive is restored
+type ProjectImportOptions struct {
+	// EncryptionKey is the key used to export the archive, the secrets are not restored without it
+	EncryptionKey []byte
+}
+
+// ProjectArchiveManifest describes the content of a project archive
+type ProjectArchiveManifest struct {
+	Version          string                   `json:"version" description:"Version of the archive format"`
+	ProjectId        string                   `json:"project_id" description:"ID of the exported project"`
+	Pipelines        []ProjectArchivePipeline `json:"pipelines,omitempty" description:"Pipelines in the archive"`
+	Domains          []*CredentialDomain      `json:"domains,omitempty" description:"Credential domains of the project except the global one"`
+	Credentials      []*Credential            `json:"credentials,omitempty" description:"Metadata of the credentials, the secrets are not included"`
+	EncryptedSecrets bool                     `json:"encrypted_secrets,omitempty" description:"The archive contains the encrypted secrets of the credentials"`
+	IncludeBuilds    bool                     `json:"include_builds,omitempty" description:"The archive contains the build history metadata"`
+}
+
+// ProjectArchivePipeline is a pipeline in a project archive
+type ProjectArchivePipeline struct {
+	Name  string `json:"name" description:"Name of the pipeline"`
+	Class string `json:"class" description:"Class of the job in Jenkins"`
+}
+
+// ProjectImportResult describes what was restored from a project archive
+type ProjectImportResult struct {
+	ProjectId          string   `json:"project_id" description:"ID of the restored project"`
+	Pipelines          []string `json:"pipelines,omitempty" description:"Restored pipelines"`
+	Credentials        []string `json:"credentials,omitempty" description:"Restored credentials"`
+	SkippedCredentials []string `json:"skipped_credentials,omitempty" description:"Credentials which are not restored because their secrets are not in the archive or the key is not given"`
+}
+
+// the modes of deleting a project
+const (
+	// ProjectDeleteModeRefuse refuses to delete a project which has running builds or queued items
+	ProjectDeleteModeRefuse = "refuse"
+	// ProjectDeleteModeAbort aborts the running builds and cancels the queued items before deleting a project
+	ProjectDeleteModeAbort = "abort"
+	// ProjectDeleteModeForce deletes a project without any check
+	ProjectDeleteModeForce = "force"
+)
+
+// DevOpsProject is the folder of a DevOps project
+type DevOpsProject struct {
+	Name          string `json:"name" description:"ID of the project"`
+	DisplayName   string `json:"display_name,omitempty" description:"Display name of the folder"`
+	Description   string `json:"description,omitempty" description:"Description of the folder"`
+	PipelineCount int    `json:"pipeline_count" description:"Count of the pipelines in the project"`
+}
+
+// ProjectDeleteOptions controls how a project is deleted
+type ProjectDeleteOptions struct {
+	// Mode is one of refuse, abort and force, it is refuse by default
+	Mode string
+	// Archive receives the archive of the project written by ExportProject before the project is deleted,
+	// the project is kept if the archive cannot be written
+	Archive io.Writer
+	// ArchiveOptions is used to write the archive
+	ArchiveOptions *ProjectExportOptions
+}
+
+// ProjectDeletionReport describes what would be lost if a project is deleted
+type ProjectDeletionReport struct {
+	ProjectId     string                 `json:"project_id" description:"ID of the project"`
+	RunningBuilds []*ProjectRunningBuild `json:"running_builds,omitempty" description:"Running builds in the project"`
+	QueuedItems   []*ProjectQueuedItem   `json:"queued_items,omitempty" description:"Items of the project waiting in the build queue"`
+	Credentials   []string               `json:"credentials,omitempty" description:"Credentials in the project"`
+	Deleted       bool                   `json:"deleted,omitempty" description:"The project is deleted"`
+}
+
+// Blocked returns true if there are running builds or queued items in the project
+func (r *ProjectDeletionReport) Blocked() bool {
+	return len(r.RunningBuilds) > 0 || len(r.QueuedItems) > 0
+}
+
+// ProjectRunningBuild is a running build in a project
+type ProjectRunningBuild struct {
+	Pipeline string `json:"pipeline" description:"Path of the job in the project, such as pipeline or pipeline/branch"`
+	Number   int    `json:"number" description:"Number of the build"`
+	URL      string `json:"url,omitempty" description:"URL of the build"`
+}
+
+// ProjectQueuedItem is an item of a project in the build queue
+type ProjectQueuedItem struct {
+	Id       int64  `json:"id" description:"ID of the queue item"`
+	Pipeline string `json:"pipeline" description:"Path of the job in the project, such as pipeline or pipeline/branch"`
+	Why      string `json:"why,omitempty" description:"The reason why the item is waiting"`
 }
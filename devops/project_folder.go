@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package devops
+
+import "github.com/opswave/go-jenkins/devops/v1alpha3"
+
+// the types of the health metrics of a folder
+const (
+	// WorstChildHealthMetric reports the worst health of the jobs in the folder
+	WorstChildHealthMetric = "worst_child"
+	// AverageChildHealthMetric reports the average health of the jobs in the folder
+	AverageChildHealthMetric = "average_child"
+)
+
+// ProjectFolderConfig is the configuration of the folder of a DevOps project
+type ProjectFolderConfig struct {
+	DisplayName    string                `json:"display_name,omitempty" description:"Display name of the folder"`
+	Description    string                `json:"description,omitempty" description:"Description of the folder"`
+	Environment    []*FolderProperty     `json:"environment,omitempty" description:"Environment variables which are available for all the pipelines in the folder"`
+	Libraries      []*PipelineLibrary    `json:"libraries,omitempty" description:"Pipeline shared libraries of the folder"`
+	DockerLabel    string                `json:"docker_label,omitempty" description:"Agent label of the docker based declarative pipelines"`
+	DockerRegistry *DockerRegistry       `json:"docker_registry,omitempty" description:"Docker registry of the docker based declarative pipelines"`
+	HealthMetrics  []*FolderHealthMetric `json:"health_metrics,omitempty" description:"Health metrics of the folder"`
+	AllowedLabels  []string              `json:"allowed_labels,omitempty" description:"Agent labels which the pipelines in the folder are allowed to run on"`
+}
+
+// FolderProperty is an environment variable of a folder
+type FolderProperty struct {
+	Key   string `json:"key" description:"Name of the environment variable"`
+	Value string `json:"value" description:"Value of the environment variable"`
+}
+
+// PipelineLibrary is a pipeline shared library
+type PipelineLibrary struct {
+	Name                 string `json:"name" description:"Name of the library, used in @Library"`
+	DefaultVersion       string `json:"default_version,omitempty" description:"Default version of the library, such as a branch or tag"`
+	Implicit             bool   `json:"implicit,omitempty" description:"Load the library implicitly"`
+	AllowVersionOverride bool   `json:"allow_version_override,omitempty" description:"Allow the default version to be overridden"`
+	IncludeInChangesets  bool   `json:"include_in_changesets,omitempty" description:"Include the changes of the library in the changesets of the builds"`
+	// Retriever is kept as it is when it is empty in an update
+	Retriever *LibraryRetriever `json:"retriever,omitempty" description:"Retriever which fetches the library from a SCM source"`
+}
+
+// LibraryRetriever retrieves a pipeline shared library from a SCM source
+type LibraryRetriever struct {
+	SourceType   string                 `json:"source_type" description:"Type of the SCM source, git, github or gitlab"`
+	GitSource    *v1alpha3.GitSource    `json:"git_source,omitempty" description:"Git source of the library"`
+	GithubSource *v1alpha3.GithubSource `json:"github_source,omitempty" description:"GitHub source of the library"`
+	GitlabSource *v1alpha3.GitlabSource `json:"gitlab_source,omitempty" description:"GitLab source of the library"`
+	LibraryPath  string                 `json:"library_path,omitempty" description:"Path of the library in the repository, the root by default"`
+	Clone        bool                   `json:"clone,omitempty" description:"Clone the whole repository instead of checking out the library"`
+}
+
+// DockerRegistry is a docker registry with the credential to access it
+type DockerRegistry struct {
+	URL          string `json:"url,omitempty" description:"URL of the docker registry"`
+	CredentialId string `json:"credential_id,omitempty" description:"ID of the credential of the docker registry"`
+}
+
+// FolderHealthMetric is a health metric of a folder
+type FolderHealthMetric struct {
+	Type         string `json:"type" description:"Type of the health metric, worst_child or average_child"`
+	NonRecursive bool   `json:"non_recursive,omitempty" description:"Only check the direct children, it is available for worst_child"`
+}
@@ -23,4 +23,137 @@ type ProjectPipelineOperator interface {
 	DeleteProjectPipeline(projectId string, pipelineId string) (string, error)
 	UpdateProjectPipeline(projectId string, pipeline *v1alpha3.Pipeline) (string, error)
 	GetProjectPipelineConfig(projectId, pipelineId string) (*v1alpha3.Pipeline, error)
+	// GetOrganizationFolderRepositories returns the names of the repositories matched by an organization folder
+	GetOrganizationFolderRepositories(folder *v1alpha3.OrganizationFolder, httpParameters *HttpParameters) ([]string, error)
+	// DiffProjectPipeline compares a pipeline with the config of its job in Jenkins
+	DiffProjectPipeline(projectId string, pipeline *v1alpha3.Pipeline) (*PipelineDrift, error)
+	// ScanProjectPipelineDrift compares the pipelines of a project with the jobs in Jenkins,
+	// the jobs which have no pipeline are reported as unmanaged
+	ScanProjectPipelineDrift(projectId string, pipelines []*v1alpha3.Pipeline) ([]*PipelineDrift, error)
+	// DiscoverProjectJobs walks the folder tree of a project and converts the supported jobs to pipelines
+	DiscoverProjectJobs(projectId string) ([]*DiscoveredJob, error)
+	// ApplyProjectPipeline creates the job of a pipeline or updates it if needed. The job is recreated if its type
+	// is changed, which requires the fingerprint. If fingerprint is not empty, the apply is refused with 409 when the
+	// current config does not match it
+	ApplyProjectPipeline(projectId string, pipeline *v1alpha3.Pipeline, fingerprint string) (*PipelineApplyResult, error)
+	// ListDownstreamPipelines returns the pipelines of a project which are triggered, directly or transitively,
+	// by the completion of the given pipeline through their upstream triggers
+	ListDownstreamPipelines(projectId, pipelineId string) ([]*PipelineDependency, error)
+	// MoveProjectPipeline moves a pipeline to another project by the move action of Jenkins, so its builds are kept,
+	// its config history is moved as well
+	MoveProjectPipeline(projectId, pipelineId, targetProjectId string, options *PipelineRelocateOptions) (*PipelineRelocateResult, error)
+	// RenameProjectPipeline renames a pipeline in its project, its builds and config history are kept
+	RenameProjectPipeline(projectId, pipelineId, newName string) (*PipelineRelocateResult, error)
+	// CopyProjectPipeline creates a pipeline with the config of the given one, the copy could be in another project.
+	// The builds are not copied
+	CopyProjectPipeline(projectId, pipelineId, targetProjectId, targetName string, options *PipelineRelocateOptions) (*PipelineRelocateResult, error)
+	// EnableProjectPipeline enables a pipeline or a multi-branch pipeline, the reason of disabling is removed
+	// from its description
+	EnableProjectPipeline(projectId, pipelineId string) error
+	// DisableProjectPipeline disables a pipeline or a multi-branch pipeline, the reason is recorded in its
+	// description if it is not empty
+	DisableProjectPipeline(projectId, pipelineId, reason string) error
+	// ToggleProjectPipelines disables or enables all the pipelines and multi-branch pipelines of a project, the
+	// results of the toggled ones are returned with the error if it fails in the middle
+	ToggleProjectPipelines(projectId string, disabled bool, reason string) ([]*PipelineToggleResult, error)
+}
+
+// PipelineToggleResult is the state of a pipeline after ToggleProjectPipelines
+type PipelineToggleResult struct {
+	Pipeline string `json:"pipeline" description:"Name of the pipeline"`
+	Disabled bool   `json:"disabled" description:"Whether the pipeline is disabled"`
+	Changed  bool   `json:"changed" description:"Whether the pipeline was changed, it is false if the pipeline was already in the state with the same reason"`
+}
+
+// PipelineRelocateOptions controls how the references of a pipeline are handled when it is moved or copied
+type PipelineRelocateOptions struct {
+	// CredentialMapping replaces the credential IDs referenced by the pipeline, the key is the ID in the source project
+	CredentialMapping map[string]string `json:"credential_mapping,omitempty" description:"Replacements of the credential IDs referenced by the pipeline, keyed by the ID in the source project"`
+}
+
+// PipelineRelocateResult is the result of moving, renaming or copying a pipeline
+type PipelineRelocateResult struct {
+	ProjectId string `json:"project_id" description:"Project which the pipeline is in after the operation"`
+	Pipeline  string `json:"pipeline" description:"Name of the pipeline after the operation"`
+	// MissingCredentials could be global credentials, which are not stored in projects
+	MissingCredentials []string `json:"missing_credentials,omitempty" description:"Credentials referenced by the pipeline but not found in its project"`
+	UpdatedTriggers    []string `json:"updated_triggers,omitempty" description:"Full names of the multi-branch pipelines whose triggers were updated to the new name"`
+}
+
+// PipelineDependency is a pipeline triggered by the completion of its upstream pipeline
+type PipelineDependency struct {
+	Pipeline  string `json:"pipeline" description:"Name of the triggered pipeline"`
+	Upstream  string `json:"upstream" description:"Name of the pipeline whose completion triggers the pipeline"`
+	Threshold string `json:"threshold" description:"The worst result of the upstream build which triggers the pipeline"`
+	Depth     int    `json:"depth" description:"Distance from the given pipeline, it is 1 if the pipeline is triggered by the given one directly"`
+}
+
+// PipelineApplyOperation is what ApplyProjectPipeline did to the job of a pipeline
+type PipelineApplyOperation string
+
+const (
+	PipelineApplyCreated   PipelineApplyOperation = "created"
+	PipelineApplyUpdated   PipelineApplyOperation = "updated"
+	PipelineApplyRecreated PipelineApplyOperation = "recreated"
+	PipelineApplyUnchanged PipelineApplyOperation = "unchanged"
+)
+
+// PipelineApplyResult is the result of ApplyProjectPipeline
+type PipelineApplyResult struct {
+	Pipeline    string                 `json:"pipeline" description:"Name of the pipeline"`
+	Operation   PipelineApplyOperation `json:"operation" description:"What was done to the job, such as created/updated/recreated/unchanged"`
+	Fingerprint string                 `json:"fingerprint" description:"Fingerprint of the config after the apply, pass it to the next apply to detect concurrent changes"`
+}
+
+// Changed returns true if the job was changed by the apply
+func (r *PipelineApplyResult) Changed() bool {
+	return r.Operation != PipelineApplyUnchanged
+}
+
+// DiscoveredJobKind is the classification of an item found in a folder tree
+type DiscoveredJobKind string
+
+const (
+	DiscoveredJobKindPipeline            DiscoveredJobKind = "pipeline"
+	DiscoveredJobKindMultiBranchPipeline DiscoveredJobKind = "multi_branch_pipeline"
+	DiscoveredJobKindOrganizationFolder  DiscoveredJobKind = "organization_folder"
+	DiscoveredJobKindFolder              DiscoveredJobKind = "folder"
+	DiscoveredJobKindFreestyle           DiscoveredJobKind = "freestyle"
+	DiscoveredJobKindUnsupported         DiscoveredJobKind = "unsupported"
+)
+
+// DiscoveredJob is an item found in the folder tree of a project
+type DiscoveredJob struct {
+	Path        string             `json:"path" description:"Slash separated path of the item relative to the project"`
+	Class       string             `json:"class" description:"Class of the item in Jenkins"`
+	Kind        DiscoveredJobKind  `json:"kind" description:"Classification of the item"`
+	Pipeline    *v1alpha3.Pipeline `json:"pipeline,omitempty" description:"Pipeline converted from the item, it is empty if the item is not supported"`
+	LossyFields []string           `json:"lossy_fields,omitempty" description:"Slash separated paths of the config elements which cannot be represented by the pipeline"`
+	Reason      string             `json:"reason,omitempty" description:"Why the item cannot be converted"`
+}
+
+// Supported returns true if the item was converted to a pipeline
+func (j *DiscoveredJob) Supported() bool {
+	return j.Pipeline != nil
+}
+
+// PipelineDrift describes how a job in Jenkins diverges from its pipeline
+type PipelineDrift struct {
+	Pipeline  string              `json:"pipeline" description:"Name of the pipeline"`
+	Missing   bool                `json:"missing,omitempty" description:"The pipeline has no job in Jenkins"`
+	Unmanaged bool                `json:"unmanaged,omitempty" description:"The job in Jenkins has no pipeline"`
+	Diffs     []PipelineFieldDiff `json:"diffs,omitempty" description:"Fields which differ between the pipeline and the job"`
+	Error     string              `json:"error,omitempty" description:"Why the job could not be read, its differences are unknown"`
+}
+
+// Drifted returns true if the job in Jenkins does not match the pipeline
+func (d *PipelineDrift) Drifted() bool {
+	return d.Missing || d.Unmanaged || len(d.Diffs) > 0
+}
+
+// PipelineFieldDiff is a field which differs between a pipeline and its job
+type PipelineFieldDiff struct {
+	Field   string `json:"field" description:"Path of the field in the pipeline spec, such as Pipeline.Jenkinsfile"`
+	Desired string `json:"desired" description:"Value in the pipeline"`
+	Actual  string `json:"actual" description:"Value in the job of Jenkins"`
 }
@@ -17,8 +17,12 @@ limitations under the License.
 package util
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/emicklei/go-restful"
 	jcredential "github.com/jenkins-zh/jenkins-client/pkg/credential"
@@ -26,6 +30,58 @@ import (
 	v1 "k8s.io/api/core/v1"
 )
 
+const (
+	// CertificateCredentialStaplerClass is the Jenkins class of the certificate credential
+	CertificateCredentialStaplerClass = "com.cloudbees.plugins.credentials.impl.CertificateCredentialsImpl"
+	// UploadedKeyStoreSourceStaplerClass is the Jenkins class of an uploaded PKCS#12 keystore
+	UploadedKeyStoreSourceStaplerClass = "com.cloudbees.plugins.credentials.impl.CertificateCredentialsImpl$UploadedKeyStoreSource"
+	// FileCredentialStaplerClass is the Jenkins class of the secret file credential
+	FileCredentialStaplerClass = "org.jenkinsci.plugins.plaincredentials.impl.FileCredentialsImpl"
+	// GitHubAppCredentialStaplerClass is the Jenkins class of the GitHub App credential
+	GitHubAppCredentialStaplerClass = "org.jenkinsci.plugins.github_branch_source.GitHubAppCredentials"
+	// AWSCredentialStaplerClass is the Jenkins class of the AWS access key credential
+	AWSCredentialStaplerClass = "com.cloudbees.jenkins.plugins.awscredentials.AWSCredentialsImpl"
+)
+
+// CertificateCredential is a PKCS#12 certificate credential
+type CertificateCredential struct {
+	jcredential.Credential `json:",inline"`
+	Password               string         `json:"password"`
+	KeyStoreSource         KeyStoreSource `json:"keyStoreSource"`
+}
+
+// KeyStoreSource is the source of a certificate keystore
+type KeyStoreSource struct {
+	StaplerClass string `json:"stapler-class"`
+	// UploadedKeystore is the base64 encoded keystore
+	UploadedKeystore string `json:"uploadedKeystore"`
+}
+
+// FileCredential is a secret file credential
+type FileCredential struct {
+	jcredential.Credential `json:",inline"`
+	FileName               string `json:"fileName"`
+	// SecretBytes is the base64 encoded file content
+	SecretBytes string `json:"secretBytes"`
+}
+
+// GitHubAppCredential is a GitHub App credential
+type GitHubAppCredential struct {
+	jcredential.Credential `json:",inline"`
+	AppID                  string `json:"appID"`
+	PrivateKey             string `json:"privateKey"`
+	APIURI                 string `json:"apiUri,omitempty"`
+	Owner                  string `json:"owner,omitempty"`
+}
+
+// AWSCredential is an AWS access key credential
+type AWSCredential struct {
+	jcredential.Credential `json:",inline"`
+	AccessKey              string `json:"accessKey"`
+	SecretKey              string `json:"secretKey"`
+	IAMRoleArn             string `json:"iamRoleArn,omitempty"`
+}
+
 // ConvertSecretToCredential converts a secret to Jenkins credential type
 func ConvertSecretToCredential(secret *v1.Secret) (interface{}, error) {
 	name := secret.GetName()
@@ -46,8 +102,91 @@ func ConvertSecretToCredential(secret *v1.Secret) (interface{}, error) {
 	case devopsv1alpha3.SecretTypeKubeConfig:
 		secretContent := string(secret.Data[devopsv1alpha3.KubeConfigSecretKey])
 		return jcredential.NewKubeConfigCredential(name, secretContent), nil
+	case devopsv1alpha3.SecretTypeCertificate:
+		return &CertificateCredential{
+			Credential: newCredential(name, CertificateCredentialStaplerClass),
+			Password:   string(secret.Data[devopsv1alpha3.CertificatePasswordKey]),
+			KeyStoreSource: KeyStoreSource{
+				StaplerClass:     UploadedKeyStoreSourceStaplerClass,
+				UploadedKeystore: base64.StdEncoding.EncodeToString(secret.Data[devopsv1alpha3.CertificateKeystoreKey]),
+			},
+		}, nil
+	case devopsv1alpha3.SecretTypeSecretFile:
+		return &FileCredential{
+			Credential:  newCredential(name, FileCredentialStaplerClass),
+			FileName:    string(secret.Data[devopsv1alpha3.SecretFileNameKey]),
+			SecretBytes: base64.StdEncoding.EncodeToString(secret.Data[devopsv1alpha3.SecretFileContentKey]),
+		}, nil
+	case devopsv1alpha3.SecretTypeGitHubApp:
+		return &GitHubAppCredential{
+			Credential: newCredential(name, GitHubAppCredentialStaplerClass),
+			AppID:      string(secret.Data[devopsv1alpha3.GitHubAppIDKey]),
+			PrivateKey: string(secret.Data[devopsv1alpha3.GitHubAppPrivateKey]),
+			APIURI:     string(secret.Data[devopsv1alpha3.GitHubAppAPIURIKey]),
+			Owner:      string(secret.Data[devopsv1alpha3.GitHubAppOwnerKey]),
+		}, nil
+	case devopsv1alpha3.SecretTypeDockerRegistry:
+		username, password, err := GetDockerRegistryAuth(secret)
+		if err != nil {
+			return nil, restful.NewError(http.StatusBadRequest, err.Error())
+		}
+		return jcredential.NewUsernamePasswordCredential(name, username, password), nil
+	case devopsv1alpha3.SecretTypeAWSAccessKey:
+		return &AWSCredential{
+			Credential: newCredential(name, AWSCredentialStaplerClass),
+			AccessKey:  string(secret.Data[devopsv1alpha3.AWSAccessKeyIDKey]),
+			SecretKey:  string(secret.Data[devopsv1alpha3.AWSSecretAccessKeyKey]),
+			IAMRoleArn: string(secret.Data[devopsv1alpha3.AWSIAMRoleARNKey]),
+		}, nil
 	default:
 		err := fmt.Errorf("error unsupport credential type")
 		return nil, restful.NewError(http.StatusBadRequest, err.Error())
 	}
 }
+
+// GetDockerRegistryAuth returns the username and password of the first registry, in alphabetical order,
+// found in a docker config secret
+func GetDockerRegistryAuth(secret *v1.Secret) (username, password string, err error) {
+	dockerConfig := struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			Auth     string `json:"auth"`
+		} `json:"auths"`
+	}{}
+	if err = json.Unmarshal(secret.Data[v1.DockerConfigJsonKey], &dockerConfig); err != nil {
+		err = fmt.Errorf("invalid docker config: %v", err)
+		return
+	}
+
+	registries := make([]string, 0, len(dockerConfig.Auths))
+	for registry := range dockerConfig.Auths {
+		registries = append(registries, registry)
+	}
+	if len(registries) == 0 {
+		err = fmt.Errorf("no registry found in the docker config")
+		return
+	}
+	sort.Strings(registries)
+
+	auth := dockerConfig.Auths[registries[0]]
+	username, password = auth.Username, auth.Password
+	if username == "" && auth.Auth != "" {
+		var decoded []byte
+		if decoded, err = base64.StdEncoding.DecodeString(auth.Auth); err != nil {
+			err = fmt.Errorf("invalid auth of registry %s: %v", registries[0], err)
+			return
+		}
+		username, password, _ = strings.Cut(string(decoded), ":")
+	}
+	return
+}
+
+func newCredential(id, class string) jcredential.Credential {
+	return jcredential.Credential{
+		Scope:        jcredential.GLOBALScope,
+		ID:           id,
+		Class:        class,
+		StaplerClass: class,
+	}
+}
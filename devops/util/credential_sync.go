@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// CredentialSyncStatusSuccessful is the sync status of a credential which is synced to Jenkins
+	CredentialSyncStatusSuccessful = "successful"
+	// CredentialSyncStatusFailed is the sync status of a credential which failed to sync to Jenkins
+	CredentialSyncStatusFailed = "failed"
+)
+
+// CredentialSyncResult is the result of syncing a secret to Jenkins
+type CredentialSyncResult struct {
+	// Name is the name of the secret
+	Name string
+	// Synced indicates whether a create or update request was sent to Jenkins
+	Synced bool
+	// Annotations should be written back to the secret, it is empty if nothing changed
+	Annotations map[string]string
+	Err         error
+}
+
+// ComputeCredentialDataHash returns the hash of the type and data of a secret.
+// The hash does not depend on the order of the data keys.
+func ComputeCredentialDataHash(secret *v1.Secret) string {
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	writeHashField(hash.Write, []byte(secret.Type))
+	for _, key := range keys {
+		writeHashField(hash.Write, []byte(key))
+		writeHashField(hash.Write, secret.Data[key])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// writeHashField writes the length before the field, then different data never share the same input
+func writeHashField(write func([]byte) (int, error), field []byte) {
+	length := make([]byte, 8)
+	binary.BigEndian.PutUint64(length, uint64(len(field)))
+	_, _ = write(length)
+	_, _ = write(field)
+}
+
+// CredentialNeedsSync checks whether a secret should be created or updated in Jenkins
+func CredentialNeedsSync(secret *v1.Secret) bool {
+	if !isSupportedCredentialType(secret.Type) {
+		return false
+	}
+
+	annotations := secret.GetAnnotations()
+	switch annotations[devopsv1alpha3.CredentialAutoSyncAnnoKey] {
+	case "false":
+		return false
+	case "true":
+	default:
+		// credentials of the old version only exist in Jenkins, do not overwrite them with nil values
+		if len(secret.Data) == 0 {
+			return false
+		}
+	}
+
+	return annotations[devopsv1alpha3.CredentialSyncStatusAnnoKey] != CredentialSyncStatusSuccessful ||
+		annotations[devopsv1alpha3.DevOpsCredentialDataHash] != ComputeCredentialDataHash(secret)
+}
+
+// SyncCredential creates or updates the credential in Jenkins if the secret has drifted,
+// then returns the annotations which should be written back to the secret
+func SyncCredential(operator devops.CredentialOperator, projectID string, secret *v1.Secret) *CredentialSyncResult {
+	result := &CredentialSyncResult{Name: secret.GetName()}
+	if !CredentialNeedsSync(secret) {
+		return result
+	}
+
+	result.Synced = true
+	result.Err = createOrUpdateCredential(operator, projectID, secret)
+	result.Annotations = map[string]string{
+		devopsv1alpha3.CredentialSyncTimeAnnoKey: time.Now().UTC().Format(time.RFC3339),
+	}
+	if result.Err != nil {
+		result.Annotations[devopsv1alpha3.CredentialSyncStatusAnnoKey] = CredentialSyncStatusFailed
+		result.Annotations[devopsv1alpha3.CredentialSyncMsgAnnoKey] = result.Err.Error()
+	} else {
+		result.Annotations[devopsv1alpha3.CredentialSyncStatusAnnoKey] = CredentialSyncStatusSuccessful
+		result.Annotations[devopsv1alpha3.CredentialSyncMsgAnnoKey] = ""
+		result.Annotations[devopsv1alpha3.DevOpsCredentialDataHash] = ComputeCredentialDataHash(secret)
+	}
+	return result
+}
+
+// SyncCredentials syncs all the credential secrets of a project, secrets of other types are ignored
+func SyncCredentials(operator devops.CredentialOperator, projectID string, secrets []v1.Secret) []*CredentialSyncResult {
+	results := make([]*CredentialSyncResult, 0, len(secrets))
+	for i := range secrets {
+		if !isSupportedCredentialType(secrets[i].Type) {
+			continue
+		}
+		results = append(results, SyncCredential(operator, projectID, &secrets[i]))
+	}
+	return results
+}
+
+func createOrUpdateCredential(operator devops.CredentialOperator, projectID string, secret *v1.Secret) (err error) {
+	if _, err = operator.GetCredentialInProject(projectID, secret.GetName()); err != nil {
+		if devops.GetDevOpsStatusCode(err) != http.StatusNotFound {
+			return
+		}
+		_, err = operator.CreateCredentialInProject(projectID, secret)
+		return
+	}
+	_, err = operator.UpdateCredentialInProject(projectID, secret)
+	return
+}
+
+func isSupportedCredentialType(secretType v1.SecretType) bool {
+	for _, supportedType := range devopsv1alpha3.GetSupportedCredentialTypes() {
+		if secretType == supportedType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/opswave/go-jenkins/devops"
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeCredentialOperator struct {
+	credentials map[string]*v1.Secret
+	err         error
+	calls       []string
+}
+
+func (o *fakeCredentialOperator) CreateCredentialInProject(projectID string, secret *v1.Secret) (string, error) {
+	o.calls = append(o.calls, "create:"+secret.Name)
+	if o.err != nil {
+		return "", o.err
+	}
+	o.credentials[secret.Name] = secret
+	return secret.Name, nil
+}
+
+func (o *fakeCredentialOperator) UpdateCredentialInProject(projectID string, secret *v1.Secret) (string, error) {
+	o.calls = append(o.calls, "update:"+secret.Name)
+	if o.err != nil {
+		return "", o.err
+	}
+	o.credentials[secret.Name] = secret
+	return secret.Name, nil
+}
+
+func (o *fakeCredentialOperator) GetCredentialInProject(projectID, id string) (*devops.Credential, error) {
+	if _, ok := o.credentials[id]; !ok {
+		return nil, fmt.Errorf("%d", http.StatusNotFound)
+	}
+	return &devops.Credential{Id: id}, nil
+}
+
+func (o *fakeCredentialOperator) DeleteCredentialInProject(projectID, id string, force bool) (string, error) {
+	delete(o.credentials, id)
+	return id, nil
+}
+
+func (o *fakeCredentialOperator) ListCredentialsInProject(projectID string) ([]*devops.Credential, error) {
+	return nil, nil
+}
+
+func newSecret(name string, secretType v1.SecretType, data map[string]string, annotations map[string]string) *v1.Secret {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+		Type:       secretType,
+		Data:       map[string][]byte{},
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+func TestComputeCredentialDataHash(t *testing.T) {
+	a := newSecret("a", devopsv1alpha3.SecretTypeBasicAuth, map[string]string{"username": "admin", "password": "pass"}, nil)
+	b := newSecret("b", devopsv1alpha3.SecretTypeBasicAuth, map[string]string{"password": "pass", "username": "admin"}, nil)
+	assert.Equal(t, ComputeCredentialDataHash(a), ComputeCredentialDataHash(b))
+
+	b.Type = devopsv1alpha3.SecretTypeSecretText
+	assert.NotEqual(t, ComputeCredentialDataHash(a), ComputeCredentialDataHash(b))
+
+	// moving bytes between key and value must change the hash
+	c := newSecret("c", devopsv1alpha3.SecretTypeSecretText, map[string]string{"ab": "c"}, nil)
+	d := newSecret("d", devopsv1alpha3.SecretTypeSecretText, map[string]string{"a": "bc"}, nil)
+	assert.NotEqual(t, ComputeCredentialDataHash(c), ComputeCredentialDataHash(d))
+}
+
+func TestCredentialNeedsSync(t *testing.T) {
+	data := map[string]string{devopsv1alpha3.SecretTextSecretKey: "token"}
+	synced := newSecret("synced", devopsv1alpha3.SecretTypeSecretText, data, nil)
+	synced.Annotations = map[string]string{
+		devopsv1alpha3.DevOpsCredentialDataHash:    ComputeCredentialDataHash(synced),
+		devopsv1alpha3.CredentialSyncStatusAnnoKey: CredentialSyncStatusSuccessful,
+	}
+	drifted := synced.DeepCopy()
+	drifted.Data[devopsv1alpha3.SecretTextSecretKey] = []byte("new token")
+	failed := synced.DeepCopy()
+	failed.Annotations[devopsv1alpha3.CredentialSyncStatusAnnoKey] = CredentialSyncStatusFailed
+
+	tests := []struct {
+		name   string
+		secret *v1.Secret
+		want   bool
+	}{{
+		name:   "new secret",
+		secret: newSecret("new", devopsv1alpha3.SecretTypeSecretText, data, nil),
+		want:   true,
+	}, {
+		name:   "up to date",
+		secret: synced,
+		want:   false,
+	}, {
+		name:   "data changed",
+		secret: drifted,
+		want:   true,
+	}, {
+		name:   "last sync failed",
+		secret: failed,
+		want:   true,
+	}, {
+		name:   "not a credential",
+		secret: newSecret("opaque", v1.SecretTypeOpaque, data, nil),
+		want:   false,
+	}, {
+		name: "auto sync disabled",
+		secret: newSecret("disabled", devopsv1alpha3.SecretTypeSecretText, data, map[string]string{
+			devopsv1alpha3.CredentialAutoSyncAnnoKey: "false",
+		}),
+		want: false,
+	}, {
+		name:   "empty data of the old version",
+		secret: newSecret("old", devopsv1alpha3.SecretTypeSecretText, nil, nil),
+		want:   false,
+	}, {
+		name: "empty data with auto sync",
+		secret: newSecret("empty", devopsv1alpha3.SecretTypeSecretText, nil, map[string]string{
+			devopsv1alpha3.CredentialAutoSyncAnnoKey: "true",
+		}),
+		want: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CredentialNeedsSync(tt.secret))
+		})
+	}
+}
+
+func TestSyncCredential(t *testing.T) {
+	data := map[string]string{devopsv1alpha3.SecretTextSecretKey: "token"}
+
+	t.Run("create then update", func(t *testing.T) {
+		operator := &fakeCredentialOperator{credentials: map[string]*v1.Secret{}}
+		secret := newSecret("token", devopsv1alpha3.SecretTypeSecretText, data, nil)
+
+		result := SyncCredential(operator, "project", secret)
+		assert.Nil(t, result.Err)
+		assert.True(t, result.Synced)
+		assert.Equal(t, CredentialSyncStatusSuccessful, result.Annotations[devopsv1alpha3.CredentialSyncStatusAnnoKey])
+		assert.Equal(t, ComputeCredentialDataHash(secret), result.Annotations[devopsv1alpha3.DevOpsCredentialDataHash])
+		assert.NotEmpty(t, result.Annotations[devopsv1alpha3.CredentialSyncTimeAnnoKey])
+
+		secret.Annotations = result.Annotations
+		result = SyncCredential(operator, "project", secret)
+		assert.False(t, result.Synced)
+		assert.Empty(t, result.Annotations)
+
+		secret.Data[devopsv1alpha3.SecretTextSecretKey] = []byte("new token")
+		result = SyncCredential(operator, "project", secret)
+		assert.True(t, result.Synced)
+		assert.Equal(t, []string{"create:token", "update:token"}, operator.calls)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		operator := &fakeCredentialOperator{credentials: map[string]*v1.Secret{}, err: fmt.Errorf("jenkins is down")}
+		result := SyncCredential(operator, "project", newSecret("token", devopsv1alpha3.SecretTypeSecretText, data, nil))
+		assert.NotNil(t, result.Err)
+		assert.Equal(t, CredentialSyncStatusFailed, result.Annotations[devopsv1alpha3.CredentialSyncStatusAnnoKey])
+		assert.Equal(t, "jenkins is down", result.Annotations[devopsv1alpha3.CredentialSyncMsgAnnoKey])
+		assert.NotContains(t, result.Annotations, devopsv1alpha3.DevOpsCredentialDataHash)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		operator := &fakeCredentialOperator{credentials: map[string]*v1.Secret{}}
+		results := SyncCredentials(operator, "project", []v1.Secret{
+			*newSecret("token", devopsv1alpha3.SecretTypeSecretText, data, nil),
+			*newSecret("opaque", v1.SecretTypeOpaque, data, nil),
+			*newSecret("kubeconfig", devopsv1alpha3.SecretTypeKubeConfig, map[string]string{
+				devopsv1alpha3.KubeConfigSecretKey: "content",
+			}, nil),
+		})
+		if assert.Len(t, results, 2) {
+			assert.Equal(t, "token", results[0].Name)
+			assert.Equal(t, "kubeconfig", results[1].Name)
+		}
+		assert.Len(t, operator.credentials, 2)
+	})
+}
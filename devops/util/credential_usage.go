@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"regexp"
+	"strings"
+
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+)
+
+const (
+	// CredentialReferenceJenkinsfile means a credential is referenced by the Jenkinsfile
+	CredentialReferenceJenkinsfile = "jenkinsfile"
+)
+
+var (
+	// matches credentialsId: 'id', it is used by withCredentials bindings, the git step and the rendered step templates
+	credentialsIdPattern = regexp.MustCompile(`credentialsId\s*:\s*['"]([^'"$]+)['"]`)
+	// matches credentials('id') of the declarative environment directive
+	credentialsHelperPattern = regexp.MustCompile(`credentials\(\s*['"]([^'"$]+)['"]\s*\)`)
+	// matches sshagent(['id1', 'id2']) and sshagent(credentials: ['id'])
+	sshAgentPattern = regexp.MustCompile(`sshagent\s*\(\s*(?:credentials\s*:\s*)?\[([^\]]*)\]`)
+	quotedPattern   = regexp.MustCompile(`['"]([^'"$]+)['"]`)
+)
+
+// FindCredentialReferences returns the credential IDs referenced by a pipeline, mapped to where they are referenced.
+// Credential IDs built by Groovy string interpolation cannot be found.
+func FindCredentialReferences(pipeline *devopsv1alpha3.Pipeline) map[string][]string {
+	references := map[string][]string{}
+	add := func(id, source string) {
+		if id = strings.TrimSpace(id); id == "" {
+			return
+		}
+		for _, existing := range references[id] {
+			if existing == source {
+				return
+			}
+		}
+		references[id] = append(references[id], source)
+	}
+
+	if pipeline.Spec.Pipeline != nil {
+		for _, id := range FindCredentialsInJenkinsfile(pipeline.Spec.Pipeline.Jenkinsfile) {
+			add(id, CredentialReferenceJenkinsfile)
+		}
+	}
+
+	if multiBranch := pipeline.Spec.MultiBranchPipeline; multiBranch != nil {
+		if multiBranch.GitSource != nil {
+			add(multiBranch.GitSource.CredentialId, "git_source")
+		}
+		if multiBranch.GitHubSource != nil {
+			add(multiBranch.GitHubSource.CredentialId, "github_source")
+		}
+		if multiBranch.GitlabSource != nil {
+			add(multiBranch.GitlabSource.CredentialId, "gitlab_source")
+		}
+		if multiBranch.BitbucketServerSource != nil {
+			add(multiBranch.BitbucketServerSource.CredentialId, "bitbucket_server_source")
+		}
+		if multiBranch.BitbucketCloudSource != nil {
+			add(multiBranch.BitbucketCloudSource.CredentialId, "bitbucket_cloud_source")
+		}
+		if multiBranch.GiteaSource != nil {
+			add(multiBranch.GiteaSource.CredentialId, "gitea_source")
+		}
+		if multiBranch.SvnSource != nil {
+			add(multiBranch.SvnSource.CredentialId, "svn_source")
+		}
+		if multiBranch.SingleSvnSource != nil {
+			add(multiBranch.SingleSvnSource.CredentialId, "single_svn_source")
+		}
+	}
+
+	if folder := pipeline.Spec.OrganizationFolder; folder != nil {
+		add(folder.CredentialId, "organization_folder")
+	}
+	return references
+}
+
+// FindCredentialsInJenkinsfile returns the literal credential IDs used by a Jenkinsfile
+func FindCredentialsInJenkinsfile(jenkinsfile string) (ids []string) {
+	found := map[string]bool{}
+	add := func(id string) {
+		if !found[id] {
+			found[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, pattern := range []*regexp.Regexp{credentialsIdPattern, credentialsHelperPattern} {
+		for _, match := range pattern.FindAllStringSubmatch(jenkinsfile, -1) {
+			add(match[1])
+		}
+	}
+	for _, match := range sshAgentPattern.FindAllStringSubmatch(jenkinsfile, -1) {
+		for _, quoted := range quotedPattern.FindAllStringSubmatch(match[1], -1) {
+			add(quoted[1])
+		}
+	}
+	return
+}
+
+// ReplaceCredentialsInJenkinsfile replaces the literal credential IDs which could be found by
+// FindCredentialsInJenkinsfile, the key of mapping is the ID to be replaced
+func ReplaceCredentialsInJenkinsfile(jenkinsfile string, mapping map[string]string) string {
+	replaceQuoted := func(text string) string {
+		return quotedPattern.ReplaceAllStringFunc(text, func(quoted string) string {
+			if id, ok := mapping[quoted[1:len(quoted)-1]]; ok {
+				return quoted[:1] + id + quoted[len(quoted)-1:]
+			}
+			return quoted
+		})
+	}
+	for _, pattern := range []*regexp.Regexp{credentialsIdPattern, credentialsHelperPattern, sshAgentPattern} {
+		jenkinsfile = pattern.ReplaceAllStringFunc(jenkinsfile, replaceQuoted)
+	}
+	return jenkinsfile
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	devopsv1alpha3 "github.com/opswave/go-jenkins/devops/v1alpha3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindCredentialsInJenkinsfile(t *testing.T) {
+	jenkinsfile := `pipeline {
+  agent any
+  environment {
+    TOKEN = credentials('sonar-token')
+  }
+  stages {
+    stage('checkout') {
+      steps {
+        git(url: 'https://github.com/kubesphere/devops-java-sample', credentialsId: "github-id", branch: 'master')
+        withCredentials([usernamePassword(credentialsId : 'docker', passwordVariable : 'PASS', usernameVariable : 'USER')]) {
+          sh 'docker login -u $USER -p $PASS'
+        }
+        withCredentials([kubeconfigContent(credentialsId: "${KUBECONFIG_ID}", variable: 'CONFIG')]) {
+          sh 'kubectl get pods'
+        }
+        sshagent(['deploy-key', 'github-id']) {
+          sh 'ssh host'
+        }
+      }
+    }
+  }
+}`
+	assert.Equal(t, []string{"github-id", "docker", "sonar-token", "deploy-key"}, FindCredentialsInJenkinsfile(jenkinsfile))
+	assert.Empty(t, FindCredentialsInJenkinsfile(""))
+}
+
+func TestReplaceCredentialsInJenkinsfile(t *testing.T) {
+	jenkinsfile := `pipeline {
+  environment {
+    TOKEN = credentials('sonar-token')
+  }
+  stages {
+    stage('docker') {
+      steps {
+        withCredentials([usernamePassword(credentialsId : "docker", passwordVariable : 'PASS', usernameVariable : 'USER')]) {
+          sh 'echo docker'
+        }
+        sshagent(['deploy-key', 'docker']) {
+          sh 'ssh host'
+        }
+      }
+    }
+  }
+}`
+	replaced := ReplaceCredentialsInJenkinsfile(jenkinsfile, map[string]string{"docker": "registry", "sonar-token": "sonar"})
+	assert.Equal(t, []string{"registry", "sonar", "deploy-key"}, FindCredentialsInJenkinsfile(replaced))
+	// the other strings are kept even if they are the same as a credential ID
+	assert.Contains(t, replaced, "stage('docker')")
+	assert.Contains(t, replaced, "sh 'echo docker'")
+	assert.Equal(t, jenkinsfile, ReplaceCredentialsInJenkinsfile(jenkinsfile, nil))
+}
+
+func TestFindCredentialReferences(t *testing.T) {
+	noScm := &devopsv1alpha3.Pipeline{Spec: devopsv1alpha3.PipelineSpec{
+		Type: devopsv1alpha3.NoScmPipelineType,
+		Pipeline: &devopsv1alpha3.NoScmPipeline{
+			Jenkinsfile: `withCredentials([string(credentialsId: 'token', variable: 'VARIABLE')]) { sh 'echo 1' }`,
+		},
+	}}
+	assert.Equal(t, map[string][]string{"token": {CredentialReferenceJenkinsfile}}, FindCredentialReferences(noScm))
+
+	multiBranch := &devopsv1alpha3.Pipeline{Spec: devopsv1alpha3.PipelineSpec{
+		Type: devopsv1alpha3.MultiBranchPipelineType,
+		MultiBranchPipeline: &devopsv1alpha3.MultiBranchPipeline{
+			SourceType:   devopsv1alpha3.SourceTypeGithub,
+			GitHubSource: &devopsv1alpha3.GithubSource{CredentialId: "github"},
+			GitSource:    &devopsv1alpha3.GitSource{},
+		},
+	}}
+	assert.Equal(t, map[string][]string{"github": {"github_source"}}, FindCredentialReferences(multiBranch))
+
+	organizationFolder := &devopsv1alpha3.Pipeline{Spec: devopsv1alpha3.PipelineSpec{
+		Type: devopsv1alpha3.OrganizationFolderPipelineType,
+		OrganizationFolder: &devopsv1alpha3.OrganizationFolder{
+			Navigator:    devopsv1alpha3.NavigatorTypeGitlab,
+			CredentialId: "gitlab",
+		},
+	}}
+	assert.Equal(t, map[string][]string{"gitlab": {"organization_folder"}}, FindCredentialReferences(organizationFolder))
+}
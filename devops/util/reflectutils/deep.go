@@ -59,11 +59,27 @@ var (
 )
 
 type cmp struct {
-	diff        []string
+	diff        []Difference
 	buff        []string
 	floatFormat string
 }
 
+// Difference is a difference found by Differences, Path is the dotted path of the field, it is empty
+// if the values are different at the top level.
+type Difference struct {
+	Path string
+	A    string
+	B    string
+}
+
+// String formats the difference in the same way as Equal does.
+func (d Difference) String() string {
+	if d.Path == "" {
+		return fmt.Sprintf("%s != %s", d.A, d.B)
+	}
+	return fmt.Sprintf("%s: %s != %s", d.Path, d.A, d.B)
+}
+
 var errorType = reflect.TypeOf((*error)(nil)).Elem()
 
 // Equal compares variables a and b, recursing into their structure up to
@@ -77,10 +93,23 @@ var errorType = reflect.TypeOf((*error)(nil)).Elem()
 // When comparing a struct, if a field has the tag `deep:"-"` then it will be
 // ignored.
 func Equal(a, b interface{}) []string {
+	diffs := Differences(a, b)
+	if diffs == nil {
+		return nil
+	}
+	result := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		result = append(result, diff.String())
+	}
+	return result
+}
+
+// Differences is like Equal but returns the differences in a structured form, or nil if there are none.
+func Differences(a, b interface{}) []Difference {
 	aVal := reflect.ValueOf(a)
 	bVal := reflect.ValueOf(b)
 	c := &cmp{
-		diff:        []string{},
+		diff:        []Difference{},
 		buff:        []string{},
 		floatFormat: fmt.Sprintf("%%.%df", FloatPrecision),
 	}
@@ -377,12 +406,11 @@ func (c *cmp) pop() {
 }
 
 func (c *cmp) saveDiff(aval, bval interface{}) {
-	if len(c.buff) > 0 {
-		varName := strings.Join(c.buff, ".")
-		c.diff = append(c.diff, fmt.Sprintf("%s: %v != %v", varName, aval, bval))
-	} else {
-		c.diff = append(c.diff, fmt.Sprintf("%v != %v", aval, bval))
-	}
+	c.diff = append(c.diff, Difference{
+		Path: strings.Join(c.buff, "."),
+		A:    fmt.Sprint(aval),
+		B:    fmt.Sprint(bval),
+	})
 }
 
 func logError(err error) {
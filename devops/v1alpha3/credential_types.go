@@ -67,6 +67,64 @@ const (
 	SecretTypeKubeConfig v1.SecretType = DevOpsCredentialPrefix + "kubeconfig"
 	// KubeConfigSecretKey is the key of the secret for SecretTypeKubeConfig secrets
 	KubeConfigSecretKey = "content"
+
+	// SecretTypeCertificate contains a PKCS#12 certificate.
+	//
+	// Required at least one of fields:
+	// - Secret.Data["keystore"] - the PKCS#12 keystore
+	// - Secret.Data["password"] - password of the keystore
+	SecretTypeCertificate v1.SecretType = DevOpsCredentialPrefix + "certificate"
+	// CertificateKeystoreKey is the key of the keystore for SecretTypeCertificate secrets
+	CertificateKeystoreKey = "keystore"
+	// CertificatePasswordKey is the key of the keystore password for SecretTypeCertificate secrets
+	CertificatePasswordKey = "password"
+
+	// SecretTypeSecretFile contains a secret file.
+	//
+	// Required at least one of fields:
+	// - Secret.Data["filename"] - name of the file
+	// - Secret.Data["content"] - content of the file
+	SecretTypeSecretFile v1.SecretType = DevOpsCredentialPrefix + "secret-file"
+	// SecretFileNameKey is the key of the file name for SecretTypeSecretFile secrets
+	SecretFileNameKey = "filename"
+	// SecretFileContentKey is the key of the file content for SecretTypeSecretFile secrets
+	SecretFileContentKey = "content"
+
+	// SecretTypeGitHubApp contains data needed for GitHub App authentication.
+	//
+	// Required at least one of fields:
+	// - Secret.Data["app_id"] - ID of the GitHub App
+	// - Secret.Data["private_key"] - private key of the GitHub App
+	// - Secret.Data["api_uri"] - GitHub API endpoint, optional
+	// - Secret.Data["owner"] - organization or user the App is installed to, optional
+	SecretTypeGitHubApp v1.SecretType = DevOpsCredentialPrefix + "github-app"
+	// GitHubAppIDKey is the key of the App ID for SecretTypeGitHubApp secrets
+	GitHubAppIDKey = "app_id"
+	// GitHubAppPrivateKey is the key of the private key for SecretTypeGitHubApp secrets
+	GitHubAppPrivateKey = "private_key"
+	// GitHubAppAPIURIKey is the key of the API endpoint for SecretTypeGitHubApp secrets
+	GitHubAppAPIURIKey = "api_uri"
+	// GitHubAppOwnerKey is the key of the owner for SecretTypeGitHubApp secrets
+	GitHubAppOwnerKey = "owner"
+
+	// SecretTypeDockerRegistry is the native docker config secret type.
+	// The first registry found in Secret.Data[".dockerconfigjson"] is used as a username and password credential.
+	SecretTypeDockerRegistry = v1.SecretTypeDockerConfigJson
+
+	// SecretTypeAWSAccessKey contains data needed for AWS authentication.
+	//
+	// Required at least one of fields:
+	// - Secret.Data["access_key_id"] - access key ID
+	// - Secret.Data["secret_access_key"] - secret access key
+	// - Secret.Data["iam_role_arn"] - IAM role to assume, optional
+	SecretTypeAWSAccessKey v1.SecretType = DevOpsCredentialPrefix + "aws-access-key"
+	// AWSAccessKeyIDKey is the key of the access key ID for SecretTypeAWSAccessKey secrets
+	AWSAccessKeyIDKey = "access_key_id"
+	// AWSSecretAccessKeyKey is the key of the secret access key for SecretTypeAWSAccessKey secrets
+	AWSSecretAccessKeyKey = "secret_access_key"
+	// AWSIAMRoleARNKey is the key of the IAM role ARN for SecretTypeAWSAccessKey secrets
+	AWSIAMRoleARNKey = "iam_role_arn"
+
 	//	CredentialAutoSyncAnnoKey is used to indicate whether the secret is automatically synchronized to devops.
 	//	In the old version, the credential is stored in jenkins and cannot be obtained.
 	//	This field is set to ensure that the secret is not overwritten by a nil value.
@@ -81,6 +139,11 @@ var supportedCredentialTypes = []v1.SecretType{
 	SecretTypeSSHAuth,
 	SecretTypeSecretText,
 	SecretTypeKubeConfig,
+	SecretTypeCertificate,
+	SecretTypeSecretFile,
+	SecretTypeGitHubApp,
+	SecretTypeDockerRegistry,
+	SecretTypeAWSAccessKey,
 }
 
 // GetSupportedCredentialTypes gets all supported credential types. The return value is unmodifiable.
@@ -18,6 +18,8 @@ package v1alpha3
 
 import (
 	"fmt"
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -64,6 +66,7 @@ type PipelineSpec struct {
 	Type                PipelineType         `json:"type" description:"type of devops pipeline, in scm or no scm"`
 	Pipeline            *NoScmPipeline       `json:"pipeline,omitempty" description:"no scm pipeline structs"`
 	MultiBranchPipeline *MultiBranchPipeline `json:"multi_branch_pipeline,omitempty" description:"in scm pipeline structs"`
+	OrganizationFolder  *OrganizationFolder  `json:"organization_folder,omitempty" description:"organization folder structs"`
 }
 
 // PipelineStatus defines the observed state of Pipeline
@@ -111,6 +114,8 @@ type PipelineType string
 const (
 	NoScmPipelineType       PipelineType = "pipeline"
 	MultiBranchPipelineType PipelineType = "multi-branch-pipeline"
+	// OrganizationFolderPipelineType creates a multi-branch pipeline for each repository of an organization
+	OrganizationFolderPipelineType PipelineType = "organization-folder"
 )
 
 const (
@@ -120,34 +125,79 @@ const (
 	SourceTypeGitlab    = "gitlab"
 	SourceTypeGithub    = "github"
 	SourceTypeBitbucket = "bitbucket_server"
+	// SourceTypeBitbucketCloud is the source type of repositories hosted on https://bitbucket.org
+	SourceTypeBitbucketCloud = "bitbucket_cloud"
+	SourceTypeGitea          = "gitea"
 )
 
+// BitbucketCloudServerURL is the server URL of Bitbucket Cloud
+const BitbucketCloudServerURL = "https://bitbucket.org"
+
 type NoScmPipeline struct {
-	Name              string                `json:"name" description:"name of pipeline"`
-	Description       string                `json:"description,omitempty" description:"description of pipeline"`
-	Discarder         *DiscarderProperty    `json:"discarder,omitempty" description:"Discarder of pipeline, managing when to drop a pipeline"`
-	Parameters        []ParameterDefinition `json:"parameters,omitempty" description:"Parameters define of pipeline,user could pass param when run pipeline"`
-	DisableConcurrent bool                  `json:"disable_concurrent,omitempty" mapstructure:"disable_concurrent" description:"Whether to prohibit the pipeline from running in parallel"`
-	TimerTrigger      *TimerTrigger         `json:"timer_trigger,omitempty" mapstructure:"timer_trigger" description:"Timer to trigger pipeline run"`
-	RemoteTrigger     *RemoteTrigger        `json:"remote_trigger,omitempty" mapstructure:"remote_trigger" description:"Remote api define to trigger pipeline run"`
-	GenericWebhook    *GenericWebhook       `json:"generic_webhook,omitempty" mapstructure:"generic_webhook" description:"Generic webhook config"`
-	Jenkinsfile       string                `json:"jenkinsfile,omitempty" description:"Jenkinsfile's content'"`
+	Name              string                     `json:"name" description:"name of pipeline"`
+	Description       string                     `json:"description,omitempty" description:"description of pipeline"`
+	Discarder         *DiscarderProperty         `json:"discarder,omitempty" description:"Discarder of pipeline, managing when to drop a pipeline"`
+	Disabled          bool                       `json:"disabled,omitempty" description:"Whether the pipeline is disabled, a disabled pipeline can not be built"`
+	Parameters        []ParameterDefinition      `json:"parameters,omitempty" description:"Parameters define of pipeline,user could pass param when run pipeline"`
+	DisableConcurrent bool                       `json:"disable_concurrent,omitempty" mapstructure:"disable_concurrent" description:"Whether to prohibit the pipeline from running in parallel"`
+	AbortPrevious     bool                       `json:"abort_previous,omitempty" mapstructure:"abort_previous" description:"Whether to abort the running build when a new one is started, only if the concurrent builds are disabled"`
+	QuietPeriod       *int                       `json:"quiet_period,omitempty" mapstructure:"quiet_period" description:"Seconds to wait before starting a triggered build, the global quiet period is used if it is not set"`
+	DurabilityHint    string                     `json:"durability_hint,omitempty" mapstructure:"durability_hint" description:"Durability of the pipeline, such as PERFORMANCE_OPTIMIZED/SURVIVABLE_NONATOMIC/MAX_SURVIVABILITY"`
+	DisableResume     bool                       `json:"disable_resume,omitempty" mapstructure:"disable_resume" description:"Whether to prohibit the pipeline from resuming after Jenkins restarts"`
+	GithubProject     *GithubProjectProperty     `json:"github_project,omitempty" mapstructure:"github_project" description:"GitHub project which the pipeline belongs to"`
+	GitlabConnection  *GitlabConnectionProperty  `json:"gitlab_connection,omitempty" mapstructure:"gitlab_connection" description:"GitLab connection used by the pipeline"`
+	RateLimit         *RateLimitProperty         `json:"rate_limit,omitempty" mapstructure:"rate_limit" description:"Limit of the number of builds in a duration"`
+	LockableResources *LockableResourcesProperty `json:"lockable_resources,omitempty" mapstructure:"lockable_resources" description:"Lockable resources required by the builds"`
+	TimerTrigger      *TimerTrigger              `json:"timer_trigger,omitempty" mapstructure:"timer_trigger" description:"Timer to trigger pipeline run"`
+	RemoteTrigger     *RemoteTrigger             `json:"remote_trigger,omitempty" mapstructure:"remote_trigger" description:"Remote api define to trigger pipeline run"`
+	GenericWebhook    *GenericWebhook            `json:"generic_webhook,omitempty" mapstructure:"generic_webhook" description:"Generic webhook config"`
+	UpstreamTrigger   *UpstreamTrigger           `json:"upstream_trigger,omitempty" mapstructure:"upstream_trigger" description:"Trigger the pipeline when the upstream pipelines are completed"`
+	ScmTrigger        *ScmTrigger                `json:"scm_trigger,omitempty" mapstructure:"scm_trigger" description:"Poll the scm to trigger pipeline run when there are changes"`
+	GithubPushTrigger *GithubPushTrigger         `json:"github_push_trigger,omitempty" mapstructure:"github_push_trigger" description:"Trigger the pipeline when changes are pushed to GitHub"`
+	GitlabPushTrigger *GitlabPushTrigger         `json:"gitlab_push_trigger,omitempty" mapstructure:"gitlab_push_trigger" description:"Trigger the pipeline on the push and merge request events of GitLab"`
+	Jenkinsfile       string                     `json:"jenkinsfile,omitempty" description:"Jenkinsfile's content'"`
+	ScmDefinition     *ScmFlowDefinition         `json:"scm_definition,omitempty" mapstructure:"scm_definition" description:"Load the Jenkinsfile from scm instead of the inline Jenkinsfile"`
+	UnknownElements   []UnknownElement           `json:"unknown_elements,omitempty" mapstructure:"unknown_elements" description:"elements of the Jenkins config which are not recognized, they are kept when updating the pipeline"`
+}
+
+// ScmFlowDefinition is the definition of the "Pipeline script from SCM" pipelines, only one branch is built
+type ScmFlowDefinition struct {
+	SourceType  string           `json:"source_type" mapstructure:"source_type" description:"type of scm, git or svn"`
+	GitSource   *GitSource       `json:"git_source,omitempty" mapstructure:"git_source" description:"git scm define, the url, credential, clone and checkout options are used"`
+	SvnSource   *SingleSvnSource `json:"svn_source,omitempty" mapstructure:"svn_source" description:"svn scm define"`
+	Branch      string           `json:"branch,omitempty" description:"branch specifier of git, default is */master"`
+	ScriptPath  string           `json:"script_path,omitempty" mapstructure:"script_path" description:"path of the Jenkinsfile in scm, default is Jenkinsfile"`
+	Lightweight bool             `json:"lightweight,omitempty" description:"Whether to fetch the Jenkinsfile only instead of checking out the whole repository"`
 }
 
 type MultiBranchPipeline struct {
-	Name                  string                 `json:"name" description:"name of pipeline"`
-	Description           string                 `json:"description,omitempty" description:"description of pipeline"`
-	Discarder             *DiscarderProperty     `json:"discarder,omitempty" description:"Discarder of pipeline, managing when to drop a pipeline"`
-	TimerTrigger          *TimerTrigger          `json:"timer_trigger,omitempty" mapstructure:"timer_trigger" description:"Timer to trigger pipeline run"`
-	SourceType            string                 `json:"source_type" description:"type of scm, such as github/git/svn"`
-	GitSource             *GitSource             `json:"git_source,omitempty" description:"git scm define"`
-	GitHubSource          *GithubSource          `json:"github_source,omitempty" description:"github scm define"`
-	GitlabSource          *GitlabSource          `json:"gitlab_source,omitempty" description:"gitlab scm define"`
-	SvnSource             *SvnSource             `json:"svn_source,omitempty" description:"multi branch svn scm define"`
-	SingleSvnSource       *SingleSvnSource       `json:"single_svn_source,omitempty" description:"single branch svn scm define"`
-	BitbucketServerSource *BitbucketServerSource `json:"bitbucket_server_source,omitempty" description:"bitbucket server scm defile"`
-	ScriptPath            string                 `json:"script_path" mapstructure:"script_path" description:"script path in scm"`
-	MultiBranchJobTrigger *MultiBranchJobTrigger `json:"multibranch_job_trigger,omitempty" mapstructure:"multibranch_job_trigger" description:"Pipeline tasks that need to be triggered when branch creation/deletion"`
+	Name                   string                  `json:"name" description:"name of pipeline"`
+	Description            string                  `json:"description,omitempty" description:"description of pipeline"`
+	Discarder              *DiscarderProperty      `json:"discarder,omitempty" description:"Discarder of pipeline, managing when to drop a pipeline"`
+	Disabled               bool                    `json:"disabled,omitempty" description:"Whether the pipeline is disabled, the branches are neither scanned nor built when it is disabled"`
+	TimerTrigger           *TimerTrigger           `json:"timer_trigger,omitempty" mapstructure:"timer_trigger" description:"Timer to trigger pipeline run"`
+	SourceType             string                  `json:"source_type" description:"type of scm, such as github/git/svn"`
+	GitSource              *GitSource              `json:"git_source,omitempty" description:"git scm define"`
+	GitHubSource           *GithubSource           `json:"github_source,omitempty" description:"github scm define"`
+	GitlabSource           *GitlabSource           `json:"gitlab_source,omitempty" description:"gitlab scm define"`
+	SvnSource              *SvnSource              `json:"svn_source,omitempty" description:"multi branch svn scm define"`
+	SingleSvnSource        *SingleSvnSource        `json:"single_svn_source,omitempty" description:"single branch svn scm define"`
+	BitbucketServerSource  *BitbucketServerSource  `json:"bitbucket_server_source,omitempty" description:"bitbucket server scm defile"`
+	BitbucketCloudSource   *BitbucketCloudSource   `json:"bitbucket_cloud_source,omitempty" mapstructure:"bitbucket_cloud_source" description:"bitbucket cloud scm define"`
+	GiteaSource            *GiteaSource            `json:"gitea_source,omitempty" mapstructure:"gitea_source" description:"gitea scm define"`
+	ScriptPath             string                  `json:"script_path" mapstructure:"script_path" description:"script path in scm"`
+	MultiBranchJobTrigger  *MultiBranchJobTrigger  `json:"multibranch_job_trigger,omitempty" mapstructure:"multibranch_job_trigger" description:"Pipeline tasks that need to be triggered when branch creation/deletion"`
+	BranchPropertyStrategy *BranchPropertyStrategy `json:"branch_property_strategy,omitempty" mapstructure:"branch_property_strategy" description:"properties applied to the branch jobs"`
+	BuildStrategies        *BranchBuildStrategies  `json:"build_strategies,omitempty" mapstructure:"build_strategies" description:"strategies to decide which branches, tags and change requests are built automatically"`
+	UnknownElements        []UnknownElement        `json:"unknown_elements,omitempty" mapstructure:"unknown_elements" description:"elements of the Jenkins config which are not recognized, they are kept when updating the pipeline"`
+}
+
+// UnknownElement is an element of the Jenkins config.xml which is not modelled, such as the properties, triggers and
+// traits added through the Jenkins UI. It is written back as it is.
+type UnknownElement struct {
+	Path string `json:"path" description:"slash separated path of the parent element relative to the root element, it is empty if the parent is the root element"`
+	Tag  string `json:"tag" description:"tag of the element"`
+	XML  string `json:"xml" description:"raw XML of the element"`
 }
 
 func (b *MultiBranchPipeline) GetGitURL() string {
@@ -158,28 +208,56 @@ func (b *MultiBranchPipeline) GetGitURL() string {
 		}
 	case SourceTypeGithub:
 		if b.GitHubSource != nil {
-			return fmt.Sprintf("https://github.com/%s/%s", b.GitHubSource.Owner, b.GitHubSource.Repo)
+			server := "https://github.com"
+			if apiURI := strings.TrimSuffix(b.GitHubSource.ApiUri, "/"); apiURI != "" && apiURI != "https://api.github.com" {
+				// the API of GitHub Enterprise is served under /api/v3
+				server = strings.TrimSuffix(apiURI, "/api/v3")
+			}
+			return fmt.Sprintf("%s/%s/%s", server, b.GitHubSource.Owner, b.GitHubSource.Repo)
 		}
 	case SourceTypeGitlab:
 		if b.GitlabSource != nil {
-			return fmt.Sprintf("https://gitlab.com/%s/%s", b.GitlabSource.Owner, b.GitlabSource.Repo)
+			return fmt.Sprintf("%s/%s/%s", serverOrDefault(b.GitlabSource.ApiUri, "https://gitlab.com"),
+				b.GitlabSource.Owner, b.GitlabSource.Repo)
 		}
 	case SourceTypeBitbucket:
 		if b.BitbucketServerSource != nil {
-			return fmt.Sprintf("https://bitbucket.org/%s/%s", b.BitbucketServerSource.Owner, b.BitbucketServerSource.Repo)
+			if server := serverOrDefault(b.BitbucketServerSource.ApiUri, ""); server != "" && server != BitbucketCloudServerURL {
+				// repositories of Bitbucket Server are cloned under /scm
+				return fmt.Sprintf("%s/scm/%s/%s", server, b.BitbucketServerSource.Owner, b.BitbucketServerSource.Repo)
+			}
+			return fmt.Sprintf("%s/%s/%s", BitbucketCloudServerURL, b.BitbucketServerSource.Owner, b.BitbucketServerSource.Repo)
+		}
+	case SourceTypeBitbucketCloud:
+		if b.BitbucketCloudSource != nil {
+			return fmt.Sprintf("%s/%s/%s", BitbucketCloudServerURL, b.BitbucketCloudSource.Owner, b.BitbucketCloudSource.Repo)
+		}
+	case SourceTypeGitea:
+		// there is no public Gitea service, so the server URL is required
+		if b.GiteaSource != nil && b.GiteaSource.ServerUrl != "" {
+			return fmt.Sprintf("%s/%s/%s", serverOrDefault(b.GiteaSource.ServerUrl, ""),
+				b.GiteaSource.Owner, b.GiteaSource.Repo)
 		}
 	}
 	return ""
 }
 
+func serverOrDefault(server, defaultServer string) string {
+	if server = strings.TrimSuffix(server, "/"); server != "" {
+		return server
+	}
+	return defaultServer
+}
+
 type GitSource struct {
-	ScmId            string          `json:"scm_id,omitempty" description:"uid of scm"`
-	Url              string          `json:"url,omitempty" mapstructure:"url" description:"url of git source"`
-	CredentialId     string          `json:"credential_id,omitempty" mapstructure:"credential_id" description:"credential id to access git source"`
-	DiscoverBranches bool            `json:"discover_branches,omitempty" mapstructure:"discover_branches" description:"Whether to discover a branch"`
-	DiscoverTags     bool            `json:"discover_tags,omitempty" mapstructure:"discover_tags" description:"Discover tags configuration"`
-	CloneOption      *GitCloneOption `json:"git_clone_option,omitempty" mapstructure:"git_clone_option" description:"advavced git clone options"`
-	RegexFilter      string          `json:"regex_filter,omitempty" mapstructure:"regex_filter" description:"Regex used to match the name of the branch that needs to be run"`
+	ScmId            string             `json:"scm_id,omitempty" description:"uid of scm"`
+	Url              string             `json:"url,omitempty" mapstructure:"url" description:"url of git source"`
+	CredentialId     string             `json:"credential_id,omitempty" mapstructure:"credential_id" description:"credential id to access git source"`
+	DiscoverBranches bool               `json:"discover_branches,omitempty" mapstructure:"discover_branches" description:"Whether to discover a branch"`
+	DiscoverTags     bool               `json:"discover_tags,omitempty" mapstructure:"discover_tags" description:"Discover tags configuration"`
+	CloneOption      *GitCloneOption    `json:"git_clone_option,omitempty" mapstructure:"git_clone_option" description:"advavced git clone options"`
+	CheckoutOption   *GitCheckoutOption `json:"git_checkout_option,omitempty" mapstructure:"git_checkout_option" description:"advanced git checkout options"`
+	RegexFilter      string             `json:"regex_filter,omitempty" mapstructure:"regex_filter" description:"Regex used to match the name of the branch that needs to be run"`
 }
 
 // GithubSource and BitbucketServerSource have the same structure, but we don't use one due to crd errors
@@ -194,6 +272,7 @@ type GithubSource struct {
 	DiscoverPRFromForks       *DiscoverPRFromForks `json:"discover_pr_from_forks,omitempty" mapstructure:"discover_pr_from_forks" description:"Discover fork PR configuration"`
 	DiscoverTags              bool                 `json:"discover_tags,omitempty" mapstructure:"discover_tags" description:"Discover tag configuration"`
 	CloneOption               *GitCloneOption      `json:"git_clone_option,omitempty" mapstructure:"git_clone_option" description:"advavced git clone options"`
+	CheckoutOption            *GitCheckoutOption   `json:"git_checkout_option,omitempty" mapstructure:"git_checkout_option" description:"advanced git checkout options"`
 	RegexFilter               string               `json:"regex_filter,omitempty" mapstructure:"regex_filter" description:"Regex used to match the name of the branch that needs to be run"`
 	AcceptJenkinsNotification bool                 `json:"accept_jenkins_notification,omitempty"  mapstructure:"accept_jenkins_notification" description:"Allow Jenkins send build status notification to Github"`
 }
@@ -210,6 +289,7 @@ type GitlabSource struct {
 	DiscoverPRFromForks       *DiscoverPRFromForks `json:"discover_pr_from_forks,omitempty" mapstructure:"discover_pr_from_forks" description:"Discover fork PR configuration"`
 	DiscoverTags              bool                 `json:"discover_tags,omitempty" mapstructure:"discover_tags" description:"Discover tags configuration"`
 	CloneOption               *GitCloneOption      `json:"git_clone_option,omitempty" mapstructure:"git_clone_option" description:"advavced git clone options"`
+	CheckoutOption            *GitCheckoutOption   `json:"git_checkout_option,omitempty" mapstructure:"git_checkout_option" description:"advanced git checkout options"`
 	RegexFilter               string               `json:"regex_filter,omitempty" mapstructure:"regex_filter" description:"Regex used to match the name of the branch that needs to be run"`
 	AcceptJenkinsNotification bool                 `json:"accept_jenkins_notification,omitempty"  mapstructure:"accept_jenkins_notification" description:"Allow Jenkins send build status notification to Gitlab"`
 }
@@ -225,19 +305,145 @@ type BitbucketServerSource struct {
 	DiscoverPRFromForks       *DiscoverPRFromForks `json:"discover_pr_from_forks,omitempty" mapstructure:"discover_pr_from_forks" description:"Discover fork PR configuration"`
 	DiscoverTags              bool                 `json:"discover_tags,omitempty" mapstructure:"discover_tags" description:"Discover tag configuration"`
 	CloneOption               *GitCloneOption      `json:"git_clone_option,omitempty" mapstructure:"git_clone_option" description:"advavced git clone options"`
+	CheckoutOption            *GitCheckoutOption   `json:"git_checkout_option,omitempty" mapstructure:"git_checkout_option" description:"advanced git checkout options"`
+	RegexFilter               string               `json:"regex_filter,omitempty" mapstructure:"regex_filter" description:"Regex used to match the name of the branch that needs to be run"`
+	AcceptJenkinsNotification bool                 `json:"accept_jenkins_notification,omitempty"  mapstructure:"accept_jenkins_notification" description:"Allow Jenkins send build status notification to Bitbucket"`
+}
+
+type BitbucketCloudSource struct {
+	ScmId                     string               `json:"scm_id,omitempty" description:"uid of scm"`
+	Owner                     string               `json:"owner,omitempty" mapstructure:"owner" description:"workspace of bitbucket cloud repo"`
+	Repo                      string               `json:"repo,omitempty" mapstructure:"repo" description:"repo name of bitbucket cloud repo"`
+	CredentialId              string               `json:"credential_id,omitempty" mapstructure:"credential_id" description:"credential id to access bitbucket cloud source"`
+	DiscoverBranches          int                  `json:"discover_branches,omitempty" mapstructure:"discover_branches" description:"Discover branch configuration"`
+	DiscoverPRFromOrigin      int                  `json:"discover_pr_from_origin,omitempty" mapstructure:"discover_pr_from_origin" description:"Discover origin PR configuration"`
+	DiscoverPRFromForks       *DiscoverPRFromForks `json:"discover_pr_from_forks,omitempty" mapstructure:"discover_pr_from_forks" description:"Discover fork PR configuration"`
+	DiscoverTags              bool                 `json:"discover_tags,omitempty" mapstructure:"discover_tags" description:"Discover tag configuration"`
+	CloneOption               *GitCloneOption      `json:"git_clone_option,omitempty" mapstructure:"git_clone_option" description:"advavced git clone options"`
+	CheckoutOption            *GitCheckoutOption   `json:"git_checkout_option,omitempty" mapstructure:"git_checkout_option" description:"advanced git checkout options"`
 	RegexFilter               string               `json:"regex_filter,omitempty" mapstructure:"regex_filter" description:"Regex used to match the name of the branch that needs to be run"`
 	AcceptJenkinsNotification bool                 `json:"accept_jenkins_notification,omitempty"  mapstructure:"accept_jenkins_notification" description:"Allow Jenkins send build status notification to Bitbucket"`
 }
 
+type GiteaSource struct {
+	ScmId                string               `json:"scm_id,omitempty" description:"uid of scm"`
+	ServerUrl            string               `json:"server_url,omitempty" mapstructure:"server_url" description:"url of the gitea server which was configured in jenkins"`
+	Owner                string               `json:"owner,omitempty" mapstructure:"owner" description:"owner of gitea repo"`
+	Repo                 string               `json:"repo,omitempty" mapstructure:"repo" description:"repo name of gitea repo"`
+	CredentialId         string               `json:"credential_id,omitempty" mapstructure:"credential_id" description:"credential id to access gitea source"`
+	DiscoverBranches     int                  `json:"discover_branches,omitempty" mapstructure:"discover_branches" description:"Discover branch configuration"`
+	DiscoverPRFromOrigin int                  `json:"discover_pr_from_origin,omitempty" mapstructure:"discover_pr_from_origin" description:"Discover origin PR configuration"`
+	DiscoverPRFromForks  *DiscoverPRFromForks `json:"discover_pr_from_forks,omitempty" mapstructure:"discover_pr_from_forks" description:"Discover fork PR configuration"`
+	DiscoverTags         bool                 `json:"discover_tags,omitempty" mapstructure:"discover_tags" description:"Discover tag configuration"`
+	CloneOption          *GitCloneOption      `json:"git_clone_option,omitempty" mapstructure:"git_clone_option" description:"advavced git clone options"`
+	RegexFilter          string               `json:"regex_filter,omitempty" mapstructure:"regex_filter" description:"Regex used to match the name of the branch that needs to be run"`
+}
+
+// Navigators scan the repositories of an organization folder
+const (
+	NavigatorTypeGithub    = "github"
+	NavigatorTypeGitlab    = "gitlab"
+	NavigatorTypeBitbucket = "bitbucket"
+)
+
+type OrganizationFolder struct {
+	Name               string                    `json:"name" description:"name of organization folder"`
+	Description        string                    `json:"description,omitempty" description:"description of organization folder"`
+	Navigator          string                    `json:"navigator" description:"type of the navigator, such as github/gitlab/bitbucket"`
+	ApiUri             string                    `json:"api_uri,omitempty" mapstructure:"api_uri" description:"api url of GitHub Enterprise or the server url of Bitbucket, the public service is used if it is empty"`
+	ServerName         string                    `json:"server_name,omitempty" mapstructure:"server_name" description:"name of the gitlab server which was configured in jenkins"`
+	Owner              string                    `json:"owner" description:"GitHub organization, GitLab group or Bitbucket project"`
+	CredentialId       string                    `json:"credential_id,omitempty" mapstructure:"credential_id" description:"credential id to scan the repositories"`
+	RepositoryFilter   string                    `json:"repository_filter,omitempty" mapstructure:"repository_filter" description:"Regex used to match the name of the repositories that need to be scanned"`
+	ScriptPath         string                    `json:"script_path" mapstructure:"script_path" description:"script path used to recognize the projects"`
+	Traits             *OrganizationFolderTraits `json:"traits,omitempty" description:"traits applied to every repository"`
+	TimerTrigger       *TimerTrigger             `json:"timer_trigger,omitempty" mapstructure:"timer_trigger" description:"Timer to scan the organization"`
+	OrphanedItemPolicy *DiscarderProperty        `json:"orphaned_item_policy,omitempty" mapstructure:"orphaned_item_policy" description:"Discarder of the repositories which are no longer found"`
+}
+
+type OrganizationFolderTraits struct {
+	DiscoverBranches     int                  `json:"discover_branches,omitempty" mapstructure:"discover_branches" description:"Discover branch configuration"`
+	DiscoverPRFromOrigin int                  `json:"discover_pr_from_origin,omitempty" mapstructure:"discover_pr_from_origin" description:"Discover origin PR configuration"`
+	DiscoverPRFromForks  *DiscoverPRFromForks `json:"discover_pr_from_forks,omitempty" mapstructure:"discover_pr_from_forks" description:"Discover fork PR configuration"`
+	DiscoverTags         bool                 `json:"discover_tags,omitempty" mapstructure:"discover_tags" description:"Discover tag configuration"`
+	CloneOption          *GitCloneOption      `json:"git_clone_option,omitempty" mapstructure:"git_clone_option" description:"advavced git clone options"`
+	CheckoutOption       *GitCheckoutOption   `json:"git_checkout_option,omitempty" mapstructure:"git_checkout_option" description:"advanced git checkout options"`
+	RegexFilter          string               `json:"regex_filter,omitempty" mapstructure:"regex_filter" description:"Regex used to match the name of the branch that needs to be run"`
+}
+
+// Durability hints of the pipelines and the Pipeline branch jobs
+const (
+	DurabilityHintPerformanceOptimized = "PERFORMANCE_OPTIMIZED"
+	DurabilityHintSurvivableNonAtomic  = "SURVIVABLE_NONATOMIC"
+	DurabilityHintMaxSurvivability     = "MAX_SURVIVABILITY"
+)
+
+// BranchPropertyStrategy applies the default properties to all branches except the ones matching a named exception
+type BranchPropertyStrategy struct {
+	DefaultProperties *BranchProperties       `json:"default_properties,omitempty" mapstructure:"default_properties" description:"properties of the branches which do not match any named exception"`
+	NamedExceptions   []NamedBranchProperties `json:"named_exceptions,omitempty" mapstructure:"named_exceptions" description:"properties of the branches matching the name patterns"`
+}
+
+type NamedBranchProperties struct {
+	Name       string           `json:"name" mapstructure:"name" description:"comma separated branch name patterns, wildcards are supported"`
+	Properties BranchProperties `json:"properties" mapstructure:"properties" description:"properties of the matched branches"`
+}
+
+type BranchProperties struct {
+	SuppressAutomaticTriggering bool   `json:"suppress_automatic_triggering,omitempty" mapstructure:"suppress_automatic_triggering" description:"Whether to suppress the automatic SCM triggering"`
+	DurabilityHint              string `json:"durability_hint,omitempty" mapstructure:"durability_hint" description:"durability hint of the pipeline, such as PERFORMANCE_OPTIMIZED/SURVIVABLE_NONATOMIC/MAX_SURVIVABILITY"`
+	UntrustedBranchProtection   bool   `json:"untrusted_branch_protection,omitempty" mapstructure:"untrusted_branch_protection" description:"Whether to prevent the untrusted branches from publishing results"`
+}
+
+// BranchBuildStrategies decides which heads are built automatically, Jenkins builds regular branches and change requests
+// only when there is no build strategy at all
+type BranchBuildStrategies struct {
+	Tags             *TagBuildStrategy           `json:"tags,omitempty" mapstructure:"tags" description:"build tags, tags are never built automatically if it is empty"`
+	ChangeRequests   *ChangeRequestBuildStrategy `json:"change_requests,omitempty" mapstructure:"change_requests" description:"build change requests"`
+	RegularBranches  bool                        `json:"regular_branches,omitempty" mapstructure:"regular_branches" description:"Whether to build regular branches"`
+	SkipInitialBuild bool                        `json:"skip_initial_build,omitempty" mapstructure:"skip_initial_build" description:"Whether to skip the build on the first branch indexing"`
+}
+
+type TagBuildStrategy struct {
+	AtLeastDays int `json:"at_least_days,omitempty" mapstructure:"at_least_days" description:"ignore the tags newer than the days, no limitation if it is zero"`
+	AtMostDays  int `json:"at_most_days,omitempty" mapstructure:"at_most_days" description:"ignore the tags older than the days, no limitation if it is zero"`
+}
+
+type ChangeRequestBuildStrategy struct {
+	IgnoreTargetOnlyChanges bool `json:"ignore_target_only_changes,omitempty" mapstructure:"ignore_target_only_changes" description:"Whether to ignore the rebuild when only the target branch changes"`
+	IgnoreUntrustedChanges  bool `json:"ignore_untrusted_changes,omitempty" mapstructure:"ignore_untrusted_changes" description:"Whether to ignore the changes from the untrusted forks"`
+}
+
 type MultiBranchJobTrigger struct {
 	CreateActionJobsToTrigger string `json:"create_action_job_to_trigger,omitempty" description:"pipeline name to trigger"`
 	DeleteActionJobsToTrigger string `json:"delete_action_job_to_trigger,omitempty" description:"pipeline name to trigger"`
 }
 
 type GitCloneOption struct {
-	Shallow bool `json:"shallow,omitempty" mapstructure:"shallow" description:"Whether to use git shallow clone"`
-	Timeout int  `json:"timeout,omitempty" mapstructure:"timeout" description:"git clone timeout mins"`
-	Depth   int  `json:"depth,omitempty" mapstructure:"depth" description:"git clone depth"`
+	Shallow   bool   `json:"shallow,omitempty" mapstructure:"shallow" description:"Whether to use git shallow clone"`
+	Timeout   int    `json:"timeout,omitempty" mapstructure:"timeout" description:"git clone timeout mins"`
+	Depth     int    `json:"depth,omitempty" mapstructure:"depth" description:"git clone depth"`
+	NoTags    bool   `json:"no_tags,omitempty" mapstructure:"no_tags" description:"Whether to skip fetching tags when cloning"`
+	Reference string `json:"reference,omitempty" mapstructure:"reference" description:"path of a local repository used as the reference to speed up cloning"`
+}
+
+// GitCheckoutOption holds the behaviours applied when Jenkins checks out a branch
+type GitCheckoutOption struct {
+	Submodule           *GitSubmoduleOption `json:"submodule,omitempty" mapstructure:"submodule" description:"git submodule options, submodules are not updated if it is empty"`
+	LFS                 bool                `json:"lfs,omitempty" mapstructure:"lfs" description:"Whether to pull Git LFS files after checkout"`
+	SparseCheckoutPaths []string            `json:"sparse_checkout_paths,omitempty" mapstructure:"sparse_checkout_paths" description:"paths to check out, the whole repository is checked out if it is empty"`
+	CleanBeforeCheckout bool                `json:"clean_before_checkout,omitempty" mapstructure:"clean_before_checkout" description:"Whether to clean the workspace before checkout"`
+	CleanAfterCheckout  bool                `json:"clean_after_checkout,omitempty" mapstructure:"clean_after_checkout" description:"Whether to clean the workspace after checkout"`
+	LocalBranch         bool                `json:"local_branch,omitempty" mapstructure:"local_branch" description:"Whether to check out to a local branch with the same name as the remote one"`
+	RefSpecs            []string            `json:"ref_specs,omitempty" mapstructure:"ref_specs" description:"refspecs used to override the default ones when fetching"`
+}
+
+type GitSubmoduleOption struct {
+	Recursive         bool `json:"recursive,omitempty" mapstructure:"recursive" description:"Whether to update submodules recursively"`
+	Shallow           bool `json:"shallow,omitempty" mapstructure:"shallow" description:"Whether to use shallow clone for submodules"`
+	Depth             int  `json:"depth,omitempty" mapstructure:"depth" description:"submodule clone depth, works with shallow clone only"`
+	ParentCredentials bool `json:"parent_credentials,omitempty" mapstructure:"parent_credentials" description:"Whether to use the credentials of the parent repository"`
+	Timeout           int  `json:"timeout,omitempty" mapstructure:"timeout" description:"submodule update timeout mins"`
 }
 
 type SvnSource struct {
@@ -259,8 +465,32 @@ type DiscoverPRFromForks struct {
 }
 
 type DiscarderProperty struct {
-	DaysToKeep string `json:"days_to_keep,omitempty" mapstructure:"days_to_keep" description:"days to keep pipeline"`
-	NumToKeep  string `json:"num_to_keep,omitempty" mapstructure:"num_to_keep" description:"nums to keep pipeline"`
+	DaysToKeep         string `json:"days_to_keep,omitempty" mapstructure:"days_to_keep" description:"days to keep pipeline"`
+	NumToKeep          string `json:"num_to_keep,omitempty" mapstructure:"num_to_keep" description:"nums to keep pipeline"`
+	ArtifactDaysToKeep string `json:"artifact_days_to_keep,omitempty" mapstructure:"artifact_days_to_keep" description:"days to keep the artifacts of pipeline, the artifacts are kept as long as the builds if it is empty"`
+	ArtifactNumToKeep  string `json:"artifact_num_to_keep,omitempty" mapstructure:"artifact_num_to_keep" description:"nums of builds to keep the artifacts, the artifacts are kept as long as the builds if it is empty"`
+}
+
+type GithubProjectProperty struct {
+	ProjectURL  string `json:"project_url" mapstructure:"project_url" description:"URL of the GitHub project, such as https://github.com/opswave/go-jenkins/"`
+	DisplayName string `json:"display_name,omitempty" mapstructure:"display_name" description:"display name of the project in the commit status"`
+}
+
+type GitlabConnectionProperty struct {
+	Connection string `json:"connection" description:"name of the GitLab connection configured in Jenkins"`
+}
+
+type RateLimitProperty struct {
+	Count        int    `json:"count" description:"max number of builds in the duration"`
+	DurationName string `json:"duration_name,omitempty" mapstructure:"duration_name" description:"the duration, such as second/minute/hour/day/week/month/year, default is hour"`
+	UserBoost    bool   `json:"user_boost,omitempty" mapstructure:"user_boost" description:"the builds started by users are not limited"`
+}
+
+type LockableResourcesProperty struct {
+	ResourceNames []string `json:"resource_names,omitempty" mapstructure:"resource_names" description:"names of the resources to lock"`
+	Label         string   `json:"label,omitempty" description:"label of the resources to lock, it is ignored if resource names are given"`
+	Quantity      int      `json:"quantity,omitempty" description:"number of the resources with the label to lock, all of them are locked if it is zero"`
+	Variable      string   `json:"variable,omitempty" description:"name of the environment variable holding the locked resource names"`
 }
 
 type ParameterDefinition struct {
@@ -268,11 +498,49 @@ type ParameterDefinition struct {
 	DefaultValue string `json:"default_value,omitempty" yaml:"default_value" mapstructure:"default_value" description:"default value of param"`
 	Type         string `json:"type" description:"type of param"`
 	Description  string `json:"description,omitempty" description:"description of pipeline"`
+
+	Trim           bool                           `json:"trim,omitempty" description:"trim the leading and trailing whitespaces of the value, only for string param"`
+	Validation     *StringParameterValidation     `json:"validation,omitempty" description:"regex validation of the value, only for validating_string param"`
+	Credentials    *CredentialsParameterOption    `json:"credentials,omitempty" description:"options of credentials param"`
+	Run            *RunParameterOption            `json:"run,omitempty" description:"options of run param"`
+	ExtendedChoice *ExtendedChoiceParameterOption `json:"extended_choice,omitempty" mapstructure:"extended_choice" description:"options of extended_choice param"`
+}
+
+// the select types of the extended choice parameters
+const (
+	ExtendedChoiceSingleSelect = "single_select"
+	ExtendedChoiceMultiSelect  = "multi_select"
+	ExtendedChoiceCheckbox     = "checkbox"
+	ExtendedChoiceRadio        = "radio"
+)
+
+type StringParameterValidation struct {
+	Regex                   string `json:"regex" description:"regular expression which the whole value must match"`
+	FailedValidationMessage string `json:"failed_validation_message,omitempty" mapstructure:"failed_validation_message" description:"message shown when the value does not match"`
+}
+
+type CredentialsParameterOption struct {
+	CredentialType string `json:"credential_type,omitempty" mapstructure:"credential_type" description:"class of the credentials which can be selected, all credentials can be selected if it is empty"`
+	Required       bool   `json:"required,omitempty" description:"a credential must be selected"`
+}
+
+type RunParameterOption struct {
+	ProjectName string `json:"project_name" mapstructure:"project_name" description:"full name of the job whose builds can be selected"`
+	Filter      string `json:"filter,omitempty" description:"filter of the builds, such as ALL/STABLE/SUCCESSFUL/COMPLETED"`
+}
+
+type ExtendedChoiceParameterOption struct {
+	SelectType       string   `json:"select_type,omitempty" mapstructure:"select_type" description:"type of the selection, such as single_select/multi_select/checkbox/radio"`
+	Choices          []string `json:"choices,omitempty" description:"values which can be selected"`
+	Delimiter        string   `json:"delimiter,omitempty" description:"delimiter of the selected values, default is comma"`
+	VisibleItemCount int      `json:"visible_item_count,omitempty" mapstructure:"visible_item_count" description:"number of visible items"`
 }
 
 type TimerTrigger struct {
 	// user in no scm job
 	Cron string `json:"cron,omitempty" description:"jenkins cron script"`
+	// Timezone is written as the TZ= line before the cron script, such as Asia/Shanghai, use in no scm job
+	Timezone string `json:"timezone,omitempty" description:"time zone of the cron script, e.g. Asia/Shanghai"`
 
 	// use in multi-branch job
 	Interval string `json:"interval,omitempty" description:"interval ms"`
@@ -299,6 +567,35 @@ type GenericVariable struct {
 	RegexpFilter string `json:"regexp_filter,omitempty" description:"A regexp filter which take value from HTTP request, or header etc."`
 }
 
+// the result thresholds of the upstream builds
+const (
+	UpstreamThresholdSuccess  = "SUCCESS"
+	UpstreamThresholdUnstable = "UNSTABLE"
+	UpstreamThresholdFailure  = "FAILURE"
+)
+
+type UpstreamTrigger struct {
+	Projects  []string `json:"projects" description:"Names of the upstream pipelines, relative to the folder of the pipeline or absolute such as project/pipeline"`
+	Threshold string   `json:"threshold,omitempty" description:"Trigger only if the result of the upstream build is better than or equal to it, such as SUCCESS/UNSTABLE/FAILURE, default is SUCCESS"`
+}
+
+type ScmTrigger struct {
+	Schedule              string `json:"schedule,omitempty" description:"jenkins cron script of polling, the scm is only polled by the post-commit hooks if it is empty"`
+	IgnorePostCommitHooks bool   `json:"ignore_post_commit_hooks,omitempty" mapstructure:"ignore_post_commit_hooks" description:"Whether to ignore the post-commit hooks"`
+}
+
+// GithubPushTrigger has no option, the repository is taken from the scm of the pipeline
+type GithubPushTrigger struct {
+}
+
+type GitlabPushTrigger struct {
+	TriggerOnPush         bool   `json:"trigger_on_push,omitempty" mapstructure:"trigger_on_push" description:"Trigger on the push events"`
+	TriggerOnMergeRequest bool   `json:"trigger_on_merge_request,omitempty" mapstructure:"trigger_on_merge_request" description:"Trigger on the opened merge request events"`
+	IncludeBranches       string `json:"include_branches,omitempty" mapstructure:"include_branches" description:"Comma separated names of the branches which trigger the pipeline, all branches trigger if both include and exclude branches are empty"`
+	ExcludeBranches       string `json:"exclude_branches,omitempty" mapstructure:"exclude_branches" description:"Comma separated names of the branches which do not trigger the pipeline"`
+	SecretToken           string `json:"secret_token,omitempty" mapstructure:"secret_token" description:"Secret token of the webhook, Jenkins returns it encrypted"`
+}
+
 func init() {
 	SchemeBuilder.Register(&Pipeline{}, &PipelineList{})
 }
@@ -70,6 +70,8 @@ func TestMultiBranchPipeline_GetGitURL(t *testing.T) {
 		GitHubSource          *GithubSource
 		GitlabSource          *GitlabSource
 		BitbucketServerSource *BitbucketServerSource
+		BitbucketCloudSource  *BitbucketCloudSource
+		GiteaSource           *GiteaSource
 	}
 	tests := []struct {
 		name   string
@@ -82,6 +84,13 @@ func TestMultiBranchPipeline_GetGitURL(t *testing.T) {
 			GitHubSource: &GithubSource{Owner: "linuxsuren", Repo: "tools"},
 		},
 		want: "https://github.com/linuxsuren/tools",
+	}, {
+		name: "github enterprise",
+		fields: fields{
+			SourceType:   SourceTypeGithub,
+			GitHubSource: &GithubSource{Owner: "linuxsuren", Repo: "tools", ApiUri: "https://github.example.com/api/v3/"},
+		},
+		want: "https://github.example.com/linuxsuren/tools",
 	}, {
 		name: "gitlab",
 		fields: fields{
@@ -89,6 +98,13 @@ func TestMultiBranchPipeline_GetGitURL(t *testing.T) {
 			GitlabSource: &GitlabSource{Owner: "linuxsuren", Repo: "tools"},
 		},
 		want: "https://gitlab.com/linuxsuren/tools",
+	}, {
+		name: "self-hosted gitlab",
+		fields: fields{
+			SourceType:   SourceTypeGitlab,
+			GitlabSource: &GitlabSource{Owner: "linuxsuren", Repo: "tools", ApiUri: "https://gitlab.example.com"},
+		},
+		want: "https://gitlab.example.com/linuxsuren/tools",
 	}, {
 		name: "git",
 		fields: fields{
@@ -103,6 +119,41 @@ func TestMultiBranchPipeline_GetGitURL(t *testing.T) {
 			BitbucketServerSource: &BitbucketServerSource{Owner: "linuxsuren", Repo: "tools"},
 		},
 		want: "https://bitbucket.org/linuxsuren/tools",
+	}, {
+		name: "bitbucket server",
+		fields: fields{
+			SourceType:            SourceTypeBitbucket,
+			BitbucketServerSource: &BitbucketServerSource{Owner: "linuxsuren", Repo: "tools", ApiUri: "https://bitbucket.example.com/"},
+		},
+		want: "https://bitbucket.example.com/scm/linuxsuren/tools",
+	}, {
+		name: "bitbucket server pointing to bitbucket cloud",
+		fields: fields{
+			SourceType:            SourceTypeBitbucket,
+			BitbucketServerSource: &BitbucketServerSource{Owner: "linuxsuren", Repo: "tools", ApiUri: "https://bitbucket.org/"},
+		},
+		want: "https://bitbucket.org/linuxsuren/tools",
+	}, {
+		name: "bitbucket cloud",
+		fields: fields{
+			SourceType:           SourceTypeBitbucketCloud,
+			BitbucketCloudSource: &BitbucketCloudSource{Owner: "linuxsuren", Repo: "tools"},
+		},
+		want: "https://bitbucket.org/linuxsuren/tools",
+	}, {
+		name: "gitea",
+		fields: fields{
+			SourceType:  SourceTypeGitea,
+			GiteaSource: &GiteaSource{ServerUrl: "https://gitea.example.com/", Owner: "linuxsuren", Repo: "tools"},
+		},
+		want: "https://gitea.example.com/linuxsuren/tools",
+	}, {
+		name: "gitea without server",
+		fields: fields{
+			SourceType:  SourceTypeGitea,
+			GiteaSource: &GiteaSource{Owner: "linuxsuren", Repo: "tools"},
+		},
+		want: "",
 	}, {
 		name: "fake",
 		fields: fields{
@@ -119,6 +170,8 @@ func TestMultiBranchPipeline_GetGitURL(t *testing.T) {
 				GitHubSource:          tt.fields.GitHubSource,
 				GitlabSource:          tt.fields.GitlabSource,
 				BitbucketServerSource: tt.fields.BitbucketServerSource,
+				BitbucketCloudSource:  tt.fields.BitbucketCloudSource,
+				GiteaSource:           tt.fields.GiteaSource,
 			}
 			assert.Equalf(t, tt.want, b.GetGitURL(), "GetGitURL()")
 		})
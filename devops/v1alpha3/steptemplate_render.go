@@ -75,7 +75,8 @@ func jsonFormat(content string) string {
 
 func wrapWithCredential(secretType, secretName, target string) string {
 	switch secretType {
-	case string(v1.SecretTypeBasicAuth), string(SecretTypeBasicAuth):
+	case string(v1.SecretTypeBasicAuth), string(SecretTypeBasicAuth),
+		string(SecretTypeGitHubApp), string(SecretTypeDockerRegistry):
 		target = fmt.Sprintf(`{
       "arguments": {
         "isLiteral": false,
@@ -110,6 +111,33 @@ func wrapWithCredential(secretType, secretName, target string) string {
   },
   "children": [%s],
   "name": "withCredentials"
+}`, secretName, target)
+	case string(SecretTypeCertificate):
+		target = fmt.Sprintf(`{
+  "arguments": {
+    "isLiteral": false,
+    "value": "${[certificate(credentialsId: '%s', keystoreVariable: 'KEYSTOREVARIABLE' ,passwordVariable: 'PASSWORDVARIABLE')]}"
+  },
+  "children": [%s],
+  "name": "withCredentials"
+}`, secretName, target)
+	case string(SecretTypeSecretFile):
+		target = fmt.Sprintf(`{
+  "arguments": {
+    "isLiteral": false,
+    "value": "${[file(credentialsId: '%s', variable: 'VARIABLE')]}"
+  },
+  "children": [%s],
+  "name": "withCredentials"
+}`, secretName, target)
+	case string(SecretTypeAWSAccessKey):
+		target = fmt.Sprintf(`{
+  "arguments": {
+    "isLiteral": false,
+    "value": "${[aws(credentialsId: '%s', accessKeyVariable: 'ACCESSKEYVARIABLE' ,secretKeyVariable: 'SECRETKEYVARIABLE')]}"
+  },
+  "children": [%s],
+  "name": "withCredentials"
 }`, secretName, target)
 	}
 	return jsonFormat(target)
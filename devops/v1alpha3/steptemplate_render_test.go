@@ -180,6 +180,46 @@ func Test_wrapWithCredential(t *testing.T) {
 			target:     "echo 1",
 		},
 		want: readFile("testdata/credential-ssh.json"),
+	}, {
+		name: "secret as certificate type",
+		args: args{
+			secretType: string(SecretTypeCertificate),
+			secretName: "config",
+			target:     "echo 1",
+		},
+		want: readFile("testdata/credential-certificate.json"),
+	}, {
+		name: "secret as secret file type",
+		args: args{
+			secretType: string(SecretTypeSecretFile),
+			secretName: "config",
+			target:     "echo 1",
+		},
+		want: readFile("testdata/credential-file.json"),
+	}, {
+		name: "secret as GitHub App type",
+		args: args{
+			secretType: string(SecretTypeGitHubApp),
+			secretName: "config",
+			target:     "echo 1",
+		},
+		want: readFile("testdata/credential-username-password.json"),
+	}, {
+		name: "secret as docker registry type",
+		args: args{
+			secretType: string(SecretTypeDockerRegistry),
+			secretName: "config",
+			target:     "echo 1",
+		},
+		want: readFile("testdata/credential-username-password.json"),
+	}, {
+		name: "secret as AWS access key type",
+		args: args{
+			secretType: string(SecretTypeAWSAccessKey),
+			secretName: "config",
+			target:     "echo 1",
+		},
+		want: readFile("testdata/credential-aws.json"),
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
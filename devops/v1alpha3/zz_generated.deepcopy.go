@@ -294,6 +294,36 @@ func (in *Argo) DeepCopy() *Argo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BitbucketCloudSource) DeepCopyInto(out *BitbucketCloudSource) {
+	*out = *in
+	if in.DiscoverPRFromForks != nil {
+		in, out := &in.DiscoverPRFromForks, &out.DiscoverPRFromForks
+		*out = new(DiscoverPRFromForks)
+		**out = **in
+	}
+	if in.CloneOption != nil {
+		in, out := &in.CloneOption, &out.CloneOption
+		*out = new(GitCloneOption)
+		**out = **in
+	}
+	if in.CheckoutOption != nil {
+		in, out := &in.CheckoutOption, &out.CheckoutOption
+		*out = new(GitCheckoutOption)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BitbucketCloudSource.
+func (in *BitbucketCloudSource) DeepCopy() *BitbucketCloudSource {
+	if in == nil {
+		return nil
+	}
+	out := new(BitbucketCloudSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BitbucketServerSource) DeepCopyInto(out *BitbucketServerSource) {
 	*out = *in
@@ -307,6 +337,11 @@ func (in *BitbucketServerSource) DeepCopyInto(out *BitbucketServerSource) {
 		*out = new(GitCloneOption)
 		**out = **in
 	}
+	if in.CheckoutOption != nil {
+		in, out := &in.CheckoutOption, &out.CheckoutOption
+		*out = new(GitCheckoutOption)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BitbucketServerSource.
@@ -319,6 +354,86 @@ func (in *BitbucketServerSource) DeepCopy() *BitbucketServerSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BranchBuildStrategies) DeepCopyInto(out *BranchBuildStrategies) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = new(TagBuildStrategy)
+		**out = **in
+	}
+	if in.ChangeRequests != nil {
+		in, out := &in.ChangeRequests, &out.ChangeRequests
+		*out = new(ChangeRequestBuildStrategy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchBuildStrategies.
+func (in *BranchBuildStrategies) DeepCopy() *BranchBuildStrategies {
+	if in == nil {
+		return nil
+	}
+	out := new(BranchBuildStrategies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BranchProperties) DeepCopyInto(out *BranchProperties) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchProperties.
+func (in *BranchProperties) DeepCopy() *BranchProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(BranchProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BranchPropertyStrategy) DeepCopyInto(out *BranchPropertyStrategy) {
+	*out = *in
+	if in.DefaultProperties != nil {
+		in, out := &in.DefaultProperties, &out.DefaultProperties
+		*out = new(BranchProperties)
+		**out = **in
+	}
+	if in.NamedExceptions != nil {
+		in, out := &in.NamedExceptions, &out.NamedExceptions
+		*out = make([]NamedBranchProperties, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchPropertyStrategy.
+func (in *BranchPropertyStrategy) DeepCopy() *BranchPropertyStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(BranchPropertyStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeRequestBuildStrategy) DeepCopyInto(out *ChangeRequestBuildStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeRequestBuildStrategy.
+func (in *ChangeRequestBuildStrategy) DeepCopy() *ChangeRequestBuildStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeRequestBuildStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterStepTemplate) DeepCopyInto(out *ClusterStepTemplate) {
 	*out = *in
@@ -454,6 +569,21 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialsParameterOption) DeepCopyInto(out *CredentialsParameterOption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialsParameterOption.
+func (in *CredentialsParameterOption) DeepCopy() *CredentialsParameterOption {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialsParameterOption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DevOpsProject) DeepCopyInto(out *DevOpsProject) {
 	*out = *in
@@ -578,6 +708,26 @@ func (in *DiscoverPRFromForks) DeepCopy() *DiscoverPRFromForks {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtendedChoiceParameterOption) DeepCopyInto(out *ExtendedChoiceParameterOption) {
+	*out = *in
+	if in.Choices != nil {
+		in, out := &in.Choices, &out.Choices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtendedChoiceParameterOption.
+func (in *ExtendedChoiceParameterOption) DeepCopy() *ExtendedChoiceParameterOption {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtendedChoiceParameterOption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GenericVariable) DeepCopyInto(out *GenericVariable) {
 	*out = *in
@@ -618,6 +768,36 @@ func (in *GenericWebhook) DeepCopy() *GenericWebhook {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitCheckoutOption) DeepCopyInto(out *GitCheckoutOption) {
+	*out = *in
+	if in.Submodule != nil {
+		in, out := &in.Submodule, &out.Submodule
+		*out = new(GitSubmoduleOption)
+		**out = **in
+	}
+	if in.SparseCheckoutPaths != nil {
+		in, out := &in.SparseCheckoutPaths, &out.SparseCheckoutPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RefSpecs != nil {
+		in, out := &in.RefSpecs, &out.RefSpecs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitCheckoutOption.
+func (in *GitCheckoutOption) DeepCopy() *GitCheckoutOption {
+	if in == nil {
+		return nil
+	}
+	out := new(GitCheckoutOption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitCloneOption) DeepCopyInto(out *GitCloneOption) {
 	*out = *in
@@ -740,6 +920,11 @@ func (in *GitSource) DeepCopyInto(out *GitSource) {
 		*out = new(GitCloneOption)
 		**out = **in
 	}
+	if in.CheckoutOption != nil {
+		in, out := &in.CheckoutOption, &out.CheckoutOption
+		*out = new(GitCheckoutOption)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitSource.
@@ -752,6 +937,76 @@ func (in *GitSource) DeepCopy() *GitSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitSubmoduleOption) DeepCopyInto(out *GitSubmoduleOption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitSubmoduleOption.
+func (in *GitSubmoduleOption) DeepCopy() *GitSubmoduleOption {
+	if in == nil {
+		return nil
+	}
+	out := new(GitSubmoduleOption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GiteaSource) DeepCopyInto(out *GiteaSource) {
+	*out = *in
+	if in.DiscoverPRFromForks != nil {
+		in, out := &in.DiscoverPRFromForks, &out.DiscoverPRFromForks
+		*out = new(DiscoverPRFromForks)
+		**out = **in
+	}
+	if in.CloneOption != nil {
+		in, out := &in.CloneOption, &out.CloneOption
+		*out = new(GitCloneOption)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GiteaSource.
+func (in *GiteaSource) DeepCopy() *GiteaSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GiteaSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GithubProjectProperty) DeepCopyInto(out *GithubProjectProperty) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubProjectProperty.
+func (in *GithubProjectProperty) DeepCopy() *GithubProjectProperty {
+	if in == nil {
+		return nil
+	}
+	out := new(GithubProjectProperty)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GithubPushTrigger) DeepCopyInto(out *GithubPushTrigger) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubPushTrigger.
+func (in *GithubPushTrigger) DeepCopy() *GithubPushTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(GithubPushTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GithubSource) DeepCopyInto(out *GithubSource) {
 	*out = *in
@@ -765,6 +1020,11 @@ func (in *GithubSource) DeepCopyInto(out *GithubSource) {
 		*out = new(GitCloneOption)
 		**out = **in
 	}
+	if in.CheckoutOption != nil {
+		in, out := &in.CheckoutOption, &out.CheckoutOption
+		*out = new(GitCheckoutOption)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GithubSource.
@@ -777,6 +1037,36 @@ func (in *GithubSource) DeepCopy() *GithubSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitlabConnectionProperty) DeepCopyInto(out *GitlabConnectionProperty) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitlabConnectionProperty.
+func (in *GitlabConnectionProperty) DeepCopy() *GitlabConnectionProperty {
+	if in == nil {
+		return nil
+	}
+	out := new(GitlabConnectionProperty)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitlabPushTrigger) DeepCopyInto(out *GitlabPushTrigger) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitlabPushTrigger.
+func (in *GitlabPushTrigger) DeepCopy() *GitlabPushTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(GitlabPushTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitlabSource) DeepCopyInto(out *GitlabSource) {
 	*out = *in
@@ -790,6 +1080,11 @@ func (in *GitlabSource) DeepCopyInto(out *GitlabSource) {
 		*out = new(GitCloneOption)
 		**out = **in
 	}
+	if in.CheckoutOption != nil {
+		in, out := &in.CheckoutOption, &out.CheckoutOption
+		*out = new(GitCheckoutOption)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitlabSource.
@@ -817,6 +1112,26 @@ func (in *JWTToken) DeepCopy() *JWTToken {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockableResourcesProperty) DeepCopyInto(out *LockableResourcesProperty) {
+	*out = *in
+	if in.ResourceNames != nil {
+		in, out := &in.ResourceNames, &out.ResourceNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockableResourcesProperty.
+func (in *LockableResourcesProperty) DeepCopy() *LockableResourcesProperty {
+	if in == nil {
+		return nil
+	}
+	out := new(LockableResourcesProperty)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MultiBranchJobTrigger) DeepCopyInto(out *MultiBranchJobTrigger) {
 	*out = *in
@@ -875,11 +1190,36 @@ func (in *MultiBranchPipeline) DeepCopyInto(out *MultiBranchPipeline) {
 		*out = new(BitbucketServerSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BitbucketCloudSource != nil {
+		in, out := &in.BitbucketCloudSource, &out.BitbucketCloudSource
+		*out = new(BitbucketCloudSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GiteaSource != nil {
+		in, out := &in.GiteaSource, &out.GiteaSource
+		*out = new(GiteaSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.MultiBranchJobTrigger != nil {
 		in, out := &in.MultiBranchJobTrigger, &out.MultiBranchJobTrigger
 		*out = new(MultiBranchJobTrigger)
 		**out = **in
 	}
+	if in.BranchPropertyStrategy != nil {
+		in, out := &in.BranchPropertyStrategy, &out.BranchPropertyStrategy
+		*out = new(BranchPropertyStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BuildStrategies != nil {
+		in, out := &in.BuildStrategies, &out.BuildStrategies
+		*out = new(BranchBuildStrategies)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UnknownElements != nil {
+		in, out := &in.UnknownElements, &out.UnknownElements
+		*out = make([]UnknownElement, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiBranchPipeline.
@@ -892,6 +1232,22 @@ func (in *MultiBranchPipeline) DeepCopy() *MultiBranchPipeline {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamedBranchProperties) DeepCopyInto(out *NamedBranchProperties) {
+	*out = *in
+	out.Properties = in.Properties
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamedBranchProperties.
+func (in *NamedBranchProperties) DeepCopy() *NamedBranchProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(NamedBranchProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NoScmPipeline) DeepCopyInto(out *NoScmPipeline) {
 	*out = *in
@@ -903,7 +1259,34 @@ func (in *NoScmPipeline) DeepCopyInto(out *NoScmPipeline) {
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
 		*out = make([]ParameterDefinition, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QuietPeriod != nil {
+		in, out := &in.QuietPeriod, &out.QuietPeriod
+		*out = new(int)
+		**out = **in
+	}
+	if in.GithubProject != nil {
+		in, out := &in.GithubProject, &out.GithubProject
+		*out = new(GithubProjectProperty)
+		**out = **in
+	}
+	if in.GitlabConnection != nil {
+		in, out := &in.GitlabConnection, &out.GitlabConnection
+		*out = new(GitlabConnectionProperty)
+		**out = **in
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimitProperty)
+		**out = **in
+	}
+	if in.LockableResources != nil {
+		in, out := &in.LockableResources, &out.LockableResources
+		*out = new(LockableResourcesProperty)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.TimerTrigger != nil {
 		in, out := &in.TimerTrigger, &out.TimerTrigger
@@ -920,6 +1303,36 @@ func (in *NoScmPipeline) DeepCopyInto(out *NoScmPipeline) {
 		*out = new(GenericWebhook)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.UpstreamTrigger != nil {
+		in, out := &in.UpstreamTrigger, &out.UpstreamTrigger
+		*out = new(UpstreamTrigger)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScmTrigger != nil {
+		in, out := &in.ScmTrigger, &out.ScmTrigger
+		*out = new(ScmTrigger)
+		**out = **in
+	}
+	if in.GithubPushTrigger != nil {
+		in, out := &in.GithubPushTrigger, &out.GithubPushTrigger
+		*out = new(GithubPushTrigger)
+		**out = **in
+	}
+	if in.GitlabPushTrigger != nil {
+		in, out := &in.GitlabPushTrigger, &out.GitlabPushTrigger
+		*out = new(GitlabPushTrigger)
+		**out = **in
+	}
+	if in.ScmDefinition != nil {
+		in, out := &in.ScmDefinition, &out.ScmDefinition
+		*out = new(ScmFlowDefinition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UnknownElements != nil {
+		in, out := &in.UnknownElements, &out.UnknownElements
+		*out = make([]UnknownElement, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NoScmPipeline.
@@ -932,6 +1345,66 @@ func (in *NoScmPipeline) DeepCopy() *NoScmPipeline {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationFolder) DeepCopyInto(out *OrganizationFolder) {
+	*out = *in
+	if in.Traits != nil {
+		in, out := &in.Traits, &out.Traits
+		*out = new(OrganizationFolderTraits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TimerTrigger != nil {
+		in, out := &in.TimerTrigger, &out.TimerTrigger
+		*out = new(TimerTrigger)
+		**out = **in
+	}
+	if in.OrphanedItemPolicy != nil {
+		in, out := &in.OrphanedItemPolicy, &out.OrphanedItemPolicy
+		*out = new(DiscarderProperty)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationFolder.
+func (in *OrganizationFolder) DeepCopy() *OrganizationFolder {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationFolder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationFolderTraits) DeepCopyInto(out *OrganizationFolderTraits) {
+	*out = *in
+	if in.DiscoverPRFromForks != nil {
+		in, out := &in.DiscoverPRFromForks, &out.DiscoverPRFromForks
+		*out = new(DiscoverPRFromForks)
+		**out = **in
+	}
+	if in.CloneOption != nil {
+		in, out := &in.CloneOption, &out.CloneOption
+		*out = new(GitCloneOption)
+		**out = **in
+	}
+	if in.CheckoutOption != nil {
+		in, out := &in.CheckoutOption, &out.CheckoutOption
+		*out = new(GitCheckoutOption)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationFolderTraits.
+func (in *OrganizationFolderTraits) DeepCopy() *OrganizationFolderTraits {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationFolderTraits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OrphanedResourceKey) DeepCopyInto(out *OrphanedResourceKey) {
 	*out = *in
@@ -990,6 +1463,26 @@ func (in *Parameter) DeepCopy() *Parameter {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ParameterDefinition) DeepCopyInto(out *ParameterDefinition) {
 	*out = *in
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(StringParameterValidation)
+		**out = **in
+	}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(CredentialsParameterOption)
+		**out = **in
+	}
+	if in.Run != nil {
+		in, out := &in.Run, &out.Run
+		*out = new(RunParameterOption)
+		**out = **in
+	}
+	if in.ExtendedChoice != nil {
+		in, out := &in.ExtendedChoice, &out.ExtendedChoice
+		*out = new(ExtendedChoiceParameterOption)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterDefinition.
@@ -1237,6 +1730,11 @@ func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
 		*out = new(MultiBranchPipeline)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OrganizationFolder != nil {
+		in, out := &in.OrganizationFolder, &out.OrganizationFolder
+		*out = new(OrganizationFolder)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSpec.
@@ -1294,6 +1792,21 @@ func (in *ProjectRole) DeepCopy() *ProjectRole {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitProperty) DeepCopyInto(out *RateLimitProperty) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitProperty.
+func (in *RateLimitProperty) DeepCopy() *RateLimitProperty {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitProperty)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RemoteTrigger) DeepCopyInto(out *RemoteTrigger) {
 	*out = *in
@@ -1309,6 +1822,21 @@ func (in *RemoteTrigger) DeepCopy() *RemoteTrigger {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunParameterOption) DeepCopyInto(out *RunParameterOption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunParameterOption.
+func (in *RunParameterOption) DeepCopy() *RunParameterOption {
+	if in == nil {
+		return nil
+	}
+	out := new(RunParameterOption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SCM) DeepCopyInto(out *SCM) {
 	*out = *in
@@ -1324,6 +1852,46 @@ func (in *SCM) DeepCopy() *SCM {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScmFlowDefinition) DeepCopyInto(out *ScmFlowDefinition) {
+	*out = *in
+	if in.GitSource != nil {
+		in, out := &in.GitSource, &out.GitSource
+		*out = new(GitSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SvnSource != nil {
+		in, out := &in.SvnSource, &out.SvnSource
+		*out = new(SingleSvnSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScmFlowDefinition.
+func (in *ScmFlowDefinition) DeepCopy() *ScmFlowDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(ScmFlowDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScmTrigger) DeepCopyInto(out *ScmTrigger) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScmTrigger.
+func (in *ScmTrigger) DeepCopy() *ScmTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(ScmTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretInStep) DeepCopyInto(out *SecretInStep) {
 	*out = *in
@@ -1412,6 +1980,21 @@ func (in *StepTemplateStatus) DeepCopy() *StepTemplateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StringParameterValidation) DeepCopyInto(out *StringParameterValidation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringParameterValidation.
+func (in *StringParameterValidation) DeepCopy() *StringParameterValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(StringParameterValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SvnSource) DeepCopyInto(out *SvnSource) {
 	*out = *in
@@ -1482,6 +2065,21 @@ func (in SyncWindows) DeepCopy() SyncWindows {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TagBuildStrategy) DeepCopyInto(out *TagBuildStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TagBuildStrategy.
+func (in *TagBuildStrategy) DeepCopy() *TagBuildStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(TagBuildStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Template) DeepCopyInto(out *Template) {
 	*out = *in
@@ -1614,6 +2212,41 @@ func (in *TimerTrigger) DeepCopy() *TimerTrigger {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnknownElement) DeepCopyInto(out *UnknownElement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnknownElement.
+func (in *UnknownElement) DeepCopy() *UnknownElement {
+	if in == nil {
+		return nil
+	}
+	out := new(UnknownElement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamTrigger) DeepCopyInto(out *UpstreamTrigger) {
+	*out = *in
+	if in.Projects != nil {
+		in, out := &in.Projects, &out.Projects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamTrigger.
+func (in *UpstreamTrigger) DeepCopy() *UpstreamTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Webhook) DeepCopyInto(out *Webhook) {
 	*out = *in